@@ -0,0 +1,103 @@
+package cache
+
+import "fmt"
+
+// arenaChunkSize is the default size of a byte arena chunk (1 MiB).
+// Values larger than a chunk get a dedicated chunk of their own size.
+const arenaChunkSize = 1 << 20
+
+// arenaSpan locates an encoded value inside a valueArena.
+// It contains no pointers, so a map of spans is cheap for the garbage collector to scan.
+type arenaSpan struct {
+	chunk  uint32
+	offset uint32
+	length uint32
+}
+
+// valueArena stores encoded values in a small number of large byte slices.
+// Values are decoded on every access, trading read CPU for far fewer heap pointers.
+// An arena is built once per Set and never modified after it is published.
+type valueArena[V any] struct {
+	codec  Codec
+	chunks [][]byte
+	spans  map[string]arenaSpan // primary key -> encoded value location
+}
+
+// newValueArena creates an empty arena sized for roughly capacity values.
+func newValueArena[V any](codec Codec, capacity int) *valueArena[V] {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	return &valueArena[V]{
+		codec: codec,
+		spans: make(map[string]arenaSpan, capacity),
+	}
+}
+
+// put encodes v and stores it under pk, replacing any previous value for pk.
+// The bytes of a replaced value stay in the arena until the next rebuild.
+func (a *valueArena[V]) put(pk string, v V) error {
+	data, err := a.codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	a.putEncoded(pk, data)
+	return nil
+}
+
+// putEncoded stores already encoded bytes under pk.
+func (a *valueArena[V]) putEncoded(pk string, data []byte) {
+	last := len(a.chunks) - 1
+	if last < 0 || cap(a.chunks[last])-len(a.chunks[last]) < len(data) {
+		size := arenaChunkSize
+		if len(data) > size {
+			size = len(data)
+		}
+		a.chunks = append(a.chunks, make([]byte, 0, size))
+		last++
+	}
+
+	offset := len(a.chunks[last])
+	a.chunks[last] = append(a.chunks[last], data...)
+	a.spans[pk] = arenaSpan{chunk: uint32(last), offset: uint32(offset), length: uint32(len(data))}
+}
+
+// get decodes the value stored under pk.
+// A value that fails to decode is reported as missing.
+func (a *valueArena[V]) get(pk string) (V, bool) {
+	var v V
+	span, exists := a.spans[pk]
+	if !exists {
+		return v, false
+	}
+	data := a.chunks[span.chunk][span.offset : span.offset+span.length]
+	if err := a.codec.Unmarshal(data, &v); err != nil {
+		var zero V
+		return zero, false
+	}
+	return v, true
+}
+
+// size returns the number of bytes held by the arena chunks.
+func (a *valueArena[V]) size() int {
+	n := 0
+	for _, chunk := range a.chunks {
+		n += len(chunk)
+	}
+	return n
+}
+
+// compacted returns a copy of the arena holding only the values of pks, packed into fresh
+// chunks, so bytes of replaced or removed values are released. Values are copied without
+// being decoded.
+func (a *valueArena[V]) compacted(pks []string) *valueArena[V] {
+	out := newValueArena[V](a.codec, len(pks))
+	for _, pk := range pks {
+		span, exists := a.spans[pk]
+		if !exists {
+			continue
+		}
+		out.putEncoded(pk, a.chunks[span.chunk][span.offset:span.offset+span.length])
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestArenaStorage_BasicOperations(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	users := []TestUser{
+		{ID: "1", Email: "user1@example.com", Name: "User 1"},
+		{ID: "2", Email: "user2@example.com", Name: "User 2"},
+	}
+	cache.Set(users)
+
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 items, got %d", cache.Len())
+	}
+
+	user, ok := cache.Get("1")
+	if !ok || user.Name != "User 1" {
+		t.Errorf("Expected User 1, got %+v (found=%v)", user, ok)
+	}
+
+	user, ok = cache.GetByIndex("email", "user2@example.com")
+	if !ok || user.ID != "2" {
+		t.Errorf("Expected ID 2 by email, got %+v (found=%v)", user, ok)
+	}
+
+	all := cache.GetAll()
+	if len(all) != 2 || all[0].ID != "1" || all[1].ID != "2" {
+		t.Errorf("Expected insertion order [1 2], got %+v", all)
+	}
+
+	if cache.ArenaBytes() == 0 {
+		t.Error("Expected arena to hold encoded bytes")
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 || cache.ArenaBytes() != 0 {
+		t.Errorf("Expected empty arena after Clear, got len=%d bytes=%d", cache.Len(), cache.ArenaBytes())
+	}
+}
+
+func TestArenaStorage_HashMatchesMapStorage(t *testing.T) {
+	users := []TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}}
+
+	arenaCache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	arenaCache.Set(users)
+
+	mapCache := NewMultiIndexCache(userConfig())
+	mapCache.Set(users)
+
+	if arenaCache.GetHash() != mapCache.GetHash() {
+		t.Error("Expected arena and map storage to produce the same hash")
+	}
+}
+
+func TestArenaStorage_DuplicateKeysLastWins(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	cache.Set([]TestUser{{ID: "1", Name: "Old"}, {ID: "1", Name: "New"}})
+
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 item, got %d", cache.Len())
+	}
+	user, _ := cache.Get("1")
+	if user.Name != "New" {
+		t.Errorf("Expected New, got %s", user.Name)
+	}
+}
+
+func TestArenaStorage_AddIndexWithExistingData(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	if _, ok := cache.GetByIndex("email", "a@example.com"); !ok {
+		t.Error("Expected index rebuilt from arena data")
+	}
+}
+
+func TestArenaStorage_IterateAndStopEarly(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+
+	var seen []string
+	cache.Iterate(func(u TestUser) bool {
+		seen = append(seen, u.ID)
+		return len(seen) < 2
+	})
+	if strings.Join(seen, ",") != "1,2" {
+		t.Errorf("Expected to visit 1,2 then stop, got %v", seen)
+	}
+}
+
+// failingCodec fails to encode values whose Name is "bad".
+type failingCodec struct{ JSONCodec }
+
+func (c failingCodec) Marshal(v any) ([]byte, error) {
+	if u, ok := v.(TestUser); ok && u.Name == "bad" {
+		return nil, errors.New("cannot encode")
+	}
+	return c.JSONCodec.Marshal(v)
+}
+
+func TestArenaStorage_SkipsUnencodableValues(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithArenaStorage(failingCodec{})
+	cache := NewMultiIndexCache(config)
+
+	cache.Set([]TestUser{{ID: "1", Name: "good"}, {ID: "2", Name: "bad"}})
+	if cache.Len() != 1 {
+		t.Errorf("Expected unencodable value to be skipped, got %d items", cache.Len())
+	}
+	if _, ok := cache.Get("2"); ok {
+		t.Error("Expected value 2 to be absent")
+	}
+}
+
+func TestValueArena_LargeValueGetsOwnChunk(t *testing.T) {
+	arena := newValueArena[string](nil, 0)
+	large := strings.Repeat("x", arenaChunkSize+10)
+
+	if err := arena.put("small", "a"); err != nil {
+		t.Fatalf("put error: %v", err)
+	}
+	if err := arena.put("large", large); err != nil {
+		t.Fatalf("put error: %v", err)
+	}
+	if len(arena.chunks) != 2 {
+		t.Errorf("Expected 2 chunks, got %d", len(arena.chunks))
+	}
+
+	got, ok := arena.get("large")
+	if !ok || got != large {
+		t.Error("Expected large value to round-trip")
+	}
+	if _, ok := arena.get("missing"); ok {
+		t.Error("Expected missing key not found")
+	}
+}
+
+func BenchmarkArenaStorage_Get(b *testing.B) {
+	cache := NewMultiIndexCache(userConfig().WithArenaStorage(nil))
+	users := make([]TestUser, 10000)
+	for i := range users {
+		users[i] = TestUser{ID: fmt.Sprintf("%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	cache.Set(users)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Get("5000")
+	}
+}
@@ -0,0 +1,30 @@
+package cache
+
+// WithAtomicSet keeps the tiers consistent when a write-through Set fails: Redis is
+// written first and memory is only swapped to the new dataset once the write succeeded,
+// so a failed Set returns the error with both tiers still holding the previous dataset.
+// Sets are serialized so memory commits in the order Redis was written. Readers of this
+// instance see the new dataset only after the Redis round trip. It applies to the
+// WriteThrough policy; WithSyncRetry does not retry such Sets, as memory never took
+// them.
+func (c *HybridCache[V]) WithAtomicSet() *HybridCache[V] {
+	c.atomicSet = true
+	return c
+}
+
+// setAtomic implements Set under WithAtomicSet.
+func (c *HybridCache[V]) setAtomic(values []V) error {
+	c.atomicMu.Lock()
+	defer c.atomicMu.Unlock()
+
+	changed, err := c.writeDataset(values)
+	if err != nil {
+		return err
+	}
+	c.memory.Set(values)
+	if !changed {
+		return nil
+	}
+	// After the commit, so persisted indexes are built from the new memory state.
+	return c.afterRedisWrite(values)
+}
@@ -0,0 +1,30 @@
+package cache
+
+import "testing"
+
+func TestHybridCache_AtomicSet(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithAtomicSet().
+		WithPersistedIndexes()
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	if err := cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if pk, ok, _ := cache.Redis().LookupIndex("email", "a@example.com"); !ok || pk != "1" {
+		t.Errorf("Expected the persisted index of the committed dataset, got %q %v", pk, ok)
+	}
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "2"}}); err == nil {
+		t.Fatal("Expected the Redis error")
+	}
+	mr.SetError("")
+	if got := idsOf(cache.GetAll()); got != "1" {
+		t.Errorf("Expected memory to keep the previous dataset, got %s", got)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "1" {
+		t.Errorf("Expected Redis to keep the previous dataset, got %s", idsOf(remote))
+	}
+}
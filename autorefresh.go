@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// backgroundLoop is a goroutine run by a HybridCache, such as the StartAutoRefresh loop.
+type backgroundLoop struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startLoop runs fn in a new goroutine with a context canceled by stop.
+func startLoop(fn func(ctx context.Context)) *backgroundLoop {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &backgroundLoop{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(l.done)
+		fn(ctx)
+	}()
+	return l
+}
+
+// stop cancels the loop and waits for it to exit; a nil loop is a no-op.
+func (l *backgroundLoop) stop() {
+	if l == nil {
+		return
+	}
+	l.cancel()
+	<-l.done
+}
+
+// StartAutoRefresh starts a background goroutine that checks the Redis version every
+// interval and reloads memory with LoadFromRedis when it changed, with the first load right
+// away. A failed check or load is retried at the next interval. Calling it again replaces
+// the running loop; Stop (or Close) ends it.
+func (c *HybridCache[V]) StartAutoRefresh(interval time.Duration) {
+	c.replaceRefreshLoop(startLoop(func(ctx context.Context) { c.runAutoRefresh(ctx, interval) }))
+}
+
+// replaceRefreshLoop makes loop the refresh loop ended by Stop, stopping the previous one.
+func (c *HybridCache[V]) replaceRefreshLoop(loop *backgroundLoop) {
+	c.loopsMu.Lock()
+	previous := c.refresh
+	c.refresh = loop
+	c.loopsMu.Unlock()
+	previous.stop()
+}
+
+// runAutoRefresh is the StartAutoRefresh loop.
+func (c *HybridCache[V]) runAutoRefresh(ctx context.Context, interval time.Duration) {
+	loaded, ok := int64(0), false
+	for {
+		version, err := c.remote.GetVersionCtx(ctx)
+		switch {
+		case err != nil:
+			c.reportError(TaskAutoRefresh, err)
+		case !ok || version != loaded:
+			// Stop cancels ctx, which interrupts a load in flight.
+			_, err = c.loadFromRedis(ctx, true)
+			if ctx.Err() != nil {
+				return
+			}
+			c.reportError(TaskAutoRefresh, err)
+			if err == nil {
+				loaded, ok = version, true
+			}
+		}
+		if sleepCtx(ctx, interval) != nil {
+			return
+		}
+	}
+}
+
+// Stop ends the StartAutoRefresh (or StartLeaderRefresh) loop and waits for it to exit.
+// Safe to call when no loop is running.
+func (c *HybridCache[V]) Stop() {
+	c.loopsMu.Lock()
+	loop := c.refresh
+	c.refresh = nil
+	c.loopsMu.Unlock()
+	loop.stop()
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_StartAutoRefresh(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	cache.StartAutoRefresh(5 * time.Millisecond)
+	defer cache.Stop()
+	waitFor(t, func() bool { return idsOf(cache.GetAll()) == "1" })
+
+	if err := writer.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool { return idsOf(cache.GetAll()) == "1,2" })
+
+	cache.Stop()
+	cache.Stop()
+	if err := writer.Set([]TestUser{{ID: "3"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := idsOf(cache.GetAll()); got != "1,2" {
+		t.Errorf("Expected no reload after Stop, got %s", got)
+	}
+}
+
+func TestHybridCache_StartAutoRefreshReplaces(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+
+	cache.StartAutoRefresh(time.Hour)
+	first := cache.refresh
+	cache.StartAutoRefresh(time.Hour)
+	select {
+	case <-first.done:
+	default:
+		t.Error("Expected a second StartAutoRefresh to stop the first loop")
+	}
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if cache.refresh != nil {
+		t.Error("Expected Close to stop the loop")
+	}
+}
+
+func TestHybridCache_StopCancelsLoad(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	started := make(chan struct{})
+	// A missing dataset makes the load fall back to the loader, which blocks until canceled.
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithLoader(func(ctx context.Context) ([]TestUser, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	cache.StartAutoRefresh(time.Hour)
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		cache.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Stop to cancel the load in flight")
+	}
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BatchWriter collects Set writes for several RedisCache instances, of any value type, and
+// flushes them together: the commands of all caches sharing a client go out in one pipeline,
+// so refreshing many datasets costs one round trip instead of one per cache. Queue writes
+// with RedisCache.QueueSet. The zero value is ready to use and safe for concurrent use.
+//
+// A batch is not atomic per cache: the data and version keys of a blob-mode cache are
+// written in the same pipeline but not in a transaction (as without VersionedEnvelope).
+// Caches with RedisConfig.AtomicWrites, and caches in another storage mode, keep their own
+// atomic write and are written one after another after the pipeline.
+type BatchWriter struct {
+	mu     sync.Mutex
+	writes []batchWrite
+}
+
+// batchWrite is one queued Set.
+type batchWrite struct {
+	key     string
+	client  redis.UniversalClient
+	timeout time.Duration
+	size    int
+	// queue adds the commands of the write to pipe; nil if the cache is written on its own.
+	queue func(ctx context.Context, pipe redis.Pipeliner) error
+	// write performs the write on its own, for caches that cannot be pipelined.
+	write func(ctx context.Context) error
+	// done reports the outcome to the cache's MetricsHook.
+	done func(start time.Time, err error)
+}
+
+// Len returns the number of queued writes.
+func (b *BatchWriter) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.writes)
+}
+
+// QueueSet queues storing values in c like Set, to be written by b.Flush. Values are encoded
+// right away, so a marshal error is returned here and nothing is queued.
+func (c *RedisCache[V]) QueueSet(b *BatchWriter, values []V) error {
+	if err := c.admit(); err != nil {
+		return err
+	}
+
+	ttl := c.effectiveTTL(c.setTTL(values))
+	w := batchWrite{key: c.key, client: c.client, timeout: c.config.OperationTimeout}
+	if c.storage == storageBlob && !c.config.AtomicWrites {
+		data, err := c.encode(values)
+		if err != nil {
+			return fmt.Errorf("failed to marshal values: %w", err)
+		}
+		w.size = len(data)
+		w.queue = func(ctx context.Context, pipe redis.Pipeliner) error {
+			c.queueBlob(ctx, pipe, data, ttl, true)
+			return c.queueDerived(ctx, pipe, values, ttl)
+		}
+		w.done = func(start time.Time, err error) { c.observe(OpSet, start, w.size, err) }
+	} else {
+		w.write = func(ctx context.Context) error { return c.write(ctx, values, ttl, true) }
+	}
+
+	b.mu.Lock()
+	b.writes = append(b.writes, w)
+	b.mu.Unlock()
+	return nil
+}
+
+// Flush writes all queued writes and empties the batch, whatever the outcome. Without a
+// deadline on ctx, each pipeline is bounded by the timeout of WithCallTimeout or else the
+// longest OperationTimeout of its caches.
+// Returns the errors of the failed writes joined, each naming the cache key.
+func (b *BatchWriter) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	writes := b.writes
+	b.writes = nil
+	b.mu.Unlock()
+
+	var errs []error
+	var clients []redis.UniversalClient
+	groups := make(map[redis.UniversalClient][]batchWrite)
+	for _, w := range writes {
+		if w.queue == nil {
+			continue
+		}
+		if _, seen := groups[w.client]; !seen {
+			clients = append(clients, w.client)
+		}
+		groups[w.client] = append(groups[w.client], w)
+	}
+	for _, client := range clients {
+		errs = append(errs, flushPipeline(ctx, client, groups[client])...)
+	}
+	for _, w := range writes {
+		if w.queue != nil {
+			continue
+		}
+		if err := w.write(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set cache %q: %w", w.key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flushPipeline sends the writes of one client in a single pipeline and reports each
+// write's outcome from its own commands.
+func flushPipeline(ctx context.Context, client redis.UniversalClient, writes []batchWrite) []error {
+	start := time.Now()
+	if _, ok := ctx.Deadline(); !ok {
+		var timeout time.Duration
+		for _, w := range writes {
+			timeout = max(timeout, w.timeout)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, operationTimeout(ctx, timeout))
+		defer cancel()
+	}
+
+	var errs []error
+	pipe := client.Pipeline()
+	bounds := make([]int, len(writes)+1)
+	queued := make([]bool, len(writes))
+	for i, w := range writes {
+		if err := w.queue(ctx, pipe); err != nil {
+			w.done(start, err)
+			errs = append(errs, fmt.Errorf("failed to set cache %q: %w", w.key, err))
+		} else {
+			queued[i] = true
+		}
+		bounds[i+1] = pipe.Len()
+	}
+
+	cmds, execErr := pipe.Exec(ctx)
+	for i, w := range writes {
+		if !queued[i] {
+			continue
+		}
+		err := execErr
+		if len(cmds) == bounds[len(writes)] {
+			err = nil
+			for _, cmd := range cmds[bounds[i]:bounds[i+1]] {
+				if cmdErr := cmd.Err(); cmdErr != nil {
+					err = cmdErr
+					break
+				}
+			}
+		}
+		err = markTimeout(err)
+		w.done(start, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to set cache %q: %w", w.key, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// pipelineCounter is a go-redis hook counting pipelines sent to the server.
+type pipelineCounter struct{ n atomic.Int32 }
+
+func (h *pipelineCounter) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *pipelineCounter) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h *pipelineCounter) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.n.Add(1)
+		return next(ctx, cmds)
+	}
+}
+
+func TestBatchWriter_Flush(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	counter := &pipelineCounter{}
+	client.AddHook(counter)
+
+	hook := &recordingHook{}
+	users := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithKeyPrefix("users:").WithPublishMetadata(true).WithMetricsHook(hook))
+	names := NewRedisCache[string](client, DefaultRedisConfig().
+		WithKeyPrefix("names:").WithVersionedEnvelope(true))
+
+	var batch BatchWriter
+	if err := users.QueueSet(&batch, []TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("QueueSet error: %v", err)
+	}
+	if err := names.QueueSet(&batch, []string{"a", "b", "c"}); err != nil {
+		t.Fatalf("QueueSet error: %v", err)
+	}
+	if batch.Len() != 2 {
+		t.Errorf("Expected 2 queued writes, got %d", batch.Len())
+	}
+	client.Ping(context.Background()) // connection setup is pipelined too
+	counter.n.Store(0)
+	if err := batch.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if n := counter.n.Load(); n != 1 {
+		t.Errorf("Expected one pipeline, got %d", n)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("Expected an empty batch after Flush, got %d", batch.Len())
+	}
+
+	values, version, err := users.GetWithVersion()
+	if err != nil || idsOf(values) != "1,2" || version != 1 {
+		t.Errorf("Expected users 1,2 at version 1, got %s at %d (%v)", idsOf(values), version, err)
+	}
+	if meta, _ := users.Metadata(); meta.Items != 2 {
+		t.Errorf("Expected metadata with 2 items, got %d", meta.Items)
+	}
+	if got, err := names.Get(); err != nil || strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("Expected names a,b,c, got %v (%v)", got, err)
+	}
+	if op := hook.ops[0]; op.op != OpSet || op.err != nil || op.bytes == 0 {
+		t.Errorf("Expected a successful OpSet with a size, got %+v", op)
+	}
+}
+
+func TestBatchWriter_StorageModes(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	items := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("items:")).
+		WithHashStorage(func(u TestUser) string { return u.ID })
+	blob := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("blob:"))
+
+	var batch BatchWriter
+	_ = items.QueueSet(&batch, []TestUser{{ID: "1"}})
+	_ = blob.QueueSet(&batch, []TestUser{{ID: "2"}})
+	if err := batch.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if v, ok, err := items.GetItem("1"); err != nil || !ok || v.ID != "1" {
+		t.Errorf("Expected item 1, got %+v %v (%v)", v, ok, err)
+	}
+	if values, _ := blob.Get(); idsOf(values) != "2" {
+		t.Errorf("Expected blob 2, got %s", idsOf(values))
+	}
+}
+
+func TestBatchWriter_Errors(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	var batch BatchWriter
+	if err := NewRedisCache[TestUser](nil, DefaultRedisConfig()).QueueSet(&batch, nil); err == nil {
+		t.Error("Expected an error for a nil client")
+	}
+	_ = cache.QueueSet(&batch, []TestUser{{ID: "1"}})
+	mr.SetError("LOADING")
+	err := batch.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), cache.key) {
+		t.Errorf("Expected an error naming %q, got %v", cache.key, err)
+	}
+}
@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Key suffixes of blue/green storage mode. The data key itself is the pointer to the active
+// generation; each generation is stored under the data key + generationKeyInfix + its id.
+const (
+	generationKeyInfix       = ":g:"
+	previousKeySuffix        = ":prev" // pointer to the generation Rollback returns to
+	generationCounterSuffix  = ":gen"  // allocates generation ids; has no TTL so ids never repeat
+	errGenerationMissingCode = -2
+)
+
+// ErrNoPreviousGeneration is returned by Rollback when there is no previous generation to
+// return to, or it expired.
+var ErrNoPreviousGeneration = errors.New("cache-kit: no previous generation to roll back to")
+
+// ErrNotBlueGreen is returned by Rollback on a cache not in blue/green storage mode.
+var ErrNotBlueGreen = errors.New("cache-kit: rollback requires blue/green storage (see RedisCache.WithBlueGreen)")
+
+// flipGenerationScript makes a written generation the active one: the active one becomes the
+// previous one (extended to the new TTL) and the generation before it is deleted. With an
+// expected version, the flip only happens if the version still matches; otherwise the new
+// generation is deleted. KEYS: pointer, previous pointer, version, new generation.
+// ARGV: generation id, ttl in milliseconds, expected version ("" for none), bump flag.
+// Returns the new version, -1 on mismatch or -2 if the new generation was not written.
+var flipGenerationScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[4]) == 0 then
+  return -2
+end
+if ARGV[3] ~= '' then
+  local current = tonumber(redis.call('GET', KEYS[3]) or '0')
+  if current ~= tonumber(ARGV[3]) then
+    redis.call('DEL', KEYS[4])
+    return -1
+  end
+end
+local cur = redis.call('GET', KEYS[1])
+local old = redis.call('GET', KEYS[2])
+if old and old ~= cur and old ~= ARGV[1] then
+  redis.call('DEL', KEYS[1] .. ':g:' .. old)
+end
+if cur then
+  redis.call('SET', KEYS[2], cur, 'PX', ARGV[2])
+  redis.call('PEXPIRE', KEYS[1] .. ':g:' .. cur, ARGV[2])
+else
+  redis.call('DEL', KEYS[2])
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+local v
+if ARGV[4] == '0' then
+  v = tonumber(redis.call('GET', KEYS[3]) or '0')
+else
+  v = redis.call('INCR', KEYS[3])
+end
+redis.call('PEXPIRE', KEYS[3], ARGV[2])
+return v
+`)
+
+// readGenerationScript reads the active generation and the version in one round trip.
+// KEYS: pointer, version. Returns {payload, version}, either possibly nil.
+var readGenerationScript = redis.NewScript(`
+local id = redis.call('GET', KEYS[1])
+local data = false
+if id then
+  data = redis.call('GET', KEYS[1] .. ':g:' .. id)
+end
+return {data, redis.call('GET', KEYS[2])}
+`)
+
+// rollbackGenerationScript swaps the active and previous generations and bumps the version.
+// KEYS: pointer, previous pointer, version. ARGV: ttl in milliseconds.
+// Returns the new version, or -1 if there is no previous generation.
+var rollbackGenerationScript = redis.NewScript(`
+local prev = redis.call('GET', KEYS[2])
+if not prev or redis.call('EXISTS', KEYS[1] .. ':g:' .. prev) == 0 then
+  return -1
+end
+local cur = redis.call('GET', KEYS[1])
+redis.call('SET', KEYS[1], prev, 'PX', ARGV[1])
+redis.call('PEXPIRE', KEYS[1] .. ':g:' .. prev, ARGV[1])
+if cur then
+  redis.call('SET', KEYS[2], cur, 'PX', ARGV[1])
+else
+  redis.call('DEL', KEYS[2])
+end
+local v = redis.call('INCR', KEYS[3])
+redis.call('PEXPIRE', KEYS[3], ARGV[1])
+return v
+`)
+
+// expireGenerationsScript resets the TTL of the generations the pointers refer to.
+// KEYS: pointer, previous pointer. ARGV: ttl in milliseconds.
+var expireGenerationsScript = redis.NewScript(`
+for _, key in ipairs(KEYS) do
+  local id = redis.call('GET', key)
+  if id then
+    redis.call('PEXPIRE', key, ARGV[1])
+    redis.call('PEXPIRE', KEYS[1] .. ':g:' .. id, ARGV[1])
+  end
+end
+return 1
+`)
+
+// clearGenerationsScript deletes both generations, the previous pointer and the id counter.
+// KEYS: pointer, previous pointer, counter.
+var clearGenerationsScript = redis.NewScript(`
+for i = 1, 2 do
+  local id = redis.call('GET', KEYS[i])
+  if id then
+    redis.call('DEL', KEYS[1] .. ':g:' .. id)
+  end
+end
+redis.call('DEL', KEYS[2], KEYS[3])
+return 1
+`)
+
+// WithBlueGreen switches the cache to blue/green storage mode: each full write stores the
+// dataset as a single payload under a new generation key, then atomically flips the data
+// key, which holds the id of the active generation, to it. Readers resolve the pointer and
+// read the generation in one script, so they see either the old or the new dataset, and
+// the large payload is transferred without touching the active one. The generation before
+// is kept (with the same TTL) so Rollback can return to it instantly.
+//
+// Generation keys are resolved inside Lua scripts, so on a cluster RedisConfig.ClusterHashTag
+// is required. VersionedEnvelope is not used in this mode and Export is not supported.
+// Replaces other storage modes. Must be called before the cache is used; all readers and
+// writers of a key must agree on the mode.
+func (c *RedisCache[V]) WithBlueGreen() *RedisCache[V] {
+	c.storage = storageBlueGreen
+	c.itemKey = nil
+	return c
+}
+
+// previousKey returns the key pointing to the previous generation.
+func (c *RedisCache[V]) previousKey() string {
+	return c.key + previousKeySuffix
+}
+
+// generationKey returns the key of the generation with the given id.
+func (c *RedisCache[V]) generationKey(id int64) string {
+	return c.key + generationKeyInfix + strconv.FormatInt(id, 10)
+}
+
+// writeGeneration stores values as a new generation and flips the pointer to it. With
+// checkVersion, the flip only happens if the version equals expected. Returns the new
+// version (-1 on mismatch) and the payload size.
+func (c *RedisCache[V]) writeGeneration(ctx context.Context, values []V, ttl time.Duration, bump bool, checkVersion bool, expected int64) (int64, int, error) {
+	data, err := c.encode(values)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	id, err := c.client.Incr(ctx, c.key+generationCounterSuffix).Result()
+	if err != nil {
+		return 0, len(data), fmt.Errorf("failed to allocate generation: %w", err)
+	}
+	expectedArg := ""
+	if checkVersion {
+		expectedArg = strconv.FormatInt(expected, 10)
+	}
+	generation := c.generationKey(id)
+	pipe := c.client.Pipeline()
+	pipe.Set(ctx, generation, data, ttl)
+	flip := flipGenerationScript.Eval(ctx, pipe,
+		[]string{c.key, c.previousKey(), c.versionKey(), generation},
+		id, ttl.Milliseconds(), expectedArg, bumpFlag(bump))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, len(data), fmt.Errorf("failed to set cache: %w", err)
+	}
+	version := flip.Val().(int64)
+	if version == errGenerationMissingCode {
+		return 0, len(data), fmt.Errorf("failed to set cache: generation %d was not written", id)
+	}
+	return version, len(data), nil
+}
+
+// fetchGeneration reads the active generation and the version in one round trip.
+// The dataset counts as found if the generation or the version key exists.
+func (c *RedisCache[V]) fetchGeneration(ctx context.Context) (values []V, version int64, found bool, size int, err error) {
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	reply, err := readGenerationScript.Run(ctx, c.client, []string{c.key, c.versionKey()}).Slice()
+	if err != nil {
+		return nil, 0, false, 0, fmt.Errorf("failed to get cache: %w", err)
+	}
+	if len(reply) != 2 {
+		return nil, 0, false, 0, fmt.Errorf("failed to get cache: unexpected reply %v", reply)
+	}
+	if v, ok := reply[1].(string); ok {
+		if version, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, 0, false, 0, fmt.Errorf("failed to get version: %w", err)
+		}
+	}
+
+	data, ok := reply[0].(string)
+	if !ok {
+		return []V{}, version, version != 0, 0, nil
+	}
+	values, err = c.decode([]byte(data))
+	if err != nil {
+		return nil, version, true, len(data), err
+	}
+	return values, version, true, len(data), nil
+}
+
+// Rollback makes the previous generation active again in blue/green storage mode and bumps
+// the version, without transferring the dataset. The generation rolled away from becomes the
+// previous one, so a second Rollback rolls forward. Returns ErrNoPreviousGeneration if there
+// is none; ErrNotBlueGreen outside blue/green storage mode. The content hash is dropped;
+// metadata and the stale copy still describe the last write.
+func (c *RedisCache[V]) Rollback() error {
+	return c.RollbackCtx(context.Background())
+}
+
+// RollbackCtx is like Rollback but uses ctx for the Redis calls.
+func (c *RedisCache[V]) RollbackCtx(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpRollback, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+	if c.storage != storageBlueGreen {
+		return ErrNotBlueGreen
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl := c.effectiveTTL(c.config.TTL)
+	version, err := rollbackGenerationScript.Run(ctx, c.client,
+		[]string{c.key, c.previousKey(), c.versionKey()}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to roll back: %w", err)
+	}
+	if version < 0 {
+		return ErrNoPreviousGeneration
+	}
+	if c.config.ContentHash {
+		if err := c.client.Del(ctx, c.contentHashKey()).Err(); err != nil {
+			return fmt.Errorf("failed to drop content hash: %w", err)
+		}
+	}
+	return nil
+}
+
+// queueExpireGenerations queues resetting the TTL of both generations on pipe.
+func (c *RedisCache[V]) queueExpireGenerations(ctx context.Context, pipe redis.Pipeliner, ttl time.Duration) {
+	expireGenerationsScript.Eval(ctx, pipe, []string{c.key, c.previousKey()}, ttl.Milliseconds())
+}
+
+// clearGenerations deletes the generations and the keys tracking them.
+func (c *RedisCache[V]) clearGenerations(ctx context.Context) error {
+	return clearGenerationsScript.Run(ctx, c.client,
+		[]string{c.key, c.previousKey(), c.key + generationCounterSuffix}).Err()
+}
+
+// generationSizeScript returns the length of the active generation, 0 if there is none.
+// KEYS: pointer.
+var generationSizeScript = redis.NewScript(`
+local id = redis.call('GET', KEYS[1])
+if not id then
+  return 0
+end
+return redis.call('STRLEN', KEYS[1] .. ':g:' .. id)
+`)
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRedisCache_BlueGreen(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig()).WithBlueGreen()
+
+	if err := cache.Rollback(); !errors.Is(err, ErrNoPreviousGeneration) {
+		t.Errorf("Expected ErrNoPreviousGeneration on an empty cache, got %v", err)
+	}
+	for _, values := range [][]TestUser{{{ID: "1"}}, {{ID: "1"}, {ID: "2"}}, {{ID: "3"}}} {
+		if err := cache.Set(values); err != nil {
+			t.Fatalf("Set error: %v", err)
+		}
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil || idsOf(values) != "3" || version != 3 {
+		t.Fatalf("Expected 3 at version 3, got %s at %d (%v)", idsOf(values), version, err)
+	}
+	// Only the active and the previous generation are kept.
+	if mr.Exists(cache.generationKey(1)) || !mr.Exists(cache.generationKey(2)) || !mr.Exists(cache.generationKey(3)) {
+		t.Errorf("Expected generations 2 and 3 to be kept, got keys %v", mr.Keys())
+	}
+
+	if err := cache.Rollback(); err != nil {
+		t.Fatalf("Rollback error: %v", err)
+	}
+	values, version, _ = cache.GetWithVersion()
+	if idsOf(values) != "1,2" || version != 4 {
+		t.Errorf("Expected 1,2 at version 4 after rollback, got %s at %d", idsOf(values), version)
+	}
+	if err := cache.Rollback(); err != nil {
+		t.Fatalf("Rollback error: %v", err)
+	}
+	if values, _ := cache.Get(); idsOf(values) != "3" {
+		t.Errorf("Expected a second rollback to roll forward to 3, got %s", idsOf(values))
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("Expected Clear to remove all keys, got %v", keys)
+	}
+}
+
+func TestRedisCache_BlueGreenSetIfVersion(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig()).WithBlueGreen()
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	ok, err := cache.SetIfVersion([]TestUser{{ID: "2"}}, 0)
+	if err != nil || ok {
+		t.Fatalf("Expected a stale version to be rejected, got %v (%v)", ok, err)
+	}
+	if mr.Exists(cache.generationKey(2)) {
+		t.Error("Expected the rejected generation to be deleted")
+	}
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "3"}}, 1); err != nil || !ok {
+		t.Fatalf("Expected the current version to be accepted, got %v (%v)", ok, err)
+	}
+	if values, _ := cache.Get(); idsOf(values) != "3" {
+		t.Errorf("Expected 3, got %s", idsOf(values))
+	}
+}
+
+func TestRedisCache_RollbackRequiresBlueGreen(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := cache.Rollback(); !errors.Is(err, ErrNotBlueGreen) {
+		t.Errorf("Expected ErrNotBlueGreen, got %v", err)
+	}
+}
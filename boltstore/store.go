@@ -0,0 +1,162 @@
+// Package boltstore provides a cache.RemoteStore kept in a bbolt database, for
+// single-node deployments that want a durable shared tier and the HybridCache API without
+// running a network service:
+//
+//	db, err := bbolt.Open("cache.db", 0o600, nil)
+//	store := boltstore.New[User](db, "users")
+//	users := cache.NewHybridCacheWithStore(memConfig, store)
+//
+// Each dataset is a bucket holding the encoded payload and its version, both written in
+// one transaction. Several datasets may share the database under different names.
+package boltstore
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+	"go.etcd.io/bbolt"
+)
+
+// Keys of a dataset bucket.
+var (
+	dataKey    = []byte("data")
+	versionKey = []byte("version")
+)
+
+// Store is a cache.RemoteStore backed by one bucket of a bbolt database. It is safe for
+// concurrent use.
+type Store[V any] struct {
+	db     *bbolt.DB
+	bucket []byte
+	codec  cache.Codec
+}
+
+var _ cache.RemoteStore[struct{}] = (*Store[struct{}])(nil)
+
+// New creates a Store for the dataset name in db; its bucket is created on the first write.
+func New[V any](db *bbolt.DB, name string) *Store[V] {
+	return &Store[V]{db: db, bucket: []byte(name), codec: cache.JSONCodec{}}
+}
+
+// WithCodec sets how the dataset is encoded (cache.JSONCodec by default).
+func (s *Store[V]) WithCodec(codec cache.Codec) *Store[V] {
+	s.codec = codec
+	return s
+}
+
+// GetCtx implements cache.RemoteStore.
+func (s *Store[V]) GetCtx(ctx context.Context) ([]V, error) {
+	var data []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if b := tx.Bucket(s.bucket); b != nil {
+			data = append([]byte(nil), b.Get(dataKey)...) // valid only during the transaction
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	values := []V{}
+	if len(data) == 0 {
+		return values, nil
+	}
+	if err := s.codec.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal values: %w", cache.ErrDecode, err)
+	}
+	if values == nil {
+		values = []V{}
+	}
+	return values, nil
+}
+
+// SetCtx implements cache.RemoteStore. SetOptions fail with cache.ErrUnsupportedSetOption.
+func (s *Store[V]) SetCtx(ctx context.Context, values []V, opts ...cache.SetOption) error {
+	if !cache.ApplySetOptions(opts...).IsZero() {
+		return fmt.Errorf("%w: boltstore supports no set options", cache.ErrUnsupportedSetOption)
+	}
+	if values == nil {
+		values = []V{}
+	}
+	data, err := s.codec.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(s.bucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(dataKey, data); err != nil {
+			return err
+		}
+		return b.Put(versionKey, binary.BigEndian.AppendUint64(nil, uint64(version(b)+1)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set dataset: %w", err)
+	}
+	return nil
+}
+
+// ExistsCtx implements cache.RemoteStore.
+func (s *Store[V]) ExistsCtx(ctx context.Context) (bool, error) {
+	var exists bool
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		exists = b != nil && b.Get(dataKey) != nil
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check dataset: %w", err)
+	}
+	return exists, nil
+}
+
+// GetVersionCtx implements cache.RemoteStore.
+func (s *Store[V]) GetVersionCtx(ctx context.Context) (int64, error) {
+	var v int64
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if b := tx.Bucket(s.bucket); b != nil {
+			v = version(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get version: %w", err)
+	}
+	return v, nil
+}
+
+// ClearCtx implements cache.RemoteStore.
+func (s *Store[V]) ClearCtx(ctx context.Context) error {
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket(s.bucket) == nil {
+			return nil
+		}
+		return tx.DeleteBucket(s.bucket)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear dataset: %w", err)
+	}
+	return nil
+}
+
+// TTLCtx implements cache.RemoteStore. Datasets do not expire.
+func (s *Store[V]) TTLCtx(ctx context.Context) (time.Duration, error) {
+	ok, err := s.ExistsCtx(ctx)
+	if err != nil || !ok {
+		return -2, err
+	}
+	return -1, nil
+}
+
+// version reads the version stored in b, 0 if none.
+func version(b *bbolt.Bucket) int64 {
+	data := b.Get(versionKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(data))
+}
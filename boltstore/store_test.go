@@ -0,0 +1,125 @@
+package boltstore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+	"go.etcd.io/bbolt"
+)
+
+type user struct {
+	ID   string
+	Name string
+}
+
+func openDB(t *testing.T) *bbolt.DB {
+	t.Helper()
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "cache.db"), 0o600, nil)
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	db := openDB(t)
+	store := New[user](db, "users")
+	ctx := context.Background()
+
+	if ok, err := store.ExistsCtx(ctx); err != nil || ok {
+		t.Errorf("Expected no dataset, got %v, %v", ok, err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != -2 {
+		t.Errorf("Expected TTL -2 for a missing dataset, got %v", ttl)
+	}
+	if values, err := store.GetCtx(ctx); err != nil || len(values) != 0 {
+		t.Errorf("Expected an empty dataset, got %v, %v", values, err)
+	}
+
+	if err := store.SetCtx(ctx, nil); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if ok, _ := store.ExistsCtx(ctx); !ok {
+		t.Error("Expected an empty dataset to exist")
+	}
+	if err := store.SetCtx(ctx, []user{{ID: "1", Name: "a"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if version, _ := store.GetVersionCtx(ctx); version != 2 {
+		t.Errorf("Expected version 2, got %d", version)
+	}
+	if values, err := store.GetCtx(ctx); err != nil || len(values) != 1 || values[0].Name != "a" {
+		t.Errorf("Expected the stored dataset, got %v, %v", values, err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != -1 {
+		t.Errorf("Expected TTL -1, got %v", ttl)
+	}
+
+	// Datasets sharing the database are separate.
+	if ok, _ := New[user](db, "admins").ExistsCtx(ctx); ok {
+		t.Error("Expected another name to have no dataset")
+	}
+
+	if err := store.ClearCtx(ctx); err != nil {
+		t.Fatalf("ClearCtx error: %v", err)
+	}
+	if err := store.ClearCtx(ctx); err != nil {
+		t.Fatalf("Expected clearing a missing dataset to succeed, got %v", err)
+	}
+	if version, _ := store.GetVersionCtx(ctx); version != 0 {
+		t.Errorf("Expected version 0 after ClearCtx, got %d", version)
+	}
+}
+
+func TestStore_SetOptions(t *testing.T) {
+	store := New[user](openDB(t), "users")
+	err := store.SetCtx(context.Background(), []user{{ID: "1"}}, cache.WithTTL(time.Minute))
+	if !errors.Is(err, cache.ErrUnsupportedSetOption) {
+		t.Errorf("Expected ErrUnsupportedSetOption, got %v", err)
+	}
+	if ok, _ := store.ExistsCtx(context.Background()); ok {
+		t.Error("Expected a rejected write to store nothing")
+	}
+}
+
+func TestStore_DecodeError(t *testing.T) {
+	db := openDB(t)
+	err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucket([]byte("users"))
+		if err != nil {
+			return err
+		}
+		return b.Put(dataKey, []byte("{broken"))
+	})
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if _, err := New[user](db, "users").GetCtx(context.Background()); !errors.Is(err, cache.ErrDecode) {
+		t.Errorf("Expected ErrDecode, got %v", err)
+	}
+}
+
+func TestStore_HybridCache(t *testing.T) {
+	store := New[user](openDB(t), "users")
+	config := cache.DefaultConfig[user]().WithPrimaryKey(func(u user) string { return u.ID })
+	writer := cache.NewHybridCacheWithStore(config, store)
+	reader := cache.NewHybridCacheWithStore(config, store)
+
+	if err := writer.Set([]user{{ID: "1", Name: "a"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := writer.Delete("1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if err := reader.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if len(reader.GetAll()) != 0 || reader.LoadedVersion() != 2 {
+		t.Errorf("Expected the reader to load the empty version 2, got %v at %d", reader.GetAll(), reader.LoadedVersion())
+	}
+}
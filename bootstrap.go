@@ -0,0 +1,28 @@
+package cache
+
+import "context"
+
+// MarkLoaded records that memory holds the Redis dataset at version, for instances that
+// filled memory themselves before Bootstrap, e.g. by restoring a local snapshot taken
+// together with LoadedVersion on shutdown.
+func (c *HybridCache[V]) MarkLoaded(version int64) {
+	c.recordVersion(version)
+}
+
+// Bootstrap prepares memory at startup with a single GET of the Redis version: when memory
+// already holds data at exactly that version (see MarkLoaded), it is kept as is; otherwise,
+// including when Redis is at a lower version after losing or resetting it, Bootstrap loads
+// memory like LoadFromRedis, using ctx. Rolling restarts with a restored snapshot thus skip
+// downloading an unchanged dataset.
+func (c *HybridCache[V]) Bootstrap(ctx context.Context) error {
+	version, err := c.remote.GetVersionCtx(ctx)
+	if err != nil {
+		return err
+	}
+	if c.memory.Len() > 0 && version != 0 && c.version.Load() == version {
+		c.markValidated()
+		return nil
+	}
+	_, err = c.loadFromRedis(ctx, true)
+	return err
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHybridCache_Bootstrap(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	ctx := context.Background()
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// Cold start: memory is empty, so Bootstrap loads.
+	cold := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	if err := cold.Bootstrap(ctx); err != nil {
+		t.Fatalf("Bootstrap error: %v", err)
+	}
+	if _, ok := cold.Get("1"); !ok || cold.LoadedVersion() != 1 {
+		t.Errorf("Expected Bootstrap to load version 1, got version %d", cold.LoadedVersion())
+	}
+
+	// Restored snapshot at the current version: nothing is downloaded.
+	hook := &recordingHook{}
+	restored := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook))
+	restored.Memory().Set([]TestUser{{ID: "snapshot"}})
+	restored.MarkLoaded(1)
+	if err := restored.Bootstrap(ctx); err != nil {
+		t.Fatalf("Bootstrap error: %v", err)
+	}
+	if _, ok := restored.Get("snapshot"); !ok {
+		t.Error("Expected Bootstrap to keep a current snapshot")
+	}
+	for _, op := range hook.ops {
+		if op.op != OpGetVersion {
+			t.Errorf("Expected only a version read, got %s", op.op)
+		}
+	}
+
+	// Redis moved past the snapshot: Bootstrap loads.
+	if err := writer.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := restored.Bootstrap(ctx); err != nil {
+		t.Fatalf("Bootstrap error: %v", err)
+	}
+	if _, ok := restored.Get("2"); !ok || restored.LoadedVersion() != 2 {
+		t.Errorf("Expected Bootstrap to load version 2, got version %d", restored.LoadedVersion())
+	}
+
+	// Redis lost or reset its version: a snapshot from a higher version is stale.
+	stale := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	stale.Memory().Set([]TestUser{{ID: "snapshot"}})
+	stale.MarkLoaded(5)
+	if err := stale.Bootstrap(ctx); err != nil {
+		t.Fatalf("Bootstrap error: %v", err)
+	}
+	if _, ok := stale.Get("2"); !ok || stale.LoadedVersion() != 2 {
+		t.Errorf("Expected Bootstrap to replace a snapshot above the Redis version, got version %d", stale.LoadedVersion())
+	}
+}
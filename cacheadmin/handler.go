@@ -0,0 +1,188 @@
+package cacheadmin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+// Registry holds the live caches served by Handler, by name.
+// It is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	caches map[string]entry
+}
+
+// entry is a registered cache behind closures, so caches of any value type share a map.
+type entry struct {
+	info    func(ctx context.Context) CacheInfo
+	get     func(index, key string) (any, bool)
+	clear   func(ctx context.Context) error
+	refresh func(ctx context.Context, fromSource bool) error
+	rebuild Rebuilder
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{caches: make(map[string]entry)}
+}
+
+// Register adds c to r under name, replacing a cache registered under the same name.
+func Register[V any](r *Registry, name string, c *cache.HybridCache[V]) {
+	e := entry{
+		info: func(ctx context.Context) CacheInfo {
+			info := CacheInfo{
+				Name:          name,
+				Items:         c.Memory().Len(),
+				Hash:          c.Memory().GetHash(),
+				LoadedVersion: c.LoadedVersion(),
+				WritePolicy:   c.WritePolicy().String(),
+				Stats:         c.Stats(),
+			}
+			if version, err := c.Store().GetVersionCtx(ctx); err != nil {
+				info.RemoteError = err.Error()
+			} else {
+				info.RemoteVersion = version
+			}
+			return info
+		},
+		get: func(index, key string) (any, bool) {
+			if index == "" {
+				return c.Get(key)
+			}
+			return c.GetByIndex(index, key)
+		},
+		clear: c.Clear,
+		refresh: func(ctx context.Context, fromSource bool) error {
+			if fromSource {
+				return c.RefreshFromSource(ctx)
+			}
+			return c.LoadFromRedis()
+		},
+		rebuild: c,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.caches[name] = e
+}
+
+// Unregister removes the cache registered under name, if any.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.caches, name)
+}
+
+// Names returns the registered names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.caches))
+	for name := range r.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookup returns the cache registered under name.
+func (r *Registry) lookup(name string) (entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.caches[name]
+	return e, ok
+}
+
+// CacheInfo is the JSON body describing one cache.
+type CacheInfo struct {
+	Name          string            `json:"name"`
+	Items         int               `json:"items"`
+	Hash          string            `json:"hash"`
+	LoadedVersion int64             `json:"loaded_version"`
+	RemoteVersion int64             `json:"remote_version"`
+	RemoteError   string            `json:"remote_error,omitempty"`
+	WritePolicy   string            `json:"write_policy"`
+	Stats         cache.HybridStats `json:"stats"`
+}
+
+// ErrorResponse is the JSON body of a failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns an http.Handler serving the caches of r:
+//
+//	GET  /caches                      CacheInfo of every cache
+//	GET  /caches/{name}               CacheInfo of one cache
+//	GET  /caches/{name}/keys/{key}    the value under a primary key, or ?index=email
+//	POST /caches/{name}/clear         Clear
+//	POST /caches/{name}/refresh       LoadFromRedis, or RefreshFromSource with ?source=true
+//	POST /caches/{name}/rebuild       RebuildRedis, see RebuildHandler
+//
+// Unknown caches and keys answer 404, failed Redis (or store) calls 502. Mount it under a
+// prefix with http.StripPrefix.
+func Handler(r *Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /caches", func(w http.ResponseWriter, req *http.Request) {
+		infos := []CacheInfo{}
+		for _, name := range r.Names() {
+			if e, ok := r.lookup(name); ok {
+				infos = append(infos, e.info(req.Context()))
+			}
+		}
+		writeJSON(w, http.StatusOK, infos)
+	})
+	mux.HandleFunc("GET /caches/{name}", r.with(func(w http.ResponseWriter, req *http.Request, e entry) {
+		writeJSON(w, http.StatusOK, e.info(req.Context()))
+	}))
+	mux.HandleFunc("GET /caches/{name}/keys/{key}", r.with(func(w http.ResponseWriter, req *http.Request, e entry) {
+		v, ok := e.get(req.URL.Query().Get("index"), req.PathValue("key"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "key not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	}))
+	mux.HandleFunc("POST /caches/{name}/clear", r.with(func(w http.ResponseWriter, req *http.Request, e entry) {
+		writeResult(w, e.clear(req.Context()), e.info(req.Context()))
+	}))
+	mux.HandleFunc("POST /caches/{name}/refresh", r.with(func(w http.ResponseWriter, req *http.Request, e entry) {
+		err := e.refresh(req.Context(), req.URL.Query().Get("source") == "true")
+		if errors.Is(err, cache.ErrNoLoader) {
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: err.Error()})
+			return
+		}
+		writeResult(w, err, e.info(req.Context()))
+	}))
+	mux.HandleFunc("POST /caches/{name}/rebuild", r.with(func(w http.ResponseWriter, req *http.Request, e entry) {
+		RebuildHandler(e.rebuild).ServeHTTP(w, req)
+	}))
+	return mux
+}
+
+// with resolves the {name} of a request to its cache, answering 404 if none is registered.
+func (r *Registry) with(h func(http.ResponseWriter, *http.Request, entry)) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		e, ok := r.lookup(req.PathValue("name"))
+		if !ok {
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: "cache not found"})
+			return
+		}
+		h(w, req, e)
+	}
+}
+
+// writeResult answers 502 with err, or 200 with the cache info.
+func writeResult(w http.ResponseWriter, err error, info CacheInfo) {
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
@@ -0,0 +1,135 @@
+package cacheadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+)
+
+type user struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+}
+
+func newRegistry(t *testing.T) (*Registry, *cache.HybridCache[user], *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	config := cache.DefaultConfig[user]().WithPrimaryKey(func(u user) string { return u.ID })
+	users := cache.NewHybridCache(config, client, cache.DefaultRedisConfig().WithKeyPrefix("users:"))
+	users.AddIndex("email", func(u user) string { return u.Email })
+	if err := users.Set([]user{{ID: "1", Email: "a@x"}, {ID: "2", Email: "b@x"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	names := cache.NewHybridCache(cache.DefaultConfig[string]().WithPrimaryKey(func(s string) string { return s }),
+		client, cache.DefaultRedisConfig().WithKeyPrefix("names:"))
+
+	r := NewRegistry()
+	Register(r, "users", users)
+	Register(r, "names", names)
+	return r, users, mr
+}
+
+func serve(t *testing.T, h http.Handler, method, target string, body any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if body != nil {
+		if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+			t.Fatalf("Failed to decode %s %s: %v", method, target, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandler_Info(t *testing.T) {
+	r, users, _ := newRegistry(t)
+	h := Handler(r)
+
+	var list []CacheInfo
+	if code := serve(t, h, http.MethodGet, "/caches", &list); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if len(list) != 2 || list[0].Name != "names" || list[1].Name != "users" {
+		t.Fatalf("Expected both caches sorted by name, got %+v", list)
+	}
+
+	var info CacheInfo
+	if code := serve(t, h, http.MethodGet, "/caches/users", &info); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if info.Items != 2 || info.Hash != users.Memory().GetHash() || info.LoadedVersion != 1 ||
+		info.RemoteVersion != 1 || info.WritePolicy != "write-through" {
+		t.Errorf("Unexpected info %+v", info)
+	}
+
+	var failed ErrorResponse
+	if code := serve(t, h, http.MethodGet, "/caches/missing", &failed); code != http.StatusNotFound || failed.Error == "" {
+		t.Errorf("Expected 404 for an unknown cache, got %d %+v", code, failed)
+	}
+	r.Unregister("names")
+	if r.Names()[0] != "users" || len(r.Names()) != 1 {
+		t.Errorf("Expected Unregister to remove the cache, got %v", r.Names())
+	}
+}
+
+func TestHandler_Keys(t *testing.T) {
+	r, _, _ := newRegistry(t)
+	h := Handler(r)
+
+	var u user
+	if code := serve(t, h, http.MethodGet, "/caches/users/keys/2", &u); code != http.StatusOK || u.Email != "b@x" {
+		t.Errorf("Expected user 2, got %d %+v", code, u)
+	}
+	if code := serve(t, h, http.MethodGet, "/caches/users/keys/a@x?index=email", &u); code != http.StatusOK || u.ID != "1" {
+		t.Errorf("Expected user 1 by email, got %d %+v", code, u)
+	}
+	if code := serve(t, h, http.MethodGet, "/caches/users/keys/9", nil); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown key, got %d", code)
+	}
+}
+
+func TestHandler_Operations(t *testing.T) {
+	r, users, mr := newRegistry(t)
+	h := Handler(r)
+
+	if code := serve(t, h, http.MethodGet, "/caches/users/clear", nil); code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET clear, got %d", code)
+	}
+	var info CacheInfo
+	if code := serve(t, h, http.MethodPost, "/caches/users/clear", &info); code != http.StatusOK || info.Items != 0 {
+		t.Errorf("Expected Clear to empty the cache, got %d %+v", code, info)
+	}
+
+	other := cache.NewRedisCache[user](redis.NewClient(&redis.Options{Addr: mr.Addr()}), cache.DefaultRedisConfig().WithKeyPrefix("users:"))
+	if err := other.Set([]user{{ID: "3"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if code := serve(t, h, http.MethodPost, "/caches/users/refresh", &info); code != http.StatusOK || info.Items != 1 {
+		t.Errorf("Expected refresh to load Redis, got %d %+v", code, info)
+	}
+	if _, ok := users.Get("3"); !ok {
+		t.Error("Expected refresh to reach the cache")
+	}
+	if code := serve(t, h, http.MethodPost, "/caches/users/refresh?source=true", nil); code != http.StatusConflict {
+		t.Errorf("Expected 409 without a loader, got %d", code)
+	}
+
+	var rebuilt RebuildResponse
+	if code := serve(t, h, http.MethodPost, "/caches/users/rebuild", &rebuilt); code != http.StatusOK || rebuilt.Version == 0 {
+		t.Errorf("Expected rebuild to succeed, got %d %+v", code, rebuilt)
+	}
+
+	mr.SetError("LOADING")
+	var failed ErrorResponse
+	if code := serve(t, h, http.MethodPost, "/caches/users/refresh", &failed); code != http.StatusBadGateway || failed.Error == "" {
+		t.Errorf("Expected 502 when Redis fails, got %d %+v", code, failed)
+	}
+}
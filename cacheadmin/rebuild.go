@@ -0,0 +1,54 @@
+// Package cacheadmin provides operator endpoints for cache-kit caches.
+//
+// Handlers perform no authentication; mount them on an internal listener or behind
+// your own auth middleware.
+package cacheadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+// Rebuilder rewrites the shared Redis copy from a node's memory cache.
+// *cache.HybridCache[V] implements it for any V.
+type Rebuilder interface {
+	RebuildRedis() (int64, error)
+}
+
+// RebuildResponse is the JSON body returned by the rebuild handler.
+type RebuildResponse struct {
+	Version int64  `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// RebuildHandler returns an http.Handler that calls r.RebuildRedis on POST and replies with
+// the new version. It answers 405 for other methods, 409 when the node's memory is empty
+// (so pick another node) and 502 when Redis could not be written.
+func RebuildHandler(r Rebuilder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, RebuildResponse{Error: "method not allowed"})
+			return
+		}
+
+		version, err := r.RebuildRedis()
+		switch {
+		case errors.Is(err, cache.ErrMemoryEmpty):
+			writeJSON(w, http.StatusConflict, RebuildResponse{Error: err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusBadGateway, RebuildResponse{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusOK, RebuildResponse{Version: version})
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,65 @@
+package cacheadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+type fakeRebuilder struct {
+	version int64
+	err     error
+	calls   int
+}
+
+func (f *fakeRebuilder) RebuildRedis() (int64, error) {
+	f.calls++
+	return f.version, f.err
+}
+
+func TestRebuildHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		rebuilder  *fakeRebuilder
+		wantStatus int
+		wantCalls  int
+	}{
+		{"success", http.MethodPost, &fakeRebuilder{version: 7}, http.StatusOK, 1},
+		{"wrong method", http.MethodGet, &fakeRebuilder{}, http.StatusMethodNotAllowed, 0},
+		{"empty memory", http.MethodPost, &fakeRebuilder{err: cache.ErrMemoryEmpty}, http.StatusConflict, 1},
+		{"redis failure", http.MethodPost, &fakeRebuilder{err: errors.New("connection refused")}, http.StatusBadGateway, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			RebuildHandler(tt.rebuilder).ServeHTTP(rec, httptest.NewRequest(tt.method, "/rebuild", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if tt.rebuilder.calls != tt.wantCalls {
+				t.Errorf("Expected %d RebuildRedis calls, got %d", tt.wantCalls, tt.rebuilder.calls)
+			}
+			var body RebuildResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if tt.wantStatus == http.StatusOK && body.Version != 7 {
+				t.Errorf("Expected version 7, got %d", body.Version)
+			}
+			if tt.wantStatus != http.StatusOK && body.Error == "" {
+				t.Error("Expected error message in response")
+			}
+		})
+	}
+}
+
+func TestRebuildHandler_HybridCache(t *testing.T) {
+	var _ Rebuilder = (*cache.HybridCache[string])(nil)
+}
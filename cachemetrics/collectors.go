@@ -0,0 +1,290 @@
+// Package cachemetrics provides Prometheus collectors for cache-kit caches. Register them
+// with an existing registry:
+//
+//	reg.MustRegister(cachemetrics.NewMemoryCollector("users", memoryCache))
+//
+//	rc := cachemetrics.NewRedisCollector("users")
+//	redisCache := cache.NewRedisCache[User](client, cache.DefaultRedisConfig().WithMetricsHook(rc))
+//	reg.MustRegister(rc.WithTTL(redisCache))
+//
+// Every metric carries a constant "cache" label with the name given to the constructor,
+// so several caches can share a registry.
+//
+// Alternatively, one Collector serves every cache wired with cache.Instrument, whatever
+// its type, under the cache_name, tier and op labels:
+//
+//	collector := cachemetrics.NewCollector()
+//	cache.Instrument("users", hybridCache, collector)
+//	cache.Instrument("flags", memoryCache, collector)
+//	reg.MustRegister(collector)
+package cachemetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cache "github.com/soulteary/cache-kit"
+)
+
+const namespace = "cachekit"
+
+// MemorySource is the part of *cache.MemoryCache read by MemoryCollector.
+type MemorySource interface {
+	Len() int
+	LookupStats() cache.LookupStats
+}
+
+// MemoryCollector exports the size and lookup counters of a memory cache.
+// Values are read on every scrape.
+type MemoryCollector struct {
+	source      MemorySource
+	items       *prometheus.Desc
+	hits        *prometheus.Desc
+	misses      *prometheus.Desc
+	hashChanges *prometheus.Desc
+}
+
+// NewMemoryCollector returns a collector for source, typically a *cache.MemoryCache
+// (for a HybridCache, pass hybrid.Memory()).
+func NewMemoryCollector(name string, source MemorySource) *MemoryCollector {
+	labels := prometheus.Labels{"cache": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "memory", metric), help, nil, labels)
+	}
+	return &MemoryCollector{
+		source:      source,
+		items:       desc("items", "Number of entries in the memory cache."),
+		hits:        desc("hits_total", "Lookups that found a value."),
+		misses:      desc("misses_total", "Lookups that found nothing."),
+		hashChanges: desc("hash_changes_total", "Modifications that changed the dataset hash."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *MemoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.items
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.hashChanges
+}
+
+// Collect implements prometheus.Collector.
+func (c *MemoryCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source.LookupStats()
+	ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(c.source.Len()))
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.hashChanges, prometheus.CounterValue, float64(stats.HashChanges))
+}
+
+// TTLSource reports the remaining lifetime of a Redis key, e.g. *cache.RedisCache.
+type TTLSource interface {
+	TTL() (time.Duration, error)
+}
+
+// RedisCollector records RedisCache operations as a cache.MetricsHook (latency, errors,
+// payload bytes per op) and optionally exports the remaining TTL of the data key.
+type RedisCollector struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+	ttl      *prometheus.Desc
+	source   TTLSource
+}
+
+// NewRedisCollector returns a collector to pass to RedisConfig.WithMetricsHook.
+func NewRedisCollector(name string) *RedisCollector {
+	labels := prometheus.Labels{"cache": name}
+	return &RedisCollector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   namespace,
+			Subsystem:   "redis",
+			Name:        "op_duration_seconds",
+			Help:        "Duration of Redis cache operations.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms .. ~4s
+		}, []string{"op"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "redis",
+			Name:        "op_errors_total",
+			Help:        "Redis cache operations that returned an error.",
+			ConstLabels: labels,
+		}, []string{"op"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "redis",
+			Name:        "payload_bytes_total",
+			Help:        "Encoded payload bytes sent or received by Redis cache operations.",
+			ConstLabels: labels,
+		}, []string{"op"}),
+		ttl: prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis", "ttl_seconds"),
+			"Remaining TTL of the cache data key; negative if the key is missing or has no expiry.",
+			nil, labels),
+	}
+}
+
+// WithTTL makes the collector export the remaining TTL reported by source on every scrape.
+// Must be called before the collector is registered.
+func (c *RedisCollector) WithTTL(source TTLSource) *RedisCollector {
+	c.source = source
+	return c
+}
+
+// ObserveOp implements cache.MetricsHook.
+func (c *RedisCollector) ObserveOp(op string, dur time.Duration, bytes int, err error) {
+	c.duration.WithLabelValues(op).Observe(dur.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(op).Inc()
+	}
+	if bytes > 0 {
+		c.bytes.WithLabelValues(op).Add(float64(bytes))
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *RedisCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.bytes.Describe(ch)
+	if c.source != nil {
+		ch <- c.ttl
+	}
+}
+
+// Collect implements prometheus.Collector. A failing TTL read omits the TTL metric.
+func (c *RedisCollector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.bytes.Collect(ch)
+	if c.source == nil {
+		return
+	}
+	if ttl, err := c.source.TTL(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.ttl, prometheus.GaugeValue, ttl.Seconds())
+	}
+}
+
+// Collector exports the caches wired with cache.Instrument: operation latency, errors and
+// payload bytes by cache_name, tier and op, and the size, lookup counters and staleness of
+// memory tiers by cache_name and tier, read on every scrape.
+type Collector struct {
+	duration  *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	bytes     *prometheus.CounterVec
+	items     *prometheus.Desc
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	staleness *prometheus.Desc
+
+	mu      sync.Mutex
+	sources []statsSource
+}
+
+// statsSource is a tier registered with Collector.AddStats.
+type statsSource struct {
+	labels cache.MetricLabels
+	stats  func() cache.TierStats
+}
+
+// NewCollector returns a collector to pass to cache.Instrument and register once.
+func NewCollector() *Collector {
+	opLabels := []string{cache.LabelCacheName, cache.LabelTier, cache.LabelOp}
+	tierLabels := []string{cache.LabelCacheName, cache.LabelTier}
+	return &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "op_duration_seconds",
+			Help:      "Duration of cache operations.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14), // 0.5ms .. ~4s
+		}, opLabels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "op_errors_total",
+			Help:      "Cache operations that returned an error.",
+		}, opLabels),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "payload_bytes_total",
+			Help:      "Encoded payload bytes sent or received by cache operations.",
+		}, opLabels),
+		items: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "items"),
+			"Number of entries held by the tier.", tierLabels, nil),
+		hits: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "hits_total"),
+			"Lookups that found a value.", tierLabels, nil),
+		misses: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "misses_total"),
+			"Lookups that found nothing.", tierLabels, nil),
+		staleness: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "staleness_seconds"),
+			"Time since the tier last loaded or wrote its dataset.", tierLabels, nil),
+	}
+}
+
+// ObserveOp implements cache.Collector.
+func (c *Collector) ObserveOp(labels cache.MetricLabels, dur time.Duration, bytes int, err error) {
+	values := []string{labels.CacheName, labels.Tier, labels.Op}
+	c.duration.WithLabelValues(values...).Observe(dur.Seconds())
+	if err != nil {
+		c.errors.WithLabelValues(values...).Inc()
+	}
+	if bytes > 0 {
+		c.bytes.WithLabelValues(values...).Add(float64(bytes))
+	}
+}
+
+// AddStats implements cache.Collector. A tier registered again, e.g. by instrumenting a
+// recreated cache under the same name, replaces the earlier one: duplicate series would
+// fail the scrape.
+func (c *Collector) AddStats(labels cache.MetricLabels, stats func() cache.TierStats) {
+	labels.Op = ""
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.sources {
+		if s.labels == labels {
+			c.sources[i].stats = stats
+			return
+		}
+	}
+	c.sources = append(c.sources, statsSource{labels: labels, stats: stats})
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.duration.Describe(ch)
+	c.errors.Describe(ch)
+	c.bytes.Describe(ch)
+	ch <- c.items
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.staleness
+}
+
+// Collect implements prometheus.Collector. Staleness is only exported for tiers tracking
+// it.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.duration.Collect(ch)
+	c.errors.Collect(ch)
+	c.bytes.Collect(ch)
+
+	c.mu.Lock()
+	sources := append([]statsSource(nil), c.sources...)
+	c.mu.Unlock()
+	for _, s := range sources {
+		stats := s.stats()
+		name, tier := s.labels.CacheName, s.labels.Tier
+		ch <- prometheus.MustNewConstMetric(c.items, prometheus.GaugeValue, float64(stats.Items), name, tier)
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), name, tier)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), name, tier)
+		if stats.Staleness > 0 {
+			ch <- prometheus.MustNewConstMetric(c.staleness, prometheus.GaugeValue, stats.Staleness.Seconds(), name, tier)
+		}
+	}
+}
+
+var (
+	_ prometheus.Collector = (*MemoryCollector)(nil)
+	_ prometheus.Collector = (*RedisCollector)(nil)
+	_ cache.MetricsHook    = (*RedisCollector)(nil)
+	_ prometheus.Collector = (*Collector)(nil)
+	_ cache.Collector      = (*Collector)(nil)
+)
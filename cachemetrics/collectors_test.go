@@ -0,0 +1,169 @@
+package cachemetrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+)
+
+type user struct {
+	ID    string
+	Email string
+}
+
+func TestMemoryCollector(t *testing.T) {
+	mem := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }))
+	mem.Set([]user{{ID: "1"}, {ID: "2"}})
+	mem.Get("1")
+	mem.Get("missing")
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewMemoryCollector("users", mem))
+
+	want := `
+# HELP cachekit_memory_hash_changes_total Modifications that changed the dataset hash.
+# TYPE cachekit_memory_hash_changes_total counter
+cachekit_memory_hash_changes_total{cache="users"} 1
+# HELP cachekit_memory_hits_total Lookups that found a value.
+# TYPE cachekit_memory_hits_total counter
+cachekit_memory_hits_total{cache="users"} 1
+# HELP cachekit_memory_items Number of entries in the memory cache.
+# TYPE cachekit_memory_items gauge
+cachekit_memory_items{cache="users"} 2
+# HELP cachekit_memory_misses_total Lookups that found nothing.
+# TYPE cachekit_memory_misses_total counter
+cachekit_memory_misses_total{cache="users"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedisCollector(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	rc := NewRedisCollector("users")
+	rcache := cache.NewRedisCache[user](client, cache.DefaultRedisConfig().
+		WithTTL(time.Hour).
+		WithMetricsHook(rc))
+	rc.WithTTL(rcache)
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(rc)
+
+	if err := rcache.Set([]user{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, err := rcache.Get(); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	rc.ObserveOp(cache.OpGet, time.Millisecond, 0, errors.New("boom"))
+
+	if got := testutil.ToFloat64(rc.errors.WithLabelValues(cache.OpGet)); got != 1 {
+		t.Errorf("Expected 1 get error, got %v", got)
+	}
+	if got := testutil.ToFloat64(rc.bytes.WithLabelValues(cache.OpSet)); got == 0 {
+		t.Error("Expected set payload bytes to be recorded")
+	}
+	if got := testutil.CollectAndCount(rc, "cachekit_redis_op_duration_seconds"); got != 2 {
+		t.Errorf("Expected duration series for set and get, got %d", got)
+	}
+
+	want := `
+# HELP cachekit_redis_ttl_seconds Remaining TTL of the cache data key; negative if the key is missing or has no expiry.
+# TYPE cachekit_redis_ttl_seconds gauge
+cachekit_redis_ttl_seconds{cache="users"} 3600
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "cachekit_redis_ttl_seconds"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRedisCollector_WithoutTTL(t *testing.T) {
+	rc := NewRedisCollector("users")
+	if got := testutil.CollectAndCount(rc, "cachekit_redis_ttl_seconds"); got != 0 {
+		t.Errorf("Expected no TTL metric without a source, got %d", got)
+	}
+}
+
+func TestCollector_Instrument(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	hybrid := cache.NewHybridCache[user](cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }), client, cache.DefaultRedisConfig())
+	mem := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }))
+
+	collector := NewCollector()
+	cache.Instrument("users", hybrid, collector)
+	cache.Instrument("flags", mem, collector)
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(collector)
+
+	if err := hybrid.Set([]user{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	hybrid.Get("1")
+	mem.Get("missing")
+
+	want := `
+# HELP cachekit_items Number of entries held by the tier.
+# TYPE cachekit_items gauge
+cachekit_items{cache_name="flags",tier="memory"} 0
+cachekit_items{cache_name="users",tier="memory"} 2
+# HELP cachekit_misses_total Lookups that found nothing.
+# TYPE cachekit_misses_total counter
+cachekit_misses_total{cache_name="flags",tier="memory"} 1
+cachekit_misses_total{cache_name="users",tier="memory"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "cachekit_items", "cachekit_misses_total"); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(collector, "cachekit_staleness_seconds"); got != 1 {
+		t.Errorf("Expected staleness for the hybrid memory tier only, got %d series", got)
+	}
+	if got := testutil.ToFloat64(collector.bytes.WithLabelValues("users", cache.TierRedis, cache.OpSet)); got == 0 {
+		t.Error("Expected set payload bytes recorded under the redis tier")
+	}
+	collector.ObserveOp(cache.MetricLabels{CacheName: "users", Tier: cache.TierRedis, Op: cache.OpGet}, time.Millisecond, 0, errors.New("boom"))
+	if got := testutil.ToFloat64(collector.errors.WithLabelValues("users", cache.TierRedis, cache.OpGet)); got != 1 {
+		t.Errorf("Expected 1 get error, got %v", got)
+	}
+}
+
+func TestCollector_AddStatsReplaces(t *testing.T) {
+	collector := NewCollector()
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(collector)
+
+	// A recreated cache instrumented under the same name replaces the old series.
+	old := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }))
+	old.Set([]user{{ID: "1"}})
+	cache.Instrument("flags", old, collector)
+	recreated := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }))
+	recreated.Set([]user{{ID: "1"}, {ID: "2"}})
+	cache.Instrument("flags", recreated, collector)
+
+	want := `
+# HELP cachekit_items Number of entries held by the tier.
+# TYPE cachekit_items gauge
+cachekit_items{cache_name="flags",tier="memory"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "cachekit_items"); err != nil {
+		t.Error(err)
+	}
+}
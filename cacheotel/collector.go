@@ -0,0 +1,158 @@
+// Package cacheotel reports cache-kit metrics through the OpenTelemetry metric API, for
+// services exporting to an OTel collector instead of Prometheus:
+//
+//	collector, err := cacheotel.NewCollector(otel.Meter("github.com/soulteary/cache-kit"))
+//	if err != nil { ... }
+//	cache.Instrument("users", hybridCache, collector)
+//
+// Operations are recorded as they complete, with the cache_name, tier and op attributes
+// of cache.Instrument; the size, lookup counters, hit ratio and staleness of memory tiers
+// are observed on every collection.
+package cacheotel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Collector is a cache.Collector recording to OpenTelemetry instruments:
+//
+//   - cachekit.op.duration (s): histogram of operation durations, including
+//     HybridCache loads (tier "hybrid", op "load");
+//   - cachekit.op.errors: operations that returned an error;
+//   - cachekit.payload.size (By): encoded payload bytes sent or received;
+//   - cachekit.items, cachekit.hits, cachekit.misses, cachekit.hit_ratio: size and
+//     lookups of memory tiers;
+//   - cachekit.staleness (s): time since a tier last loaded or wrote its dataset, for
+//     tiers tracking it.
+type Collector struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	bytes    metric.Int64Counter
+
+	items     metric.Int64ObservableGauge
+	hits      metric.Int64ObservableCounter
+	misses    metric.Int64ObservableCounter
+	hitRatio  metric.Float64ObservableGauge
+	staleness metric.Float64ObservableGauge
+
+	mu      sync.Mutex
+	sources []statsSource
+}
+
+// statsSource is a tier registered with Collector.AddStats.
+type statsSource struct {
+	labels cache.MetricLabels
+	attrs  metric.MeasurementOption
+	stats  func() cache.TierStats
+}
+
+// NewCollector creates the instruments on meter and returns a collector to pass to
+// cache.Instrument.
+func NewCollector(meter metric.Meter) (*Collector, error) {
+	c := &Collector{}
+	var err error
+	if c.duration, err = meter.Float64Histogram("cachekit.op.duration",
+		metric.WithDescription("Duration of cache operations."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5)); err != nil {
+		return nil, err
+	}
+	if c.errors, err = meter.Int64Counter("cachekit.op.errors",
+		metric.WithDescription("Cache operations that returned an error.")); err != nil {
+		return nil, err
+	}
+	if c.bytes, err = meter.Int64Counter("cachekit.payload.size",
+		metric.WithDescription("Encoded payload bytes sent or received by cache operations."),
+		metric.WithUnit("By")); err != nil {
+		return nil, err
+	}
+	if c.items, err = meter.Int64ObservableGauge("cachekit.items",
+		metric.WithDescription("Number of entries held by the tier.")); err != nil {
+		return nil, err
+	}
+	if c.hits, err = meter.Int64ObservableCounter("cachekit.hits",
+		metric.WithDescription("Lookups that found a value.")); err != nil {
+		return nil, err
+	}
+	if c.misses, err = meter.Int64ObservableCounter("cachekit.misses",
+		metric.WithDescription("Lookups that found nothing.")); err != nil {
+		return nil, err
+	}
+	if c.hitRatio, err = meter.Float64ObservableGauge("cachekit.hit_ratio",
+		metric.WithDescription("Share of lookups that found a value, since the cache was created.")); err != nil {
+		return nil, err
+	}
+	if c.staleness, err = meter.Float64ObservableGauge("cachekit.staleness",
+		metric.WithDescription("Time since the tier last loaded or wrote its dataset."),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if _, err = meter.RegisterCallback(c.observe, c.items, c.hits, c.misses, c.hitRatio, c.staleness); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ObserveOp implements cache.Collector.
+func (c *Collector) ObserveOp(labels cache.MetricLabels, dur time.Duration, bytes int, err error) {
+	ctx := context.Background()
+	attrs := metric.WithAttributes(
+		attribute.String(cache.LabelCacheName, labels.CacheName),
+		attribute.String(cache.LabelTier, labels.Tier),
+		attribute.String(cache.LabelOp, labels.Op),
+	)
+	c.duration.Record(ctx, dur.Seconds(), attrs)
+	if err != nil {
+		c.errors.Add(ctx, 1, attrs)
+	}
+	if bytes > 0 {
+		c.bytes.Add(ctx, int64(bytes), attrs)
+	}
+}
+
+// AddStats implements cache.Collector. A tier registered again replaces the earlier one,
+// so each tier is observed once.
+func (c *Collector) AddStats(labels cache.MetricLabels, stats func() cache.TierStats) {
+	labels.Op = ""
+	attrs := metric.WithAttributes(
+		attribute.String(cache.LabelCacheName, labels.CacheName),
+		attribute.String(cache.LabelTier, labels.Tier),
+	)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.sources {
+		if s.labels == labels {
+			c.sources[i].stats = stats
+			return
+		}
+	}
+	c.sources = append(c.sources, statsSource{labels: labels, attrs: attrs, stats: stats})
+}
+
+// observe reports the registered tiers. The hit ratio is omitted before the first lookup.
+func (c *Collector) observe(ctx context.Context, o metric.Observer) error {
+	c.mu.Lock()
+	sources := append([]statsSource(nil), c.sources...)
+	c.mu.Unlock()
+	for _, s := range sources {
+		stats := s.stats()
+		o.ObserveInt64(c.items, int64(stats.Items), s.attrs)
+		o.ObserveInt64(c.hits, int64(stats.Hits), s.attrs)
+		o.ObserveInt64(c.misses, int64(stats.Misses), s.attrs)
+		if lookups := stats.Hits + stats.Misses; lookups > 0 {
+			o.ObserveFloat64(c.hitRatio, float64(stats.Hits)/float64(lookups), s.attrs)
+		}
+		if stats.Staleness > 0 {
+			o.ObserveFloat64(c.staleness, stats.Staleness.Seconds(), s.attrs)
+		}
+	}
+	return nil
+}
+
+var _ cache.Collector = (*Collector)(nil)
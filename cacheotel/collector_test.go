@@ -0,0 +1,168 @@
+package cacheotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type user struct {
+	ID string
+}
+
+// collect returns the metrics recorded so far, by instrument name.
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// attr returns the value of key in set.
+func attr(set attribute.Set, key string) string {
+	v, _ := set.Value(attribute.Key(key))
+	return v.AsString()
+}
+
+func TestCollector(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	collector, err := NewCollector(provider.Meter("cache-kit"))
+	if err != nil {
+		t.Fatalf("NewCollector error: %v", err)
+	}
+
+	hybrid := cache.NewHybridCache[user](cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }), client, cache.DefaultRedisConfig())
+	cache.Instrument("users", hybrid, collector)
+
+	if err := hybrid.Set([]user{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := hybrid.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	hybrid.Get("1")
+	hybrid.Get("1")
+	hybrid.Get("missing")
+	mr.SetError("LOADING")
+	if err := hybrid.LoadFromRedis(); err == nil {
+		t.Fatal("Expected LoadFromRedis to fail")
+	}
+
+	metrics := collect(t, reader)
+
+	durations, ok := metrics["cachekit.op.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("Expected duration histogram, got %T", metrics["cachekit.op.duration"])
+	}
+	var loads uint64
+	for _, p := range durations.DataPoints {
+		if attr(p.Attributes, cache.LabelTier) == cache.TierHybrid && attr(p.Attributes, cache.LabelOp) == cache.OpLoad {
+			loads += p.Count
+		}
+	}
+	if loads != 2 {
+		t.Errorf("Expected 2 load durations, got %d", loads)
+	}
+
+	errs, ok := metrics["cachekit.op.errors"].(metricdata.Sum[int64])
+	if !ok || len(errs.DataPoints) == 0 {
+		t.Fatalf("Expected error counts, got %v", metrics["cachekit.op.errors"])
+	}
+
+	sizes, ok := metrics["cachekit.payload.size"].(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("Expected payload sizes, got %T", metrics["cachekit.payload.size"])
+	}
+	var setBytes int64
+	for _, p := range sizes.DataPoints {
+		if attr(p.Attributes, cache.LabelCacheName) == "users" && attr(p.Attributes, cache.LabelOp) == cache.OpSet {
+			setBytes += p.Value
+		}
+	}
+	if setBytes == 0 {
+		t.Error("Expected set payload bytes recorded")
+	}
+
+	ratio, ok := metrics["cachekit.hit_ratio"].(metricdata.Gauge[float64])
+	if !ok || len(ratio.DataPoints) != 1 {
+		t.Fatalf("Expected one hit ratio, got %v", metrics["cachekit.hit_ratio"])
+	}
+	if got := ratio.DataPoints[0].Value; got < 0.66 || got > 0.67 {
+		t.Errorf("Expected hit ratio 2/3, got %v", got)
+	}
+	if tier := attr(ratio.DataPoints[0].Attributes, cache.LabelTier); tier != cache.TierMemory {
+		t.Errorf("Expected memory tier, got %q", tier)
+	}
+
+	items, ok := metrics["cachekit.items"].(metricdata.Gauge[int64])
+	if !ok || len(items.DataPoints) != 1 || items.DataPoints[0].Value != 2 {
+		t.Errorf("Expected 2 items, got %v", metrics["cachekit.items"])
+	}
+	if _, ok := metrics["cachekit.staleness"].(metricdata.Gauge[float64]); !ok {
+		t.Errorf("Expected staleness gauge, got %v", metrics["cachekit.staleness"])
+	}
+}
+
+func TestCollector_MemoryWithoutLookups(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	collector, err := NewCollector(provider.Meter("cache-kit"))
+	if err != nil {
+		t.Fatalf("NewCollector error: %v", err)
+	}
+	mem := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+		WithPrimaryKey(func(u user) string { return u.ID }))
+	cache.Instrument("flags", mem, collector)
+
+	metrics := collect(t, reader)
+	if _, ok := metrics["cachekit.hit_ratio"]; ok {
+		t.Error("Expected no hit ratio before the first lookup")
+	}
+	if _, ok := metrics["cachekit.staleness"]; ok {
+		t.Error("Expected no staleness for a standalone memory cache")
+	}
+	if _, ok := metrics["cachekit.items"]; !ok {
+		t.Error("Expected items gauge")
+	}
+}
+
+func TestCollector_AddStatsReplaces(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	collector, err := NewCollector(provider.Meter("cache-kit"))
+	if err != nil {
+		t.Fatalf("NewCollector error: %v", err)
+	}
+	// A recreated cache instrumented under the same name replaces the old one.
+	for _, users := range [][]user{{{ID: "1"}}, {{ID: "1"}, {ID: "2"}}} {
+		mem := cache.NewMultiIndexCache(cache.DefaultConfig[user]().
+			WithPrimaryKey(func(u user) string { return u.ID }))
+		mem.Set(users)
+		cache.Instrument("flags", mem, collector)
+	}
+
+	items, ok := collect(t, reader)["cachekit.items"].(metricdata.Gauge[int64])
+	if !ok || len(items.DataPoints) != 1 || items.DataPoints[0].Value != 2 {
+		t.Errorf("Expected one items point from the last registration, got %+v", items)
+	}
+}
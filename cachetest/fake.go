@@ -0,0 +1,152 @@
+// Package cachetest provides test doubles for code built on cache-kit. Depend on
+// cache.Hybrid instead of *cache.HybridCache, and pass a FakeHybrid in tests:
+//
+//	fake := cachetest.NewFakeHybrid(userConfig)
+//	fake.AddIndex("email", func(u User) string { return u.Email })
+//	svc := NewService(fake) // NewService(users cache.Hybrid[User])
+//
+// FakeHybrid keeps "Redis" in process, so tests need neither a Redis server nor
+// miniredis.
+package cachetest
+
+import (
+	"slices"
+	"sync"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+// FakeHybrid is an in-memory cache.Hybrid. It has a real MemoryCache for the memory tier
+// and a plain slice standing in for the Redis dataset, and behaves like a HybridCache
+// with the default write-through policy: Set and Delete update memory and then the
+// remote dataset, LoadFromRedis replaces memory with the remote dataset (clearing it when
+// there is none), and SyncToRedis copies memory to the remote dataset.
+// It is safe for concurrent use.
+type FakeHybrid[V any] struct {
+	memory *cache.MemoryCache[V]
+	pkFunc cache.KeyFunc[V]
+
+	mu        sync.Mutex
+	remote    []V
+	hasRemote bool
+	remoteErr error
+}
+
+var _ cache.Hybrid[struct{}] = (*FakeHybrid[struct{}])(nil)
+
+// NewFakeHybrid creates a FakeHybrid whose memory tier uses config, which must set a
+// primary key as for cache.NewHybridCache.
+func NewFakeHybrid[V any](config *cache.Config[V]) *FakeHybrid[V] {
+	if config == nil {
+		config = cache.DefaultConfig[V]()
+	}
+	return &FakeHybrid[V]{memory: cache.NewMultiIndexCache(config), pkFunc: config.PrimaryKeyFunc}
+}
+
+// AddIndex registers an index on the memory tier.
+func (f *FakeHybrid[V]) AddIndex(name string, keyFunc cache.KeyFunc[V]) {
+	f.memory.AddIndex(name, keyFunc)
+}
+
+// Set stores values in memory, then in the remote dataset unless an error was set by
+// SetRemoteError, in which case memory keeps the new values and the error is returned.
+func (f *FakeHybrid[V]) Set(values []V) error {
+	f.memory.Set(values)
+	return f.writeRemote(slices.Clone(values))
+}
+
+// Get retrieves a value from memory by its primary key.
+func (f *FakeHybrid[V]) Get(pk string) (V, bool) {
+	return f.memory.Get(pk)
+}
+
+// GetByIndex retrieves a value from memory by index.
+func (f *FakeHybrid[V]) GetByIndex(indexName string, key string) (V, bool) {
+	return f.memory.GetByIndex(indexName, key)
+}
+
+// GetAll returns all values from memory.
+func (f *FakeHybrid[V]) GetAll() []V {
+	return f.memory.GetAll()
+}
+
+// Delete removes the value stored under pk from memory and the remote dataset. A missing
+// pk is not an error.
+func (f *FakeHybrid[V]) Delete(pk string) error {
+	f.memory.Delete(pk)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.remoteErr != nil {
+		return f.remoteErr
+	}
+	f.remote = slices.DeleteFunc(f.remote, func(v V) bool { return f.pkFunc(v) == pk })
+	return nil
+}
+
+// LoadFromRedis replaces memory with the remote dataset, or clears memory when there is
+// none.
+func (f *FakeHybrid[V]) LoadFromRedis() error {
+	f.mu.Lock()
+	if f.remoteErr != nil {
+		defer f.mu.Unlock()
+		return f.remoteErr
+	}
+	values := slices.Clone(f.remote)
+	f.mu.Unlock()
+
+	f.memory.Set(values)
+	return nil
+}
+
+// SyncToRedis copies memory to the remote dataset.
+func (f *FakeHybrid[V]) SyncToRedis() error {
+	return f.writeRemote(f.memory.GetAll())
+}
+
+// Memory returns the memory tier.
+func (f *FakeHybrid[V]) Memory() *cache.MemoryCache[V] {
+	return f.memory
+}
+
+// Remote returns a copy of the remote dataset, and false if nothing was written yet.
+func (f *FakeHybrid[V]) Remote() ([]V, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.remote), f.hasRemote
+}
+
+// SetRemote replaces the remote dataset without touching memory, as another instance
+// writing Redis would.
+func (f *FakeHybrid[V]) SetRemote(values []V) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.remote = slices.Clone(values)
+	f.hasRemote = true
+}
+
+// SetRemoteError makes every operation reaching the remote dataset fail with err, as if
+// Redis were unavailable; memory writes still happen as in HybridCache. A nil err
+// restores normal operation.
+func (f *FakeHybrid[V]) SetRemoteError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.remoteErr = err
+}
+
+// writeRemote replaces the remote dataset with values.
+func (f *FakeHybrid[V]) writeRemote(values []V) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.remoteErr != nil {
+		return f.remoteErr
+	}
+	f.remote = values
+	f.hasRemote = true
+	return nil
+}
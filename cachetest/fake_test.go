@@ -0,0 +1,112 @@
+package cachetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+type user struct {
+	ID    string
+	Email string
+}
+
+// The fake and the real cache are interchangeable behind cache.Hybrid.
+var _ cache.Hybrid[user] = (*cache.HybridCache[user])(nil)
+
+func newFake() *FakeHybrid[user] {
+	config := cache.DefaultConfig[user]().WithPrimaryKey(func(u user) string { return u.ID })
+	f := NewFakeHybrid(config)
+	f.AddIndex("email", func(u user) string { return u.Email })
+	return f
+}
+
+func TestFakeHybrid_SetAndGet(t *testing.T) {
+	var f cache.Hybrid[user] = newFake()
+	if err := f.Set([]user{{ID: "1", Email: "a@x"}, {ID: "2", Email: "b@x"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if u, ok := f.Get("2"); !ok || u.Email != "b@x" {
+		t.Errorf("Get(2) = %+v, %v", u, ok)
+	}
+	if u, ok := f.GetByIndex("email", "a@x"); !ok || u.ID != "1" {
+		t.Errorf("GetByIndex = %+v, %v", u, ok)
+	}
+	if len(f.GetAll()) != 2 {
+		t.Errorf("Expected 2 values, got %d", len(f.GetAll()))
+	}
+
+	if err := f.Delete("1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if _, ok := f.Get("1"); ok {
+		t.Error("Expected Delete to remove the value from memory")
+	}
+	remote, _ := f.(*FakeHybrid[user]).Remote()
+	if !reflect.DeepEqual(remote, []user{{ID: "2", Email: "b@x"}}) {
+		t.Errorf("Expected Delete to remove the value remotely, got %+v", remote)
+	}
+}
+
+func TestFakeHybrid_RemoteTier(t *testing.T) {
+	f := newFake()
+	if _, ok := f.Remote(); ok {
+		t.Error("Expected no remote dataset before the first write")
+	}
+
+	// Another instance writes Redis; only LoadFromRedis picks it up.
+	f.SetRemote([]user{{ID: "9", Email: "z@x"}})
+	if _, ok := f.Get("9"); ok {
+		t.Error("Expected SetRemote to leave memory alone")
+	}
+	if err := f.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if _, ok := f.GetByIndex("email", "z@x"); !ok {
+		t.Error("Expected LoadFromRedis to load the remote dataset")
+	}
+
+	f.Memory().Set([]user{{ID: "3"}})
+	if err := f.SyncToRedis(); err != nil {
+		t.Fatalf("SyncToRedis error: %v", err)
+	}
+	if remote, _ := f.Remote(); !reflect.DeepEqual(remote, []user{{ID: "3"}}) {
+		t.Errorf("Expected SyncToRedis to copy memory, got %+v", remote)
+	}
+}
+
+func TestFakeHybrid_RemoteError(t *testing.T) {
+	f := newFake()
+	f.SetRemote([]user{{ID: "1"}})
+	down := errors.New("connection refused")
+	f.SetRemoteError(down)
+
+	if err := f.Set([]user{{ID: "2"}}); !errors.Is(err, down) {
+		t.Errorf("Set error = %v, want %v", err, down)
+	}
+	if _, ok := f.Get("2"); !ok {
+		t.Error("Expected a failed Set to keep the new values in memory")
+	}
+	if err := f.LoadFromRedis(); !errors.Is(err, down) {
+		t.Errorf("LoadFromRedis error = %v, want %v", err, down)
+	}
+	if err := f.SyncToRedis(); !errors.Is(err, down) {
+		t.Errorf("SyncToRedis error = %v, want %v", err, down)
+	}
+	if err := f.Delete("2"); !errors.Is(err, down) {
+		t.Errorf("Delete error = %v, want %v", err, down)
+	}
+	if remote, _ := f.Remote(); !reflect.DeepEqual(remote, []user{{ID: "1"}}) {
+		t.Errorf("Expected the remote dataset to be unchanged, got %+v", remote)
+	}
+
+	f.SetRemoteError(nil)
+	if err := f.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if _, ok := f.Get("1"); !ok {
+		t.Error("Expected LoadFromRedis to work again after clearing the error")
+	}
+}
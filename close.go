@@ -0,0 +1,55 @@
+package cache
+
+import "context"
+
+// WithSyncOnClose makes Close finish with a SyncToRedis of the memory dataset, for
+// instances whose memory may hold writes Redis never received, e.g. after failed Sets.
+func (c *HybridCache[V]) WithSyncOnClose() *HybridCache[V] {
+	c.syncOnClose = true
+	return c
+}
+
+// Close is the shutdown hook of a HybridCache. It stops the StartAutoRefresh loop and the
+// WithPubSubReload subscriber, drains the write-behind queue with a final Flush and stops
+// its worker (later Sets write to Redis synchronously), under WithSyncOnClose runs a
+// final SyncToRedis, whose error replaces the one of the Flush as it writes the same
+// dataset, then writes a pending WithSyncRetry retry and the WithColdStore copy one last
+// time. Returns the error of the final write, or ctx.Err() if ctx is done first, in
+// which case the write continues in the background. Safe to call more than once.
+func (c *HybridCache[V]) Close(ctx context.Context) error {
+	c.Stop()
+	c.loopsMu.Lock()
+	reload := c.reload
+	c.reload = nil
+	c.loopsMu.Unlock()
+	reload.stop()
+
+	done := make(chan error, 1)
+	go func() { done <- c.drain() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// drain performs the final writes of Close.
+func (c *HybridCache[V]) drain() error {
+	var err error
+	if c.writeBehind != nil {
+		err = c.writeBehind.close()
+	}
+	if c.syncOnClose {
+		err = c.SyncToRedis()
+	}
+	for _, w := range []*writeBehind[V]{c.retry, c.cold} {
+		if w == nil {
+			continue
+		}
+		if wErr := w.close(); err == nil {
+			err = wErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHybridCache_CloseSyncOnClose(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithSyncOnClose()
+
+	// A Set whose Redis write failed leaves the dataset in memory only.
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err == nil {
+		t.Fatal("Expected the Set to fail")
+	}
+	mr.SetError("")
+
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "1" {
+		t.Errorf("Expected the final sync to write memory, got %s", idsOf(remote))
+	}
+}
+
+func TestHybridCache_CloseContext(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	client.Ping(context.Background())
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour})
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	release := make(chan struct{})
+	client.AddHook(blockingHook{release})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := cache.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error while the final write hangs, got %v", err)
+	}
+}
+
+// blockingHook holds every pipeline until release is closed.
+type blockingHook struct{ release chan struct{} }
+
+func (h blockingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h blockingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h blockingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		<-h.release
+		return next(ctx, cmds)
+	}
+}
@@ -0,0 +1,103 @@
+// Command cachekit is an operator CLI for services embedding cache-kit.
+//
+// Usage:
+//
+//	cachekit rebuild -url http://node-1:9090/admin/cache/rebuild
+//	cachekit info -addr localhost:6379 -prefix users:
+//	cachekit dump -prefix users:
+//	cachekit diff -prefix users: -other users-staging: -pk ID
+//	cachekit bump -prefix users:
+//	cachekit reset-version -prefix users:
+//	cachekit clear-prefix -prefix users: -yes
+//
+// rebuild asks the chosen node (served by cacheadmin.RebuildHandler) to rewrite Redis
+// from its memory cache and prints the new version.
+//
+// The other commands work on Redis directly, on a dataset named by its -prefix
+// (RedisConfig.KeyPrefix) or -key (NewRedisCacheWithKey) and written with the default
+// JSONCodec. info prints whether it exists, its version, TTL and size; dump prints the
+// decoded values; diff lists the values added (+), removed (-) and changed (~) in the
+// -other dataset, matched by their -pk field; bump increments the version so instances
+// reload and reset-version deletes it; clear-prefix deletes every key under the prefix,
+// which it requires as an explicit -prefix. Flag errors print the command's usage.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/soulteary/cache-kit/cacheadmin"
+)
+
+// stderr receives flag errors and usage.
+var stderr io.Writer = os.Stderr
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "cachekit:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: cachekit <rebuild|info|dump|diff|bump|reset-version|clear-prefix> [flags]")
+	}
+	switch args[0] {
+	case "rebuild":
+		return rebuild(args[1:], out)
+	case "info":
+		return info(args[1:], out)
+	case "dump":
+		return dump(args[1:], out)
+	case "diff":
+		return diff(args[1:], out)
+	case "bump":
+		return bump(args[1:], out)
+	case "reset-version":
+		return resetVersion(args[1:], out)
+	case "clear-prefix":
+		return clearPrefix(args[1:], out)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func rebuild(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("rebuild", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	url := fs.String("url", "", "rebuild endpoint of a healthy node")
+	timeout := fs.Duration("timeout", 30*time.Second, "request timeout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *url == "" {
+		return errors.New("rebuild: -url is required")
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	resp, err := client.Post(*url, "application/json", nil)
+	if err != nil {
+		return fmt.Errorf("rebuild: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body cacheadmin.RebuildResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("rebuild: decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("rebuild: %s: %s", resp.Status, body.Error)
+	}
+	_, err = fmt.Fprintf(out, "rebuilt Redis, version %d\n", body.Version)
+	return err
+}
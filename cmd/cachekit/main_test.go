@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+	"github.com/soulteary/cache-kit/cacheadmin"
+)
+
+type stubRebuilder struct {
+	version int64
+	err     error
+}
+
+func (s stubRebuilder) RebuildRedis() (int64, error) { return s.version, s.err }
+
+func TestRun_Rebuild(t *testing.T) {
+	srv := httptest.NewServer(cacheadmin.RebuildHandler(stubRebuilder{version: 3}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	if err := run([]string{"rebuild", "-url", srv.URL}, &out); err != nil {
+		t.Fatalf("run error: %v", err)
+	}
+	if !strings.Contains(out.String(), "version 3") {
+		t.Errorf("Expected version in output, got %q", out.String())
+	}
+}
+
+func TestRun_RebuildRejected(t *testing.T) {
+	srv := httptest.NewServer(cacheadmin.RebuildHandler(stubRebuilder{err: cache.ErrMemoryEmpty}))
+	defer srv.Close()
+
+	err := run([]string{"rebuild", "-url", srv.URL}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "memory cache is empty") {
+		t.Errorf("Expected empty-memory error, got %v", err)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	if err := run(nil, &bytes.Buffer{}); err == nil {
+		t.Error("Expected usage error without arguments")
+	}
+	if err := run([]string{"nope"}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for unknown command")
+	}
+	if err := run([]string{"rebuild"}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error without -url")
+	}
+	if err := run([]string{"rebuild", "-url", "http://127.0.0.1:0"}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for unreachable node")
+	}
+}
+
+func TestRun_FlagUsage(t *testing.T) {
+	var errOut bytes.Buffer
+	stderr = &errOut
+	defer func() { stderr = os.Stderr }()
+
+	if err := run([]string{"info", "-nope"}, &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected error for an unknown flag")
+	}
+	for _, want := range []string{"-nope", "Usage of info", "-prefix"} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("Expected %q in the flag error output, got %q", want, errOut.String())
+		}
+	}
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// seed writes users to the dataset under prefix.
+func seed(t *testing.T, mr *miniredis.Miniredis, prefix string, users []user) {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	c := cache.NewRedisCache[user](client, cache.DefaultRedisConfig().WithKeyPrefix(prefix))
+	if err := c.Set(users); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+}
+
+func TestRun_Inspect(t *testing.T) {
+	mr := miniredis.RunT(t)
+	seed(t, mr, "users:", []user{{1, "Ada"}, {2, "Grace"}})
+
+	var out bytes.Buffer
+	if err := run([]string{"info", "-addr", mr.Addr(), "-prefix", "users:"}, &out); err != nil {
+		t.Fatalf("info error: %v", err)
+	}
+	for _, want := range []string{"exists   true", "version  1", "ttl      "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in info output, got %q", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := run([]string{"dump", "-addr", mr.Addr(), "-prefix", "users:"}, &out); err != nil {
+		t.Fatalf("dump error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"name": "Grace"`) {
+		t.Errorf("Expected decoded values in dump, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"info", "-addr", mr.Addr(), "-prefix", "missing:"}, &out); err != nil {
+		t.Fatalf("info error: %v", err)
+	}
+	if !strings.Contains(out.String(), "exists   false") || !strings.Contains(out.String(), "ttl      missing") {
+		t.Errorf("Expected missing dataset, got %q", out.String())
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	mr := miniredis.RunT(t)
+	seed(t, mr, "a:", []user{{1, "Ada"}, {2, "Grace"}})
+	seed(t, mr, "b:", []user{{1, "Ada"}, {2, "Grace Hopper"}, {3, "Linus"}})
+	seed(t, mr, "c:", []user{{2, "Grace"}, {1, "Ada"}})
+
+	var out bytes.Buffer
+	if err := run([]string{"diff", "-addr", mr.Addr(), "-prefix", "a:", "-other", "b:", "-pk", "id"}, &out); err != nil {
+		t.Fatalf("diff error: %v", err)
+	}
+	if out.String() != "~ 2\n+ 3\n" {
+		t.Errorf("Expected changed 2 and added 3, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"diff", "-addr", mr.Addr(), "-prefix", "a:", "-other", "c:", "-pk", "id"}, &out); err != nil {
+		t.Fatalf("diff error: %v", err)
+	}
+	if !strings.Contains(out.String(), "identical (2 values)") {
+		t.Errorf("Expected identical datasets, got %q", out.String())
+	}
+
+	if err := run([]string{"diff", "-addr", mr.Addr(), "-prefix", "a:", "-other", "b:"}, &out); err == nil {
+		t.Error("Expected error for a missing pk field")
+	}
+	if err := run([]string{"diff", "-addr", mr.Addr(), "-prefix", "a:"}, &out); err == nil {
+		t.Error("Expected error without -other")
+	}
+}
+
+func TestRun_Versions(t *testing.T) {
+	mr := miniredis.RunT(t)
+	seed(t, mr, "users:", []user{{1, "Ada"}})
+
+	var out bytes.Buffer
+	if err := run([]string{"bump", "-addr", mr.Addr(), "-prefix", "users:"}, &out); err != nil {
+		t.Fatalf("bump error: %v", err)
+	}
+	if out.String() != "version 2\n" {
+		t.Errorf("Expected version 2, got %q", out.String())
+	}
+	if err := run([]string{"reset-version", "-addr", mr.Addr(), "-prefix", "users:"}, &out); err != nil {
+		t.Fatalf("reset-version error: %v", err)
+	}
+	if mr.Exists("users:version") {
+		t.Error("Expected version key deleted")
+	}
+}
+
+func TestRun_ClearPrefix(t *testing.T) {
+	mr := miniredis.RunT(t)
+	seed(t, mr, "users:", []user{{1, "Ada"}})
+	seed(t, mr, "other:", []user{{1, "Ada"}})
+
+	args := []string{"clear-prefix", "-addr", mr.Addr(), "-prefix", "users:"}
+	if err := run(args, &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected error without -yes")
+	}
+	if !mr.Exists("users:data") {
+		t.Fatal("Expected keys kept without -yes")
+	}
+
+	// Neither the default prefix nor -key may widen a mass delete.
+	seed(t, mr, "cache:", []user{{1, "Ada"}})
+	for _, args := range [][]string{
+		{"clear-prefix", "-addr", mr.Addr(), "-yes"},
+		{"clear-prefix", "-addr", mr.Addr(), "-prefix", "users:", "-key", "users:data", "-yes"},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("Expected %v to be refused", args[2:])
+		}
+	}
+	if !mr.Exists("cache:data") || !mr.Exists("users:data") {
+		t.Fatal("Expected keys kept by refused commands")
+	}
+
+	var out bytes.Buffer
+	if err := run(append(args, "-yes"), &out); err != nil {
+		t.Fatalf("clear-prefix error: %v", err)
+	}
+	if out.String() != "deleted 2 keys\n" {
+		t.Errorf("Expected 2 deleted keys, got %q", out.String())
+	}
+	if !mr.Exists("other:data") {
+		t.Error("Expected other prefix kept")
+	}
+}
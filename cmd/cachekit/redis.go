@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+)
+
+// target is a Redis-backed dataset named on the command line.
+type target struct {
+	addr, password string
+	db             int
+	prefix, key    string
+	suffix         string
+	timeout        time.Duration
+	prefixSet      bool // -prefix was given on the command line
+}
+
+// addTargetFlags registers the flags naming a dataset on fs.
+func addTargetFlags(fs *flag.FlagSet, t *target) {
+	fs.StringVar(&t.addr, "addr", "localhost:6379", "Redis address")
+	fs.StringVar(&t.password, "password", "", "Redis password")
+	fs.IntVar(&t.db, "db", 0, "Redis database")
+	fs.StringVar(&t.prefix, "prefix", "cache:", "RedisConfig.KeyPrefix of the cache")
+	fs.StringVar(&t.key, "key", "", "data key, for caches created with NewRedisCacheWithKey (overrides -prefix)")
+	fs.StringVar(&t.suffix, "version-suffix", ":version", "RedisConfig.VersionKeySuffix of the cache")
+	fs.DurationVar(&t.timeout, "timeout", 5*time.Second, "Redis operation timeout")
+}
+
+// open connects to the dataset, whose values are kept as raw JSON: the CLI reads caches
+// written with the default JSONCodec.
+func (t *target) open(prefix string) (*cache.RedisCache[json.RawMessage], func(), error) {
+	client := redis.NewClient(&redis.Options{Addr: t.addr, Password: t.password, DB: t.db})
+	config := cache.DefaultRedisConfig().WithVersionKeySuffix(t.suffix).WithOperationTimeout(t.timeout)
+	var c *cache.RedisCache[json.RawMessage]
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+		if t.key != "" {
+			c = cache.NewRedisCacheWithKey[json.RawMessage](client, t.key, config.WithKeyPrefix(prefix))
+		} else {
+			c = cache.NewRedisCache[json.RawMessage](client, config.WithKeyPrefix(prefix))
+		}
+	}()
+	if err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+	return c, func() { _ = client.Close() }, nil
+}
+
+// parseTarget parses the dataset flags of a command and connects to it.
+func parseTarget(name string, args []string, extra func(fs *flag.FlagSet)) (*cache.RedisCache[json.RawMessage], *target, func(), error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	t := &target{}
+	addTargetFlags(fs, t)
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	fs.Visit(func(f *flag.Flag) { t.prefixSet = t.prefixSet || f.Name == "prefix" })
+	c, closeFn, err := t.open(t.prefix)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return c, t, closeFn, nil
+}
+
+// info prints whether the dataset exists, its version, TTL and payload size.
+func info(args []string, out io.Writer) error {
+	c, _, closeFn, err := parseTarget("info", args, nil)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	exists, err := c.ExistsCtx(ctx)
+	if err != nil {
+		return fmt.Errorf("info: %w", err)
+	}
+	version, err := c.GetVersionCtx(ctx)
+	if err != nil {
+		return fmt.Errorf("info: %w", err)
+	}
+	ttl, err := c.TTLCtx(ctx)
+	if err != nil {
+		return fmt.Errorf("info: %w", err)
+	}
+	size, err := c.ValueSize(ctx)
+	if err != nil {
+		return fmt.Errorf("info: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "exists   %v\nversion  %d\nttl      %s\nsize     %d bytes\n", exists, version, formatTTL(ttl), size)
+	return err
+}
+
+// formatTTL renders a RedisCache TTL, which is -2 for a missing key and -1 without expiry.
+func formatTTL(ttl time.Duration) string {
+	switch ttl {
+	case -2:
+		return "missing"
+	case -1:
+		return "none"
+	}
+	return ttl.String()
+}
+
+// dump prints the decoded dataset as indented JSON.
+func dump(args []string, out io.Writer) error {
+	c, _, closeFn, err := parseTarget("dump", args, nil)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	values, err := c.GetCtx(context.Background())
+	if err != nil {
+		return fmt.Errorf("dump: %w", err)
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("dump: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// diff compares the dataset with the one under -other, by the -pk field of each value.
+func diff(args []string, out io.Writer) error {
+	var other, pk string
+	c, t, closeFn, err := parseTarget("diff", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&other, "other", "", "KeyPrefix of the dataset to compare with")
+		fs.StringVar(&pk, "pk", "ID", "JSON field holding the primary key of a value")
+	})
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	if other == "" {
+		return errors.New("diff: -other is required")
+	}
+	t.key = "" // -other names a prefix
+	o, closeOther, err := t.open(other)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	defer closeOther()
+
+	ctx := context.Background()
+	left, err := keyed(ctx, c, pk)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	right, err := keyed(ctx, o, pk)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+
+	var lines []string
+	for k, v := range left {
+		w, ok := right[k]
+		switch {
+		case !ok:
+			lines = append(lines, "- "+k)
+		case !bytes.Equal(v, w):
+			lines = append(lines, "~ "+k)
+		}
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			lines = append(lines, "+ "+k)
+		}
+	}
+	if len(lines) == 0 {
+		_, err = fmt.Fprintf(out, "identical (%d values)\n", len(left))
+		return err
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][2:] < lines[j][2:] })
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyed reads the dataset of c as compacted JSON values keyed by their pk field.
+func keyed(ctx context.Context, c *cache.RedisCache[json.RawMessage], pk string) (map[string][]byte, error) {
+	values, err := c.GetCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(map[string][]byte, len(values))
+	for i, v := range values {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(v, &fields); err != nil {
+			return nil, fmt.Errorf("value %d is not a JSON object: %w", i, err)
+		}
+		key, ok := fields[pk]
+		if !ok {
+			return nil, fmt.Errorf("value %d has no %q field", i, pk)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, v); err != nil {
+			return nil, err
+		}
+		items[string(key)] = compact.Bytes()
+	}
+	return items, nil
+}
+
+// bump increments the version so that instances reload.
+func bump(args []string, out io.Writer) error {
+	c, _, closeFn, err := parseTarget("bump", args, nil)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	version, err := c.BumpVersionCtx(context.Background())
+	if err != nil {
+		return fmt.Errorf("bump: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "version %d\n", version)
+	return err
+}
+
+// resetVersion deletes the version key.
+func resetVersion(args []string, out io.Writer) error {
+	c, _, closeFn, err := parseTarget("reset-version", args, nil)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	if err := c.ResetVersionCtx(context.Background()); err != nil {
+		return fmt.Errorf("reset-version: %w", err)
+	}
+	_, err = fmt.Fprintln(out, "version reset")
+	return err
+}
+
+// clearPrefix deletes every key under the prefix, once confirmed with -yes. The prefix
+// must be given with -prefix: the default is no safe guess for a mass delete, and -key
+// names a single dataset, not a namespace.
+func clearPrefix(args []string, out io.Writer) error {
+	var yes bool
+	c, t, closeFn, err := parseTarget("clear-prefix", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&yes, "yes", false, "confirm deleting every key under -prefix")
+	})
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	if t.key != "" {
+		return errors.New("clear-prefix: -key is not supported; name the keys to delete with -prefix")
+	}
+	if !t.prefixSet {
+		return errors.New("clear-prefix: -prefix is required")
+	}
+	if !yes {
+		return fmt.Errorf("clear-prefix: refusing to delete every key under %q without -yes", t.prefix)
+	}
+
+	deleted, err := c.ClearPrefix(context.Background())
+	if err != nil {
+		return fmt.Errorf("clear-prefix: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "deleted %d keys\n", deleted)
+	return err
+}
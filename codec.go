@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Codec serializes and deserializes cached values.
+// Implementations must be safe for concurrent use.
+type Codec interface {
+	// Marshal encodes v into bytes.
+	Marshal(v any) ([]byte, error)
+
+	// Unmarshal decodes data into the value pointed to by v.
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is a Codec backed by encoding/json.
+type JSONCodec struct{}
+
+// Marshal encodes v as JSON.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON data into v.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// JSONOptions tweaks the encoding/json behavior of the Codec returned by NewJSONCodec.
+// The zero value behaves like JSONCodec.
+type JSONOptions struct {
+	// DisableHTMLEscape keeps <, > and & as-is instead of escaping them as \u003c etc.,
+	// for payloads read by consumers that do not decode JSON escapes.
+	DisableHTMLEscape bool
+	// Indent, if set, pretty-prints the payload with this indent, for inspection with
+	// redis-cli at the cost of size.
+	Indent string
+	// DisallowUnknownFields makes Unmarshal fail on object keys without a matching field,
+	// e.g. to detect payloads written by a newer schema.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers into interface fields as json.Number instead of float64.
+	UseNumber bool
+}
+
+// NewJSONCodec returns a JSON Codec backed by encoding/json with the given options.
+// For another JSON library (jsoniter, sonic, ...) use CodecFuncs with its Marshal and
+// Unmarshal functions instead.
+func NewJSONCodec(opts JSONOptions) Codec {
+	if opts == (JSONOptions{}) {
+		return JSONCodec{}
+	}
+	return jsonCodec{opts: opts}
+}
+
+// jsonCodec is the Codec returned by NewJSONCodec for non-default options.
+type jsonCodec struct{ opts JSONOptions }
+
+// Marshal encodes v as JSON with the configured options.
+func (c jsonCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(!c.opts.DisableHTMLEscape)
+	if c.opts.Indent != "" {
+		enc.SetIndent("", c.opts.Indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Unmarshal decodes JSON data into v with the configured options. Like json.Unmarshal, it
+// rejects data after the top-level value.
+func (c jsonCodec) Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if c.opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if c.opts.UseNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid data after top-level JSON value")
+	}
+	return nil
+}
+
+// CodecFuncs is a Codec made of two functions, to plug in a library's marshal functions
+// without writing a type, e.g. CodecFuncs{MarshalFunc: sonic.Marshal, UnmarshalFunc: sonic.Unmarshal}.
+// Both must be set and safe for concurrent use.
+type CodecFuncs struct {
+	MarshalFunc   func(v any) ([]byte, error)
+	UnmarshalFunc func(data []byte, v any) error
+}
+
+// Marshal calls MarshalFunc.
+func (f CodecFuncs) Marshal(v any) ([]byte, error) {
+	return f.MarshalFunc(v)
+}
+
+// Unmarshal calls UnmarshalFunc.
+func (f CodecFuncs) Unmarshal(data []byte, v any) error {
+	return f.UnmarshalFunc(data, v)
+}
+
+// GobCodec is a Codec backed by encoding/gob, for caches read only by Go services.
+// It is usually faster than JSON for large payloads and handles types JSON can't represent
+// (e.g. maps with non-string keys, or interface fields whose types are registered with gob.Register).
+// Each Marshal call carries its own type information, so it is a poor fit for arena storage
+// of many small values.
+type GobCodec struct{}
+
+// Marshal encodes v with gob.
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v.
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
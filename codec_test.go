@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONCodec_RoundTrip(t *testing.T) {
+	codec := JSONCodec{}
+	in := []TestUser{{ID: "1", Email: "user1@example.com"}}
+
+	data, err := codec.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var out []TestUser
+	if err := codec.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(out) != 1 || out[0] != in[0] {
+		t.Errorf("Expected %+v, got %+v", in, out)
+	}
+
+	if err := codec.Unmarshal([]byte("not json"), &out); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestGobCodec_RoundTrip(t *testing.T) {
+	codec := GobCodec{}
+	type scored struct {
+		ID     string
+		Scores map[int]float64 // non-string map keys don't survive JSON
+	}
+	in := []scored{{ID: "1", Scores: map[int]float64{1: 0.5, 2: 1.5}}}
+
+	data, err := codec.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var out []scored
+	if err := codec.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(out) != 1 || out[0].Scores[2] != 1.5 {
+		t.Errorf("Expected %+v, got %+v", in, out)
+	}
+
+	if err := codec.Unmarshal([]byte("not gob"), &out); err == nil {
+		t.Error("Expected error for invalid gob data")
+	}
+}
+
+func TestGobCodec_RedisCache(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithCodec(GobCodec{}))
+
+	if err := cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, err := cache.Get()
+	if err != nil || len(values) != 1 || values[0].Email != "a@example.com" {
+		t.Errorf("Expected gob round trip through Redis, got %v (err %v)", values, err)
+	}
+
+	if err := cache.Set([]TestUser{}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, err = cache.Get()
+	if err != nil || values == nil || len(values) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v (err %v)", values, err)
+	}
+}
+
+func TestNewJSONCodec(t *testing.T) {
+	if _, ok := NewJSONCodec(JSONOptions{}).(JSONCodec); !ok {
+		t.Error("Expected the zero options to return JSONCodec")
+	}
+
+	codec := NewJSONCodec(JSONOptions{DisableHTMLEscape: true, Indent: "  "})
+	data, err := codec.Marshal([]TestUser{{ID: "1", Name: "<b>&</b>"}})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"<b>&</b>"`) || !strings.Contains(string(data), "\n  ") || strings.HasSuffix(string(data), "\n") {
+		t.Errorf("Expected indented JSON without HTML escaping or trailing newline, got %s", data)
+	}
+	var out []TestUser
+	if err := codec.Unmarshal(data, &out); err != nil || out[0].Name != "<b>&</b>" {
+		t.Errorf("Expected a round trip, got %+v (%v)", out, err)
+	}
+	if err := codec.Unmarshal([]byte(`[] []`), &out); err == nil {
+		t.Error("Expected an error for data after the top-level value")
+	}
+
+	strict := NewJSONCodec(JSONOptions{DisallowUnknownFields: true, UseNumber: true})
+	if err := strict.Unmarshal([]byte(`[{"ID":"1","Extra":true}]`), &out); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+	var generic []any
+	if err := strict.Unmarshal([]byte(`[12345678901234567890]`), &generic); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if _, ok := generic[0].(json.Number); !ok {
+		t.Errorf("Expected json.Number, got %T", generic[0])
+	}
+}
+
+func TestCodecFuncs_RedisCache(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	calls := 0
+	codec := CodecFuncs{
+		MarshalFunc:   func(v any) ([]byte, error) { calls++; return json.Marshal(v) },
+		UnmarshalFunc: func(data []byte, v any) error { calls++; return json.Unmarshal(data, v) },
+	}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithCodec(codec))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, err := cache.Get()
+	if err != nil || idsOf(values) != "1" || calls != 2 {
+		t.Errorf("Expected 1 through both functions, got %s after %d calls (%v)", idsOf(values), calls, err)
+	}
+}
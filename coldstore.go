@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ColdStore is the optional third tier of a HybridCache, behind memory and Redis: a
+// durable copy of the encoded dataset, such as an object storage blob or a disk snapshot.
+// Implementations must be safe for concurrent use.
+type ColdStore interface {
+	// Load returns the stored dataset; found is false if nothing was stored yet.
+	Load(ctx context.Context) (data []byte, found bool, err error)
+
+	// Store replaces the stored dataset.
+	Store(ctx context.Context, data []byte) error
+}
+
+// WithColdStore adds store as a third tier, so a total Redis flush does not turn into a
+// stampede on the origin: when LoadFromRedis finds the Redis key missing, it loads the
+// dataset from store into memory and writes it back to Redis, before falling back to the
+// loader (WithLoader) or the EmptyRemotePolicy if store is empty too. Every dataset
+// written to Redis is copied to store in the background, coalesced like write-behind
+// (see WriteBehindConfig for the defaults); failures reach the WithErrorHandler handler
+// as TaskColdStore. The dataset is encoded with RedisConfig.Codec. Close drains the copy.
+func (c *HybridCache[V]) WithColdStore(store ColdStore) *HybridCache[V] {
+	if c.cold != nil {
+		_ = c.cold.close()
+	}
+	c.coldStore = store
+	c.cold = newWriteBehind(WriteBehindConfig{}, c.storeCold, func(err error) { c.reportError(TaskColdStore, err) })
+	return c
+}
+
+// storeCold writes values to the cold store.
+func (c *HybridCache[V]) storeCold(values []V) error {
+	data, err := c.codec().Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	if err := c.coldStore.Store(context.Background(), data); err != nil {
+		return fmt.Errorf("failed to store cold copy: %w", err)
+	}
+	return nil
+}
+
+// loadCold reads the dataset from the cold store; found is false if none is set or stored.
+func (c *HybridCache[V]) loadCold(ctx context.Context) (values []V, found bool, err error) {
+	if c.coldStore == nil {
+		return nil, false, nil
+	}
+	data, found, err := c.coldStore.Load(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load cold copy: %w", err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	values, err = c.decode(data)
+	return values, err == nil, err
+}
+
+// FileColdStore is a ColdStore keeping the dataset in a local file, e.g. on a persistent
+// volume. Store writes a temporary file next to it and renames it into place, so readers
+// never see a partial dataset.
+type FileColdStore struct {
+	Path string
+}
+
+// Load implements ColdStore.
+func (s FileColdStore) Load(ctx context.Context) ([]byte, bool, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Store implements ColdStore.
+func (s FileColdStore) Store(ctx context.Context, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.Path)
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestHybridCache_ColdStore(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	store := FileColdStore{Path: filepath.Join(t.TempDir(), "users.json")}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithColdStore(store)
+
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool {
+		_, found, _ := store.Load(context.Background())
+		return found
+	})
+
+	// A Redis flush: a fresh instance loads from the cold tier and repopulates Redis.
+	mr.FlushAll()
+	loads := 0
+	fresh := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithColdStore(store).
+		WithLoader(func(context.Context) ([]TestUser, error) {
+			loads++
+			return nil, nil
+		})
+	defer fresh.Close(context.Background())
+	if err := fresh.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if got := idsOf(fresh.GetAll()); got != "1,2" {
+		t.Errorf("Expected the cold copy in memory, got %s", got)
+	}
+	if remote, _ := fresh.Redis().Get(); idsOf(remote) != "1,2" {
+		t.Errorf("Expected the cold copy written back to Redis, got %s", idsOf(remote))
+	}
+	if loads != 0 {
+		t.Errorf("Expected the origin not to be consulted, got %d loads", loads)
+	}
+}
+
+func TestFileColdStore(t *testing.T) {
+	ctx := context.Background()
+	store := FileColdStore{Path: filepath.Join(t.TempDir(), "data")}
+	if _, found, err := store.Load(ctx); found || err != nil {
+		t.Fatalf("Expected nothing stored, got found=%v err=%v", found, err)
+	}
+	if err := store.Store(ctx, []byte("v1")); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	if err := store.Store(ctx, []byte("v2")); err != nil {
+		t.Fatalf("Store error: %v", err)
+	}
+	data, found, err := store.Load(ctx)
+	if err != nil || !found || string(data) != "v2" {
+		t.Errorf("Expected v2, got %q found=%v err=%v", data, found, err)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(filepath.Dir(store.Path), "*.tmp")); len(entries) != 0 {
+		t.Errorf("Expected no temporary files left, got %v", entries)
+	}
+}
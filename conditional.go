@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// setIfVersionScript stores a payload and bumps the version only if the version key still
+// holds the expected value (a missing key counts as 0). With the envelope flag set, the payload
+// is wrapped like writeEnvelopeScript does.
+// KEYS: data, version. ARGV: expected version, ttl in milliseconds, envelope flag, payload.
+// Returns the new version, or -1 on mismatch.
+var setIfVersionScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current ~= tonumber(ARGV[1]) then
+  return -1
+end
+local v = redis.call('INCR', KEYS[2])
+local data = ARGV[4]
+if ARGV[3] == '1' then
+  data = 'ckv1:' .. v .. ':' .. data
+end
+redis.call('SET', KEYS[1], data, 'PX', ARGV[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return v
+`)
+
+// setItemsIfVersionScript is setIfVersionScript for hash storage mode: it replaces the hash
+// with the given fields. KEYS: data, version. ARGV: expected version, ttl in milliseconds,
+// then alternating field names and values. Returns the new version, or -1 on mismatch.
+var setItemsIfVersionScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current ~= tonumber(ARGV[1]) then
+  return -1
+end
+redis.call('DEL', KEYS[1])
+for i = 3, #ARGV, 2 do
+  redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+if #ARGV > 2 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+local v = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return v
+`)
+
+// SetIfVersion stores values like Set, but only if the stored version still equals
+// expectedVersion (0 for a key that was never written), typically the version returned by
+// GetWithVersion before building values. Reports false, leaving Redis unchanged, when another
+// writer got there first, so out-of-order refreshes cannot overwrite a newer dataset.
+// Check and write happen atomically in a Lua script.
+func (c *RedisCache[V]) SetIfVersion(values []V, expectedVersion int64) (bool, error) {
+	return c.SetIfVersionCtx(context.Background(), values, expectedVersion)
+}
+
+// SetIfVersionCtx is like SetIfVersion but uses ctx for the Redis calls.
+func (c *RedisCache[V]) SetIfVersionCtx(ctx context.Context, values []V, expectedVersion int64) (bool, error) {
+	return c.setIfVersion(ctx, values, expectedVersion, c.setTTL(values))
+}
+
+// setIfVersion implements SetIfVersionCtx with the given TTL (see effectiveTTL).
+func (c *RedisCache[V]) setIfVersion(ctx context.Context, values []V, expectedVersion int64, ttl time.Duration) (_ bool, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpSetIfVersion, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl = c.effectiveTTL(ttl)
+	keys := []string{c.key, c.versionKey()}
+	var version int64
+	switch c.storage {
+	case storageHash:
+		fields, err := c.encodeItems(values)
+		if err != nil {
+			return false, err
+		}
+		for i := 1; i < len(fields); i += 2 {
+			size += len(fields[i].([]byte))
+		}
+		args := append([]any{expectedVersion, ttl.Milliseconds()}, fields...)
+		version, err = setItemsIfVersionScript.Run(ctx, c.client, keys, args...).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to set cache: %w", err)
+		}
+	case storageList:
+		entries, n, err := c.encodeEntries(values)
+		if err != nil {
+			return false, err
+		}
+		size = n
+		args := append([]any{expectedVersion, ttl.Milliseconds()}, entries...)
+		version, err = setListIfVersionScript.Run(ctx, c.client, keys, args...).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to set cache: %w", err)
+		}
+	case storageJSON:
+		data, err := c.encodeDocument(values)
+		if err != nil {
+			return false, err
+		}
+		size = len(data)
+		version, err = setJSONIfVersionScript.Run(ctx, c.client, keys,
+			expectedVersion, ttl.Milliseconds(), data).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to set cache: %w", err)
+		}
+	case storageBlueGreen:
+		version, size, err = c.writeGeneration(ctx, values, ttl, true, true, expectedVersion)
+		if err != nil {
+			return false, err
+		}
+	default:
+		data, err := c.encode(values)
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal values: %w", err)
+		}
+		size = len(data)
+		envelope := "0"
+		if c.config.VersionedEnvelope {
+			envelope = "1"
+		}
+		version, err = setIfVersionScript.Run(ctx, c.client, keys,
+			expectedVersion, ttl.Milliseconds(), envelope, data).Int64()
+		if err != nil {
+			return false, fmt.Errorf("failed to set cache: %w", err)
+		}
+	}
+	if version < 0 {
+		return false, nil
+	}
+
+	return true, c.writeDerived(ctx, values, ttl)
+}
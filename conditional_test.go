@@ -0,0 +1,64 @@
+package cache
+
+import "testing"
+
+func TestRedisCache_SetIfVersion(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	ok, err := cache.SetIfVersion([]TestUser{{ID: "1"}}, 0)
+	if err != nil || !ok {
+		t.Fatalf("Expected first write at version 0 to succeed, got %v (%v)", ok, err)
+	}
+
+	// A second writer that read version 0 lost the race.
+	ok, err = cache.SetIfVersion([]TestUser{{ID: "stale"}}, 0)
+	if err != nil || ok {
+		t.Fatalf("Expected stale write to be skipped, got %v (%v)", ok, err)
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil || version != 1 || len(values) != 1 || values[0].ID != "1" {
+		t.Errorf("Expected data unchanged at version 1, got %+v at %d (%v)", values, version, err)
+	}
+
+	ok, err = cache.SetIfVersion([]TestUser{{ID: "2"}}, 1)
+	if err != nil || !ok {
+		t.Fatalf("Expected write at current version to succeed, got %v (%v)", ok, err)
+	}
+	if v, _ := cache.GetVersion(); v != 2 {
+		t.Errorf("Expected version 2, got %d", v)
+	}
+}
+
+func TestRedisCache_SetIfVersionEnvelope(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithVersionedEnvelope(true))
+
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "1"}}, 0); err != nil || !ok {
+		t.Fatalf("SetIfVersion failed: %v (%v)", ok, err)
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil || version != 1 || len(values) != 1 {
+		t.Errorf("Expected enveloped data consistent with version 1, got %+v at %d (%v)", values, version, err)
+	}
+}
+
+func TestRedisCache_SetIfVersionHashStorage(t *testing.T) {
+	cache, fields := newHashCache(t, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "3"}}, 0); err != nil || ok {
+		t.Errorf("Expected mismatch to be skipped, got %v (%v)", ok, err)
+	}
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "3"}}, 1); err != nil || !ok {
+		t.Fatalf("Expected write at current version, got %v (%v)", ok, err)
+	}
+	if got, _ := fields(); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Expected hash replaced with field 3, got %v", got)
+	}
+	if v, _ := cache.GetVersion(); v != 2 {
+		t.Errorf("Expected version 2, got %d", v)
+	}
+}
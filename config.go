@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"sort"
 	"strings"
 	"time"
@@ -15,6 +16,10 @@ type Config[V any] struct {
 	// This is required for multi-index cache.
 	PrimaryKeyFunc KeyFunc[V]
 
+	// Indexes are registered by NewMultiIndexCache (and NewHybridCache) at construction,
+	// in order, so they exist before the first Set. Add them with WithIndex.
+	Indexes []IndexSpec[V]
+
 	// HashFunc computes a hash for the cache contents.
 	// If nil, defaultHashFunc is used, which serializes each value with fmt.Sprintf("%v", v).
 	// Warning: the default is not suitable for types containing sensitive fields (passwords, tokens),
@@ -34,6 +39,66 @@ type Config[V any] struct {
 	// SortFunc is used for deterministic hash calculation.
 	// If nil, values are hashed in insertion order.
 	SortFunc func(values []V) []V
+
+	// TagsFunc returns the tags of a value, used to group logically related entries
+	// (e.g. all records of one tenant) so they can be evicted together with InvalidateByTag.
+	// If nil, values are untagged.
+	TagsFunc func(value V) []string
+
+	// SetTimingsFunc, if set, is called after every Set with the duration of each phase
+	// (normalize, validate, index, sort, hash), so slow custom SortFunc or HashFunc are visible.
+	// Phases are only timed when this is set. It is called without the cache lock held.
+	SetTimingsFunc func(timings SetTimings)
+
+	// IndexColdAfter classifies an index as cold once it has not been queried for this long
+	// (see MemoryCache.IndexUsageReport). If <= 0, indexes are never considered cold and
+	// GetByIndex does not track usage.
+	IndexColdAfter time.Duration
+
+	// AutoDropColdIndexes removes cold indexes whenever a Set completes.
+	// Requires IndexColdAfter to be positive.
+	AutoDropColdIndexes bool
+
+	// ArenaStorage stores values serialized in large byte arenas instead of as Go values.
+	// This greatly reduces the number of heap pointers (and GC scan time) for caches holding
+	// millions of small structs, at the cost of decoding a value on every read.
+	// Only fields preserved by ArenaCodec survive a round trip (exported fields for JSON).
+	ArenaStorage bool
+
+	// ArenaCodec encodes values when ArenaStorage is enabled.
+	// If nil, JSONCodec is used.
+	ArenaCodec Codec
+
+	// ExpectedSize pre-sizes the data map, order slice and every index of an empty cache
+	// (on creation, AddIndex and Clear), avoiding repeated growth while a known-size dataset
+	// is loaded. Set always sizes for the values it is given. If <= 0, no capacity is reserved.
+	ExpectedSize int
+
+	// MemoryPressureFunc reports whether the process is close to its memory limit
+	// (see MemoryLimitPressure). It is checked by MemoryCache.RelievePressure.
+	// If nil, the cache never reacts to memory pressure.
+	MemoryPressureFunc func() bool
+
+	// PressureEvictStaleAfter lets RelievePressure evict entries not written by the last
+	// PressureEvictStaleAfter Set or Upsert calls (see MemoryCache.StaleEntries).
+	// If <= 0, entries are never evicted under pressure.
+	PressureEvictStaleAfter int
+	// SnapshotRetention keeps the last SnapshotRetention states of the dataset (one per
+	// modification) for time-travel reads with MemoryCache.AtTime and AtHash. Snapshots share
+	// storage with the cache, but each retained state keeps its data alive, so memory grows
+	// with the number of distinct datasets retained. If <= 0, nothing is retained.
+	SnapshotRetention int
+
+	// Logger, if set, receives warnings about recoverable problems: values Set or Upsert
+	// skipped (rejected by ValidateFunc, or without a primary key) and entries evicted
+	// under memory pressure. If nil, they pass silently.
+	Logger *slog.Logger
+}
+
+// IndexSpec names an index and its key extraction function (see Config.WithIndex).
+type IndexSpec[V any] struct {
+	Name    string
+	KeyFunc KeyFunc[V]
 }
 
 // DefaultConfig returns a default configuration.
@@ -50,6 +115,13 @@ func (c *Config[V]) WithPrimaryKey(fn KeyFunc[V]) *Config[V] {
 	return c
 }
 
+// WithIndex adds an index registered when the cache is constructed.
+// A later WithIndex (or AddIndex) with the same name replaces it.
+func (c *Config[V]) WithIndex(name string, keyFunc KeyFunc[V]) *Config[V] {
+	c.Indexes = append(c.Indexes, IndexSpec[V]{Name: name, KeyFunc: keyFunc})
+	return c
+}
+
 // WithHashFunc sets a custom hash function.
 func (c *Config[V]) WithHashFunc(fn HashFunc[V]) *Config[V] {
 	c.HashFunc = fn
@@ -74,6 +146,60 @@ func (c *Config[V]) WithSortFunc(fn func(values []V) []V) *Config[V] {
 	return c
 }
 
+// WithTagsFunc sets the function returning the tags of a value.
+func (c *Config[V]) WithTagsFunc(fn func(value V) []string) *Config[V] {
+	c.TagsFunc = fn
+	return c
+}
+
+// WithSetTimingsFunc sets the callback receiving per-phase Set timings.
+func (c *Config[V]) WithSetTimingsFunc(fn func(timings SetTimings)) *Config[V] {
+	c.SetTimingsFunc = fn
+	return c
+}
+
+// WithColdIndexPolicy sets the idle duration after which an index is cold and,
+// if autoDrop is true, removed automatically once the next Set succeeds.
+func (c *Config[V]) WithColdIndexPolicy(after time.Duration, autoDrop bool) *Config[V] {
+	c.IndexColdAfter = after
+	c.AutoDropColdIndexes = autoDrop
+	return c
+}
+
+// WithExpectedSize sets the capacity hint used for an empty cache's maps and slices.
+func (c *Config[V]) WithExpectedSize(n int) *Config[V] {
+	c.ExpectedSize = n
+	return c
+}
+
+// WithMemoryPressure sets the memory-pressure signal and how old (in generations) an entry
+// must be to be evicted under pressure; evictStaleAfter <= 0 disables eviction.
+func (c *Config[V]) WithMemoryPressure(pressure func() bool, evictStaleAfter int) *Config[V] {
+	c.MemoryPressureFunc = pressure
+	c.PressureEvictStaleAfter = evictStaleAfter
+	return c
+}
+
+// WithSnapshotRetention sets how many past dataset states are retained. See Config.SnapshotRetention.
+func (c *Config[V]) WithSnapshotRetention(n int) *Config[V] {
+	c.SnapshotRetention = n
+	return c
+}
+
+// WithLogger sets the logger for warnings. See Config.Logger.
+func (c *Config[V]) WithLogger(logger *slog.Logger) *Config[V] {
+	c.Logger = logger
+	return c
+}
+
+// WithArenaStorage enables GC-friendly arena storage using the given codec.
+// If codec is nil, JSONCodec is used. See Config.ArenaStorage for the trade-offs.
+func (c *Config[V]) WithArenaStorage(codec Codec) *Config[V] {
+	c.ArenaStorage = true
+	c.ArenaCodec = codec
+	return c
+}
+
 // defaultHashFunc provides a simple hash implementation using fmt.Sprintf("%v", v) per value.
 // See Config.HashFunc documentation for sensitivity and determinism caveats.
 func defaultHashFunc[V any](values []V) string {
@@ -109,12 +235,118 @@ type RedisConfig struct {
 	TTL time.Duration
 
 	// OperationTimeout is the timeout for Redis operations.
+	// The *Ctx methods apply it only when the caller's context has no deadline.
 	// Default: 5 seconds
 	OperationTimeout time.Duration
 
 	// MaxValueBytes limits the size of the value read from Redis in Get(). If <= 0, no limit is applied.
+	// In hash storage mode the fields are summed server-side before any is downloaded.
 	// Default: 16MB. Prevents OOM from malicious or corrupted oversized values in Redis.
 	MaxValueBytes int
+
+	// VersionedEnvelope wraps the stored payload in an envelope recording the version it was
+	// written with (data and version are updated atomically by a Lua script). Get then verifies
+	// that the data and version keys agree and returns a *VersionMismatchError if they don't.
+	// Default: false (payload stored as plain JSON, readable by non-Go consumers).
+	VersionedEnvelope bool
+
+	// AtomicWrites makes full writes in the default storage mode store the data and bump the
+	// version in one MULTI/EXEC transaction, so concurrent writers cannot interleave and leave
+	// a version pointing at the other writer's data. VersionedEnvelope writes, and the hash,
+	// list and JSON storage modes, are atomic regardless. On a cluster both keys must share
+	// a slot (see ClusterHashTag).
+	// Default: false (data and version are pipelined, not transactional)
+	AtomicWrites bool
+
+	// PublishMetadata writes a metadata hash next to the data key on every Set (write time and
+	// item count), so fleet-wide dashboards can alert on dataset age (see RedisCache.Metadata).
+	// Default: false
+	PublishMetadata bool
+
+	// ClusterHashTag wraps the key base in a Redis Cluster hash tag (e.g. "{cache:data}"), so
+	// the data, version and metadata keys land in the same slot and pipelines, transactions and
+	// Lua scripts touching several of them work on a cluster. Keys that already contain a
+	// {tag} are left as-is. Changing this renames the keys.
+	// Default: false
+	ClusterHashTag bool
+
+	// ChangePollInterval is how often RedisCache.Subscribe polls the version key.
+	// Default: 1 second
+	ChangePollInterval time.Duration
+
+	// Codec encodes the stored []V payload. All readers and writers of a key must use the
+	// same codec.
+	// Default: JSONCodec
+	Codec Codec
+
+	// InvalidationChannel is the pub/sub channel RedisCache.InvalidationSubscriber listens on.
+	// Default: keyspace notifications for the data and version keys
+	InvalidationChannel string
+
+	// MetricsHook, if set, observes every RedisCache operation (see the Op constants).
+	// Default: nil (no metrics)
+	MetricsHook MetricsHook
+
+	// Logger, if set, receives warnings about recoverable problems: values hash storage
+	// skips for lacking a primary key, and the background failures of a HybridCache (see
+	// HybridCache.WithErrorHandler).
+	// Default: nil (silent)
+	Logger *slog.Logger
+
+	// StaleTTL, if positive, makes full writes (Set, SetWithTTL, SetIfVersion) also keep a
+	// backup copy of the dataset that lives for StaleTTL, for RedisCache.GetStale to serve
+	// after the primary key expired. Should exceed TTL.
+	// Default: 0 (no backup copy)
+	StaleTTL time.Duration
+
+	// EmptyTTL, if positive, enables negative caching: Set and SetIfVersion store an empty
+	// dataset as a distinct marker that expires after EmptyTTL (typically shorter than TTL),
+	// and Get and GetWithVersion return ErrRemoteEmpty for a missing key, so callers can tell
+	// "cached as empty" from "not cached". Readers without EmptyTTL decode the marker as empty.
+	// Default: 0 (empty datasets are stored like any other and a missing key reads as empty)
+	EmptyTTL time.Duration
+
+	// SoftTTL, if positive, is how long after a full write (Set, SetWithTTL, SetIfVersion)
+	// the data counts as stale, see RedisCache.IsSoftExpired; TTL stays the hard expiry
+	// after which Redis drops it. Should be shorter than TTL.
+	// Default: 0 (no soft expiry)
+	SoftTTL time.Duration
+
+	// SlidingTTL makes every Get and GetWithVersion that finds data (including HybridCache
+	// loads) reset the TTL of the data key and the keys sharing it to TTL, so datasets that
+	// are read keep living while idle ones expire. Costs one extra round trip per read.
+	// Default: false (the TTL only resets on writes and Refresh)
+	SlidingTTL bool
+
+	// ContentHash makes full writes (Set, SetWithTTL, SetIfVersion) also store a hash of the
+	// dataset, for RedisCache.GetIfHashDiffers. Per-item writes drop it.
+	// Default: false
+	ContentHash bool
+
+	// KeyBuilder, if set, names the keys of the cache instead of KeyPrefix and
+	// VersionKeySuffix, e.g. SegmentedKeys for a structured naming convention.
+	// Default: nil (keys are derived from KeyPrefix)
+	KeyBuilder KeyBuilder
+
+	// HealthCheckKeys makes RedisCache.Ping also check that the data and version keys are
+	// readable and hold the types the storage mode expects (missing keys pass).
+	// Default: false (Ping only checks connectivity)
+	HealthCheckKeys bool
+
+	// HashLongKeys makes NewRedisCache shorten key names that would exceed 512 bytes instead
+	// of panicking: the key base (KeyPrefix, the key of NewRedisCacheWithKey or the
+	// KeyBuilder Prefix) is replaced by its first 64 bytes and its SHA-256, e.g.
+	// "tenant:acme-…~3f2a…:data", so dynamically generated names cannot crash the process.
+	// Keys within the limit are never renamed.
+	// Default: false (over-long keys panic)
+	HashLongKeys bool
+
+	// RateLimiter, if set, is consulted before every RedisCache operation; rejected
+	// operations fail fast with ErrRateLimited instead of queueing, so a misbehaving refresh
+	// loop cannot saturate a shared Redis. Operations built from others (e.g.
+	// GetIfHashDiffers) consult it once per part; Ping and WaitForRebuild are not limited.
+	// Default: nil (no limit)
+	RateLimiter RateLimiter
 }
 
 // Default max value size for Redis Get (16 MiB).
@@ -162,6 +394,116 @@ func (c *RedisConfig) WithMaxValueBytes(n int) *RedisConfig {
 	return c
 }
 
+// WithVersionedEnvelope enables or disables the versioned payload envelope.
+// See RedisConfig.VersionedEnvelope.
+func (c *RedisConfig) WithVersionedEnvelope(enabled bool) *RedisConfig {
+	c.VersionedEnvelope = enabled
+	return c
+}
+
+// WithAtomicWrites enables or disables transactional writes. See RedisConfig.AtomicWrites.
+func (c *RedisConfig) WithAtomicWrites(enabled bool) *RedisConfig {
+	c.AtomicWrites = enabled
+	return c
+}
+
+// WithPublishMetadata enables or disables the metadata hash. See RedisConfig.PublishMetadata.
+func (c *RedisConfig) WithPublishMetadata(enabled bool) *RedisConfig {
+	c.PublishMetadata = enabled
+	return c
+}
+
+// WithClusterHashTag enables or disables hash-tagged keys. See RedisConfig.ClusterHashTag.
+func (c *RedisConfig) WithClusterHashTag(enabled bool) *RedisConfig {
+	c.ClusterHashTag = enabled
+	return c
+}
+
+// WithChangePollInterval sets how often RedisCache.Subscribe polls for changes.
+func (c *RedisConfig) WithChangePollInterval(interval time.Duration) *RedisConfig {
+	c.ChangePollInterval = interval
+	return c
+}
+
+// WithCodec sets the payload codec. See RedisConfig.Codec.
+func (c *RedisConfig) WithCodec(codec Codec) *RedisConfig {
+	c.Codec = codec
+	return c
+}
+
+// WithInvalidationChannel sets the pub/sub channel used for invalidation messages.
+func (c *RedisConfig) WithInvalidationChannel(channel string) *RedisConfig {
+	c.InvalidationChannel = channel
+	return c
+}
+
+// WithMetricsHook sets the hook observing Redis operations. See RedisConfig.MetricsHook.
+func (c *RedisConfig) WithMetricsHook(hook MetricsHook) *RedisConfig {
+	c.MetricsHook = hook
+	return c
+}
+
+// WithLogger sets the logger for warnings. See RedisConfig.Logger.
+func (c *RedisConfig) WithLogger(logger *slog.Logger) *RedisConfig {
+	c.Logger = logger
+	return c
+}
+
+// WithStaleTTL enables the stale backup copy. See RedisConfig.StaleTTL.
+func (c *RedisConfig) WithStaleTTL(ttl time.Duration) *RedisConfig {
+	c.StaleTTL = ttl
+	return c
+}
+
+// WithEmptyTTL enables negative caching of empty datasets. See RedisConfig.EmptyTTL.
+func (c *RedisConfig) WithEmptyTTL(ttl time.Duration) *RedisConfig {
+	c.EmptyTTL = ttl
+	return c
+}
+
+// WithSoftTTL sets the soft expiry. See RedisConfig.SoftTTL.
+func (c *RedisConfig) WithSoftTTL(ttl time.Duration) *RedisConfig {
+	c.SoftTTL = ttl
+	return c
+}
+
+// WithSlidingTTL enables sliding expiration on read. See RedisConfig.SlidingTTL.
+func (c *RedisConfig) WithSlidingTTL(enabled bool) *RedisConfig {
+	c.SlidingTTL = enabled
+	return c
+}
+
+// WithContentHash enables storing the content hash. See RedisConfig.ContentHash.
+func (c *RedisConfig) WithContentHash(enabled bool) *RedisConfig {
+	c.ContentHash = enabled
+	return c
+}
+
+// WithKeyBuilder sets the key naming scheme. See RedisConfig.KeyBuilder.
+func (c *RedisConfig) WithKeyBuilder(builder KeyBuilder) *RedisConfig {
+	c.KeyBuilder = builder
+	return c
+}
+
+// WithHashLongKeys enables shortening over-long key names. See RedisConfig.HashLongKeys.
+func (c *RedisConfig) WithHashLongKeys(enabled bool) *RedisConfig {
+	c.HashLongKeys = enabled
+	return c
+}
+
+// WithRateLimiter sets the operation rate limiter, e.g. NewTokenBucket(100, 20).
+// See RedisConfig.RateLimiter.
+func (c *RedisConfig) WithRateLimiter(limiter RateLimiter) *RedisConfig {
+	c.RateLimiter = limiter
+	return c
+}
+
+// WithHealthCheckKeys enables the key checks of RedisCache.Ping. See RedisConfig.HealthCheckKeys.
+func (c *RedisConfig) WithHealthCheckKeys(enabled bool) *RedisConfig {
+	c.HealthCheckKeys = enabled
+	return c
+}
+
 // StringSorter provides a helper for sorting slices by a string key.
 func StringSorter[V any](keyFunc func(V) string) func([]V) []V {
 	return func(values []V) []V {
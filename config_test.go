@@ -217,3 +217,123 @@ func TestStringSorter(t *testing.T) {
 		}
 	}
 }
+
+func TestConfig_WithArenaStorage(t *testing.T) {
+	config := DefaultConfig[TestUser]()
+	if config.ArenaStorage {
+		t.Error("Expected arena storage disabled by default")
+	}
+
+	config.WithArenaStorage(nil)
+	if !config.ArenaStorage {
+		t.Error("Expected arena storage enabled")
+	}
+	if config.ArenaCodec != nil {
+		t.Error("Expected nil codec to be kept (JSON used at runtime)")
+	}
+
+	config.WithArenaStorage(JSONCodec{})
+	if config.ArenaCodec == nil {
+		t.Error("Expected codec to be set")
+	}
+}
+
+func TestConfig_WithTagsFunc(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithTagsFunc(func(u TestUser) []string { return []string{u.Name} })
+
+	if config.TagsFunc == nil {
+		t.Fatal("Expected tags function to be set")
+	}
+	if tags := config.TagsFunc(TestUser{Name: "x"}); len(tags) != 1 || tags[0] != "x" {
+		t.Errorf("Expected [x], got %v", tags)
+	}
+}
+
+func TestRedisConfig_WithVersionedEnvelope(t *testing.T) {
+	config := DefaultRedisConfig()
+	if config.VersionedEnvelope {
+		t.Error("Expected versioned envelope disabled by default")
+	}
+	if !config.WithVersionedEnvelope(true).VersionedEnvelope {
+		t.Error("Expected versioned envelope enabled")
+	}
+}
+
+func TestConfig_WithExpectedSize(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithExpectedSize(1000)
+	if config.ExpectedSize != 1000 {
+		t.Errorf("Expected ExpectedSize 1000, got %d", config.ExpectedSize)
+	}
+
+	cache := NewMultiIndexCache(config)
+	if cap(cache.order) < 1000 {
+		t.Errorf("Expected order capacity >= 1000, got %d", cap(cache.order))
+	}
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	if _, ok := cache.GetByIndex("email", "a@example.com"); !ok {
+		t.Error("Expected lookup to work with a capacity hint")
+	}
+
+	cache.Clear()
+	if cap(cache.order) < 1000 {
+		t.Errorf("Expected order capacity >= 1000 after Clear, got %d", cap(cache.order))
+	}
+
+	// Negative hints are ignored.
+	if c := NewMultiIndexCache(DefaultConfig[TestUser]().WithExpectedSize(-1)); cap(c.order) != 0 {
+		t.Errorf("Expected no capacity for negative hint, got %d", cap(c.order))
+	}
+}
+
+func TestConfig_WithIndex(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email }).
+		WithIndex("phone", func(u TestUser) string { return u.Phone })
+
+	if len(config.Indexes) != 2 || config.Indexes[0].Name != "email" {
+		t.Fatalf("Expected 2 index specs, got %+v", config.Indexes)
+	}
+
+	cache := NewMultiIndexCache(config)
+	if !cache.HasIndex("email") || !cache.HasIndex("phone") {
+		t.Error("Expected config indexes registered at construction")
+	}
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com", Phone: "123"}})
+	if _, ok := cache.GetByIndex("phone", "123"); !ok {
+		t.Error("Expected lookup through a config index")
+	}
+
+	hybrid := NewHybridCache[TestUser](config, nil, nil)
+	if !hybrid.Memory().HasIndex("email") {
+		t.Error("Expected HybridCache to register config indexes")
+	}
+}
+
+func TestRedisConfig_WithInvalidationChannel(t *testing.T) {
+	config := DefaultRedisConfig()
+	if config.InvalidationChannel != "" {
+		t.Error("Expected no invalidation channel by default")
+	}
+	if got := config.WithInvalidationChannel("users:changed").InvalidationChannel; got != "users:changed" {
+		t.Errorf("Expected channel users:changed, got %q", got)
+	}
+}
+
+func TestRedisConfig_WithMetricsHook(t *testing.T) {
+	var observed string
+	config := DefaultRedisConfig().WithMetricsHook(MetricsHookFunc(func(op string, _ time.Duration, _ int, _ error) {
+		observed = op
+	}))
+	if config.MetricsHook == nil {
+		t.Fatal("Expected metrics hook to be set")
+	}
+	config.MetricsHook.ObserveOp(OpGet, 0, 0, nil)
+	if observed != OpGet {
+		t.Errorf("Expected hook to be called with %s, got %q", OpGet, observed)
+	}
+}
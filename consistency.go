@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ConsistencyReport is the result of HybridCache.ConsistencyCheck.
+type ConsistencyReport struct {
+	// Consistent reports whether memory and Redis hold the same values.
+	Consistent bool
+
+	// MemoryItems and RedisItems are the number of values in each tier. RedisItems is
+	// MemoryItems when the stored content hash matched and the dataset was not read.
+	MemoryItems int
+	RedisItems  int
+
+	// MemoryHash is the content hash of the memory values (the hex SHA-256 of their codec
+	// encoding, as stored by RedisConfig.ContentHash); RedisHash is the stored hash, or
+	// the same hash of the Redis values when none is stored.
+	MemoryHash string
+	RedisHash  string
+
+	// Missing lists the primary keys held by Redis but not memory, Extra those held by
+	// memory only, and Changed those whose values differ, each sorted.
+	Missing []string
+	Extra   []string
+	Changed []string
+}
+
+// ConsistencyCheck compares the memory tier with Redis to detect drift, e.g. after a Set
+// whose Redis write failed. With RedisConfig.ContentHash, a stored hash equal to the
+// memory hash settles the check without reading the dataset; otherwise the dataset is
+// read and compared value by value, by primary key, so the order values were written in
+// does not matter. Repair drift with LoadFromRedis (Redis wins) or SyncToRedis (memory
+// wins).
+func (c *HybridCache[V]) ConsistencyCheck(ctx context.Context) (ConsistencyReport, error) {
+	local := c.memory.GetAll()
+	report := ConsistencyReport{MemoryItems: len(local)}
+	hash, err := hashValues(c.codec(), local)
+	if err != nil {
+		return report, fmt.Errorf("failed to hash memory values: %w", err)
+	}
+	report.MemoryHash = hash
+
+	if c.redis != nil && c.redis.config.ContentHash {
+		if err := c.redis.admit(); err != nil {
+			return report, err
+		}
+		hashCtx, cancel := c.redis.getContext(ctx)
+		stored, err := c.redis.client.Get(hashCtx, c.redis.contentHashKey()).Result()
+		cancel()
+		if err != nil && err != redis.Nil {
+			return report, fmt.Errorf("failed to get content hash: %w", err)
+		}
+		report.RedisHash = stored
+		if stored == hash {
+			report.Consistent = true
+			report.RedisItems = len(local)
+			return report, nil
+		}
+	}
+
+	remote, _, _, err := c.fetchRemote(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.RedisItems = len(remote)
+	if report.RedisHash == "" {
+		if report.RedisHash, err = hashValues(c.codec(), remote); err != nil {
+			return report, fmt.Errorf("failed to hash Redis values: %w", err)
+		}
+	}
+
+	localItems, err := c.encodeByKey(local)
+	if err != nil {
+		return report, err
+	}
+	remoteItems, err := c.encodeByKey(remote)
+	if err != nil {
+		return report, err
+	}
+	for pk, data := range remoteItems {
+		localData, ok := localItems[pk]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, pk)
+		case !bytes.Equal(localData, data):
+			report.Changed = append(report.Changed, pk)
+		}
+	}
+	for pk := range localItems {
+		if _, ok := remoteItems[pk]; !ok {
+			report.Extra = append(report.Extra, pk)
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Extra)
+	sort.Strings(report.Changed)
+	report.Consistent = len(report.Missing) == 0 && len(report.Extra) == 0 && len(report.Changed) == 0
+	return report, nil
+}
+
+// encodeByKey encodes each value with the Redis codec, keyed by its primary key.
+func (c *HybridCache[V]) encodeByKey(values []V) (map[string][]byte, error) {
+	items := make(map[string][]byte, len(values))
+	for _, v := range values {
+		data, err := c.codec().Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value: %w", err)
+		}
+		items[c.memory.config.PrimaryKeyFunc(v)] = data
+	}
+	return items, nil
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestHybridCache_ConsistencyCheck(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	ctx := context.Background()
+
+	if err := cache.Set([]TestUser{{ID: "1", Name: "a"}, {ID: "2", Name: "b"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	report, err := cache.ConsistencyCheck(ctx)
+	if err != nil {
+		t.Fatalf("ConsistencyCheck error: %v", err)
+	}
+	if !report.Consistent || report.MemoryItems != 2 || report.RedisItems != 2 || report.MemoryHash != report.RedisHash {
+		t.Errorf("Expected a consistent report, got %+v", report)
+	}
+
+	// Drift: memory changed without reaching Redis.
+	cache.Memory().Set([]TestUser{{ID: "2", Name: "changed"}, {ID: "3"}})
+	report, err = cache.ConsistencyCheck(ctx)
+	if err != nil {
+		t.Fatalf("ConsistencyCheck error: %v", err)
+	}
+	if report.Consistent || report.MemoryHash == report.RedisHash {
+		t.Errorf("Expected drift to be reported, got %+v", report)
+	}
+	if !reflect.DeepEqual(report.Missing, []string{"1"}) || !reflect.DeepEqual(report.Extra, []string{"3"}) ||
+		!reflect.DeepEqual(report.Changed, []string{"2"}) {
+		t.Errorf("Unexpected differences: missing %v, extra %v, changed %v", report.Missing, report.Extra, report.Changed)
+	}
+
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if report, _ = cache.ConsistencyCheck(ctx); !report.Consistent {
+		t.Errorf("Expected LoadFromRedis to repair drift, got %+v", report)
+	}
+}
+
+func TestHybridCache_ConsistencyCheckStoredHash(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	config := DefaultRedisConfig().WithContentHash(true).WithMetricsHook(hook)
+	cache := NewHybridCache[TestUser](userConfig(), client, config)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	before := len(hook.ops)
+	report, err := cache.ConsistencyCheck(context.Background())
+	if err != nil {
+		t.Fatalf("ConsistencyCheck error: %v", err)
+	}
+	if !report.Consistent || report.RedisHash != report.MemoryHash {
+		t.Errorf("Expected the stored hash to match, got %+v", report)
+	}
+	if len(hook.ops) != before {
+		t.Error("Expected a matching stored hash to skip reading the dataset")
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// contentHashKeySuffix is appended to the data key to name the content hash of the dataset.
+const contentHashKeySuffix = ":hash"
+
+// contentHashKey returns the content hash key for this cache.
+func (c *RedisCache[V]) contentHashKey() string {
+	return c.key + contentHashKeySuffix
+}
+
+// contentHash returns the hex SHA-256 of values encoded with the codec, independent of the
+// storage mode.
+func (c *RedisCache[V]) contentHash(values []V) (string, error) {
+	return hashValues(c.codec(), values)
+}
+
+// hashValues returns the hex SHA-256 of values encoded with codec.
+func hashValues[V any](codec Codec, values []V) (string, error) {
+	if values == nil {
+		values = []V{}
+	}
+	data, err := codec.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GetIfHashDiffers returns the dataset only if its content hash differs from knownHash, the
+// hash returned by an earlier call, like an HTTP ETag: consumers tracking content rather
+// than versions skip transferring an unchanged payload. Requires RedisConfig.ContentHash on
+// the writers. changed is false, with nil values, when the stored hash equals knownHash.
+// Without a stored hash (not enabled, dropped by a per-item write, or nothing cached) the
+// dataset is always returned, with an empty hash. The hash is read before the data, so a
+// concurrent write can only make the next call fetch again, never skip a change.
+func (c *RedisCache[V]) GetIfHashDiffers(knownHash string) (values []V, hash string, changed bool, err error) {
+	return c.GetIfHashDiffersCtx(context.Background(), knownHash)
+}
+
+// GetIfHashDiffersCtx is like GetIfHashDiffers but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetIfHashDiffersCtx(ctx context.Context, knownHash string) (_ []V, _ string, _ bool, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpGetIfHashDiffers, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, "", false, err
+	}
+
+	hashCtx, cancel := c.getContext(ctx)
+	hash, err := c.client.Get(hashCtx, c.contentHashKey()).Result()
+	cancel()
+	if err != nil && err != redis.Nil {
+		return nil, "", false, fmt.Errorf("failed to get content hash: %w", err)
+	}
+	if hash != "" && hash == knownHash {
+		return nil, hash, false, nil
+	}
+
+	values, err := c.GetCtx(ctx)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return values, hash, true, nil
+}
@@ -0,0 +1,61 @@
+package cache
+
+import "testing"
+
+func TestRedisCache_GetIfHashDiffers(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithContentHash(true))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, hash, changed, err := cache.GetIfHashDiffers("")
+	if err != nil || !changed || idsOf(values) != "1" || hash == "" {
+		t.Fatalf("Expected 1 with a hash, got %s %q %v (%v)", idsOf(values), hash, changed, err)
+	}
+	values, again, changed, err := cache.GetIfHashDiffers(hash)
+	if err != nil || changed || values != nil || again != hash {
+		t.Errorf("Expected no transfer for an unchanged hash, got %+v %q %v (%v)", values, again, changed, err)
+	}
+
+	// Writing the same content keeps the hash even though the version changes.
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, _, changed, _ := cache.GetIfHashDiffers(hash); changed {
+		t.Error("Expected identical content to keep its hash")
+	}
+
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, newHash, changed, _ := cache.GetIfHashDiffers(hash)
+	if !changed || idsOf(values) != "2" || newHash == hash {
+		t.Errorf("Expected 2 with a new hash, got %s %q %v", idsOf(values), newHash, changed)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if mr.Exists(cache.contentHashKey()) {
+		t.Error("Expected Clear to remove the content hash")
+	}
+}
+
+func TestRedisCache_ContentHashDroppedByItemWrites(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithContentHash(true)).
+		WithHashStorage(func(u TestUser) string { return u.ID })
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	_, hash, _, _ := cache.GetIfHashDiffers("")
+	if err := cache.SetItem("2", TestUser{ID: "2"}); err != nil {
+		t.Fatalf("SetItem error: %v", err)
+	}
+	values, current, changed, err := cache.GetIfHashDiffers(hash)
+	if err != nil || !changed || idsOf(values) != "1,2" || current != "" {
+		t.Errorf("Expected 1,2 without a hash after SetItem, got %s %q %v (%v)", idsOf(values), current, changed, err)
+	}
+}
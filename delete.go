@@ -0,0 +1,101 @@
+package cache
+
+import "context"
+
+// Delete removes the value stored under pk from memory and Redis without a full Set of the
+// remaining dataset. In hash storage mode Redis deletes the field (see
+// RedisCache.DeleteItem); in the other modes the Redis dataset is read and written back
+// without the value under SetIfVersion, returning ErrVersionConflict if another writer
+// changed it in between. The version is bumped only if Redis held pk; a missing pk is not
+// an error. Delete follows the WritePolicy: WriteMemoryOnly leaves Redis alone,
+// WriteRedisOnly leaves memory alone, and write-behind queues the remaining memory dataset.
+func (c *HybridCache[V]) Delete(pk string) error {
+	if c.writePolicy == WriteMemoryOnly {
+		c.memory.Delete(pk)
+		return nil
+	}
+	var remaining []V // the memory dataset without pk; nil if memory is left alone
+	if c.writePolicy != WriteRedisOnly {
+		c.memory.Delete(pk)
+		remaining = c.memory.GetAll()
+		if c.writeBehind != nil && c.writeBehind.enqueue(remaining) {
+			return nil
+		}
+	}
+	return c.deleteRedis(context.Background(), pk, remaining)
+}
+
+// deleteRedis removes pk from the Redis dataset. In hash storage mode, remaining is the
+// dataset left after the delete, passed to afterRedisWrite; if nil, it is read back from
+// Redis.
+func (c *HybridCache[V]) deleteRedis(ctx context.Context, pk string, remaining []V) error {
+	if c.redis != nil && c.redis.storage == storageHash {
+		deleted, err := c.redis.DeleteItemCtx(ctx, pk)
+		if err != nil || !deleted {
+			return err
+		}
+		if remaining == nil {
+			if remaining, _, _, err = c.fetchRemote(ctx); err != nil {
+				return err
+			}
+		}
+		return c.afterRedisWrite(remaining)
+	}
+
+	values, version, _, err := c.fetchRemote(ctx)
+	if err != nil {
+		return err
+	}
+	remaining = make([]V, 0, len(values))
+	for _, v := range values {
+		if c.memory.config.PrimaryKeyFunc(v) != pk {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) == len(values) {
+		return nil
+	}
+	if c.redis == nil {
+		if err := c.remote.SetCtx(ctx, remaining); err != nil {
+			return err
+		}
+		return c.afterRedisWrite(remaining)
+	}
+	ok, err := c.redis.SetIfVersionCtx(ctx, remaining, version)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVersionConflict
+	}
+	return c.afterRedisWrite(remaining)
+}
+
+// Clear empties both tiers. Redis is cleared first (see RedisCache.ClearCtx: the dataset,
+// its version and derived keys, and persisted indexes) and memory only once that
+// succeeded, so a failed Clear returns the Redis error with memory still serving the old
+// dataset; Redis may then be partly cleared, and calling Clear again completes it. A
+// dataset queued by write-behind is discarded. Clear follows the WritePolicy like Delete:
+// WriteMemoryOnly clears memory only, WriteRedisOnly Redis only.
+func (c *HybridCache[V]) Clear(ctx context.Context) error {
+	if c.writePolicy != WriteMemoryOnly {
+		clearRedis := func() error { return c.remote.ClearCtx(ctx) }
+		var err error
+		if c.writeBehind != nil {
+			err = c.writeBehind.discard(clearRedis)
+		} else {
+			err = clearRedis()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if c.writePolicy != WriteRedisOnly {
+		c.memory.Clear()
+	}
+	if c.writePolicy == WriteMemoryOnly {
+		return nil
+	}
+	c.recordVersion(0)
+	return c.publishInvalidation()
+}
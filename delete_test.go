@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHybridCache_Delete(t *testing.T) {
+	for _, hashStorage := range []bool{false, true} {
+		_, client := setupMiniRedis(t)
+		cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+		if hashStorage {
+			cache.Redis().WithHashStorage(func(u TestUser) string { return u.ID })
+		}
+		if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+			t.Fatalf("Set error: %v", err)
+		}
+
+		if err := cache.Delete("1"); err != nil {
+			t.Fatalf("hash=%v: Delete error: %v", hashStorage, err)
+		}
+		if got := idsOf(cache.GetAll()); got != "2" {
+			t.Errorf("hash=%v: expected memory 2, got %s", hashStorage, got)
+		}
+		remote, version, err := cache.Redis().GetWithVersion()
+		if err != nil {
+			t.Fatalf("GetWithVersion error: %v", err)
+		}
+		if idsOf(remote) != "2" || version != 2 {
+			t.Errorf("hash=%v: expected Redis 2 at version 2, got %s at %d", hashStorage, idsOf(remote), version)
+		}
+
+		// Deleting a missing key leaves the version alone.
+		if err := cache.Delete("9"); err != nil {
+			t.Fatalf("hash=%v: Delete error: %v", hashStorage, err)
+		}
+		if v, _ := cache.Redis().GetVersion(); v != 2 {
+			t.Errorf("hash=%v: expected version 2, got %d", hashStorage, v)
+		}
+	}
+}
+
+func TestHybridCache_DeleteRedisOnlyHashStorage(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	config := userConfig().WithIndex("email", func(u TestUser) string { return u.Email })
+	cache := NewHybridCache[TestUser](config, client, DefaultRedisConfig()).WithPersistedIndexes()
+	cache.Redis().WithHashStorage(func(u TestUser) string { return u.ID })
+	if err := cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	cache.Memory().Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "3", Email: "c@example.com"}})
+	cache.WithWritePolicy(WriteRedisOnly)
+
+	if err := cache.Delete("1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if pk, ok, _ := cache.Redis().LookupIndex("email", "b@example.com"); !ok || pk != "2" {
+		t.Errorf("Expected the index of the Redis dataset, got %q ok=%v", pk, ok)
+	}
+	for _, email := range []string{"a@example.com", "c@example.com"} {
+		if _, ok, _ := cache.Redis().LookupIndex("email", email); ok {
+			t.Errorf("Expected no index entry for %s", email)
+		}
+	}
+}
+
+func TestHybridCache_DeleteConflict(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// Another writer bumps the version between the read and the write.
+	client.AddHook(&bumpAfterGet{client: client, key: cache.Redis().versionKey()})
+	if err := cache.Delete("1"); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
+
+// bumpAfterGet increments key after the first pipeline, emulating a concurrent writer.
+type bumpAfterGet struct {
+	client *redis.Client
+	key    string
+	done   bool
+}
+
+func (h *bumpAfterGet) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *bumpAfterGet) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h *bumpAfterGet) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		if !h.done {
+			h.done = true
+			h.client.Incr(ctx, h.key)
+		}
+		return err
+	}
+}
+
+func TestHybridCache_DeleteMemoryOnly(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	cache.WithWritePolicy(WriteMemoryOnly)
+	if err := cache.Delete("1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "1,2" {
+		t.Errorf("Expected Redis untouched, got %s", idsOf(remote))
+	}
+}
+
+func TestHybridCache_Clear(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour})
+	defer cache.Close(context.Background())
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := cache.Clear(ctx); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if cache.Memory().Len() != 0 {
+		t.Error("Expected memory to be empty")
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Errorf("Expected no Redis keys and the queued dataset discarded, got %v", keys)
+	}
+}
+
+func TestHybridCache_ClearRedisFailure(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.SetError("LOADING")
+	if err := cache.Clear(context.Background()); err == nil {
+		t.Fatal("Expected the Redis error")
+	}
+	if got := idsOf(cache.GetAll()); got != "1" {
+		t.Errorf("Expected memory untouched after a failed Clear, got %s", got)
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"reflect"
+	"sort"
+)
+
+// CacheDiff describes how one cache's contents differ from another's, key by key.
+// Keys are sorted.
+type CacheDiff struct {
+	// Added lists primary keys present in the cache but not in the other.
+	Added []string
+
+	// Removed lists primary keys present in the other cache but not in this one.
+	Removed []string
+
+	// Changed lists primary keys present in both with different values.
+	Changed []string
+}
+
+// IsEmpty reports whether the two caches held the same contents.
+func (d CacheDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Equal reports whether c and other hold the same primary keys with equal values
+// (compared with reflect.DeepEqual). Insertion order and indexes are not compared.
+// Unlike comparing GetHash, this verifies contents rather than trusting the hash function.
+func (c *MemoryCache[V]) Equal(other *MemoryCache[V]) bool {
+	if c == other {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	mine, theirs := c.AsMapRef(), other.AsMapRef()
+	if mine.Len() != theirs.Len() {
+		return false
+	}
+	equal := true
+	mine.Range(func(pk string, v V) bool {
+		ov, exists := theirs.Get(pk)
+		equal = exists && reflect.DeepEqual(v, ov)
+		return equal
+	})
+	return equal
+}
+
+// DiffAgainst compares c with other key by key and reports the changes from other to c.
+// A nil other is treated as empty. Both caches are read from point-in-time views,
+// so the comparison is consistent even while either cache is being updated.
+func (c *MemoryCache[V]) DiffAgainst(other *MemoryCache[V]) CacheDiff {
+	mine := c.AsMapRef()
+	var theirs MapView[V]
+	if other != nil {
+		theirs = other.AsMapRef()
+	}
+
+	var diff CacheDiff
+	mine.Range(func(pk string, v V) bool {
+		ov, exists := theirs.Get(pk)
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, pk)
+		case !reflect.DeepEqual(v, ov):
+			diff.Changed = append(diff.Changed, pk)
+		}
+		return true
+	})
+	for _, pk := range theirs.order {
+		if !mine.Has(pk) {
+			diff.Removed = append(diff.Removed, pk)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMemoryCache_Equal(t *testing.T) {
+	a := NewMultiIndexCache(userConfig())
+	a.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}})
+	b := NewMultiIndexCache(userConfig())
+	b.Set([]TestUser{{ID: "2", Name: "B"}, {ID: "1", Name: "A"}})
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("Expected caches with same contents in different order to be equal")
+	}
+	if !a.Equal(a) {
+		t.Error("Expected cache to equal itself")
+	}
+	if a.Equal(nil) {
+		t.Error("Expected cache not to equal nil")
+	}
+
+	c := NewMultiIndexCache(userConfig())
+	c.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "changed"}})
+	if a.Equal(c) {
+		t.Error("Expected caches with a changed value to differ")
+	}
+
+	d := NewMultiIndexCache(userConfig())
+	d.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "3", Name: "B"}})
+	if a.Equal(d) {
+		t.Error("Expected caches with different keys to differ")
+	}
+}
+
+func TestMemoryCache_DiffAgainst(t *testing.T) {
+	local := NewMultiIndexCache(userConfig())
+	local.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B2"}, {ID: "4", Name: "D"}})
+	remote := NewMultiIndexCache(userConfig())
+	remote.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}, {ID: "3", Name: "C"}})
+
+	diff := local.DiffAgainst(remote)
+	want := CacheDiff{Added: []string{"4"}, Removed: []string{"3"}, Changed: []string{"2"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("Expected %+v, got %+v", want, diff)
+	}
+	if diff.IsEmpty() {
+		t.Error("Expected non-empty diff")
+	}
+
+	if d := local.DiffAgainst(local); !d.IsEmpty() {
+		t.Errorf("Expected empty diff against itself, got %+v", d)
+	}
+
+	d := local.DiffAgainst(nil)
+	if len(d.Added) != 3 || len(d.Removed) != 0 {
+		t.Errorf("Expected all keys added against nil, got %+v", d)
+	}
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// patchItemsScript applies a diff to the hash of hash storage mode and bumps the version,
+// if the stored version still equals the expected one. KEYS: data, version. ARGV: expected
+// version, ttl in milliseconds, number of deleted fields n, n field names, then alternating
+// field names and values. An empty diff leaves the version unchanged. Returns the version
+// and the number of fields, or -1 and 0 on mismatch.
+var patchItemsScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current ~= tonumber(ARGV[1]) then
+  return {-1, 0}
+end
+if #ARGV == 3 then
+  return {current, redis.call('HLEN', KEYS[1])}
+end
+local n = tonumber(ARGV[3])
+for i = 4, 3 + n do
+  redis.call('HDEL', KEYS[1], ARGV[i])
+end
+for i = 4 + n, #ARGV, 2 do
+  redis.call('HSET', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+local v = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return {v, redis.call('HLEN', KEYS[1])}
+`)
+
+// patchItems writes upserts (alternating primary keys and encoded values) and removes
+// deletes in hash storage mode, if the stored version still equals expectedVersion.
+// Reports false, leaving Redis unchanged, on mismatch; otherwise returns the new version.
+func (c *RedisCache[V]) patchItems(ctx context.Context, upserts []any, deletes []string, expectedVersion int64, ttl time.Duration) (version int64, ok bool, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpPatchItems, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, false, err
+	}
+	for i := 1; i < len(upserts); i += 2 {
+		size += len(upserts[i].([]byte))
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl = c.effectiveTTL(ttl)
+	args := make([]any, 0, 3+len(deletes)+len(upserts))
+	args = append(args, expectedVersion, ttl.Milliseconds(), len(deletes))
+	for _, pk := range deletes {
+		args = append(args, pk)
+	}
+	args = append(args, upserts...)
+	res, err := patchItemsScript.Run(ctx, c.client, []string{c.key, c.versionKey()}, args...).Int64Slice()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to patch items: %w", err)
+	}
+	if res[0] < 0 {
+		return 0, false, nil
+	}
+	if len(upserts) > 0 || len(deletes) > 0 {
+		if err := c.afterItemWrite(ctx, int(res[1]), ttl); err != nil {
+			return res[0], true, err
+		}
+	}
+	return res[0], true, nil
+}
+
+// syncedItems describes the hash this instance last wrote or loaded, under WithDiffSync.
+type syncedItems struct {
+	version int64
+	digests map[string][sha256.Size]byte // SHA-256 of each encoded value, by primary key
+}
+
+// WithDiffSync makes the Redis writes of Set and SyncToRedis (including write-behind and
+// retries) write only what changed when Redis uses hash storage (see
+// RedisCache.WithHashStorage): values are diffed against the dataset this instance last
+// wrote or loaded, and only new and changed fields are written and removed fields
+// deleted, in one script that bumps the version. If another writer changed Redis since,
+// or nothing was synced yet, the whole dataset is written as before. Like the other
+// per-item writes, a diff write drops the content hash (RedisConfig.ContentHash) and does
+// not refresh the stale copy. Without hash storage it has no effect.
+func (c *HybridCache[V]) WithDiffSync() *HybridCache[V] {
+	c.diffSync = true
+	return c
+}
+
+// diffSyncing reports whether writes go through writeItemsDiff.
+func (c *HybridCache[V]) diffSyncing() bool {
+	return c.diffSync && c.redis != nil && c.redis.storage == storageHash
+}
+
+// writeItemsDiff writes values to the Redis hash as a diff against c.synced, or in full,
+// and reports whether Redis changed.
+func (c *HybridCache[V]) writeItemsDiff(values []V) (changed bool, err error) {
+	fields, err := c.redis.encodeItems(values)
+	if err != nil {
+		return false, err
+	}
+	digests := digestFields(fields)
+
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	if base := c.synced; base != nil {
+		c.synced = nil
+		var upserts []any
+		var deletes []string
+		for i := 0; i < len(fields); i += 2 {
+			pk := fields[i].(string)
+			if d, ok := base.digests[pk]; !ok || d != digests[pk] {
+				upserts = append(upserts, fields[i], fields[i+1])
+			}
+		}
+		for pk := range base.digests {
+			if _, ok := digests[pk]; !ok {
+				deletes = append(deletes, pk)
+			}
+		}
+		version, ok, err := c.redis.patchItems(context.Background(), upserts, deletes, base.version, c.redis.setTTL(values))
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			c.synced = &syncedItems{version: version, digests: digests}
+			return len(upserts) > 0 || len(deletes) > 0, nil
+		}
+	}
+
+	if err := c.redis.Set(values); err != nil {
+		return false, err
+	}
+	if version, err := c.redis.GetVersion(); err == nil {
+		c.synced = &syncedItems{version: version, digests: digests}
+	}
+	return true, nil
+}
+
+// recordSynced sets the diff base to values loaded from Redis at version, under
+// WithDiffSync.
+func (c *HybridCache[V]) recordSynced(values []V, version int64) {
+	if !c.diffSyncing() {
+		return
+	}
+	var synced *syncedItems
+	if fields, err := c.redis.encodeItems(values); err == nil {
+		synced = &syncedItems{version: version, digests: digestFields(fields)}
+	}
+	c.syncMu.Lock()
+	c.synced = synced
+	c.syncMu.Unlock()
+}
+
+// digestFields hashes the encoded values of alternating primary keys and values.
+func digestFields(fields []any) map[string][sha256.Size]byte {
+	digests := make(map[string][sha256.Size]byte, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		digests[fields[i].(string)] = sha256.Sum256(fields[i+1].([]byte))
+	}
+	return digests
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHybridCache_DiffSync(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).WithDiffSync()
+	cache.Redis().WithHashStorage(func(u TestUser) string { return u.ID })
+	key := cache.Redis().key
+
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	changed := TestUser{ID: "2", Name: "changed"}
+	if err := cache.Set([]TestUser{{ID: "1"}, changed, {ID: "4"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	var patch observedOp
+	for _, op := range hook.ops {
+		if op.op == OpPatchItems {
+			patch = op
+		}
+	}
+	data2, _ := json.Marshal(changed)
+	data4, _ := json.Marshal(TestUser{ID: "4"})
+	if patch.op == "" || patch.bytes != len(data2)+len(data4) {
+		t.Errorf("Expected a diff write of the changed and new fields, got %+v", patch)
+	}
+	if fields, _ := mr.HKeys(key); len(fields) != 3 || mr.HGet(key, "3") != "" {
+		t.Errorf("Expected field 3 deleted, got %v", fields)
+	}
+	if got := mr.HGet(key, "2"); got != string(data2) {
+		t.Errorf("Expected field 2 updated, got %s", got)
+	}
+	if v, _ := cache.Redis().GetVersion(); v != 2 || cache.LoadedVersion() != 2 {
+		t.Errorf("Expected version 2, got %d (loaded %d)", v, cache.LoadedVersion())
+	}
+}
+
+func TestHybridCache_DiffSyncConflict(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithDiffSync()
+	cache.Redis().WithHashStorage(func(u TestUser) string { return u.ID })
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// Another writer adds a field: the diff base is stale, so the next sync writes in full.
+	if err := cache.Redis().SetItem("9", TestUser{ID: "9"}); err != nil {
+		t.Fatalf("SetItem error: %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "1,2" {
+		t.Errorf("Expected a full write replacing the other writer's field, got %s", idsOf(remote))
+	}
+
+	// Loading sets the diff base as well.
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if cache.synced == nil || len(cache.synced.digests) != 2 {
+		t.Errorf("Expected the loaded dataset as diff base, got %+v", cache.synced)
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// envelopePrefix marks a payload written with RedisConfig.VersionedEnvelope.
+// The stored value is envelopePrefix + version + ":" + payload.
+const envelopePrefix = "ckv1:"
+
+// ErrVersionMismatch is matched (via errors.Is) by every *VersionMismatchError.
+var ErrVersionMismatch = errors.New("cache-kit: data and version keys are inconsistent")
+
+// VersionMismatchError reports that the data key and the version key disagree,
+// e.g. because only one of them survived an eviction or a manual deletion.
+// A version of 0 means the corresponding key is missing.
+type VersionMismatchError struct {
+	// DataVersion is the version recorded in the data envelope.
+	DataVersion int64
+
+	// KeyVersion is the value of the version key.
+	KeyVersion int64
+}
+
+// Error implements error.
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("cache-kit: data written at version %d but version key is %d", e.DataVersion, e.KeyVersion)
+}
+
+// Is reports whether target is ErrVersionMismatch.
+func (e *VersionMismatchError) Is(target error) bool {
+	return target == ErrVersionMismatch
+}
+
+// writeEnvelopeScript increments the version and stores the payload wrapped in an envelope
+// recording that version, atomically. With the bump flag "0", the current version is kept.
+// KEYS: data, version. ARGV: payload, ttl in milliseconds, bump flag.
+var writeEnvelopeScript = redis.NewScript(`
+local v
+if ARGV[3] == '0' then
+  v = tonumber(redis.call('GET', KEYS[2]) or '0')
+else
+  v = redis.call('INCR', KEYS[2])
+end
+redis.call('SET', KEYS[1], 'ckv1:' .. v .. ':' .. ARGV[1], 'PX', ARGV[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return v
+`)
+
+// openEnvelope strips the envelope from data and verifies it against keyVersion.
+// Data without an envelope (written before envelopes were enabled) is returned as-is.
+func (c *RedisCache[V]) openEnvelope(data []byte, keyVersion int64) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(envelopePrefix)) {
+		return data, nil
+	}
+	rest := data[len(envelopePrefix):]
+	sep := bytes.IndexByte(rest, ':')
+	if sep < 0 {
+		return nil, decodeError(errors.New("failed to parse cache envelope: missing version separator"))
+	}
+	dataVersion, err := strconv.ParseInt(string(rest[:sep]), 10, 64)
+	if err != nil {
+		return nil, decodeError(fmt.Errorf("failed to parse cache envelope version: %w", err))
+	}
+	if dataVersion != keyVersion {
+		return nil, &VersionMismatchError{DataVersion: dataVersion, KeyVersion: keyVersion}
+	}
+	return rest[sep+1:], nil
+}
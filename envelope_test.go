@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRedisCache_VersionedEnvelope(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	config := DefaultRedisConfig().WithKeyPrefix("env:").WithVersionedEnvelope(true)
+	cache := NewRedisCache[TestUser](client, config)
+
+	// Missing keys are not a mismatch
+	got, err := cache.Get()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("Expected empty result for missing keys, got %v, %v", got, err)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	raw, _ := mr.Get("env:data")
+	if !strings.HasPrefix(raw, "ckv1:2:") {
+		t.Errorf("Expected payload envelope with version 2, got %q", raw)
+	}
+
+	got, version, err := cache.GetWithVersion()
+	if err != nil {
+		t.Fatalf("GetWithVersion error: %v", err)
+	}
+	if len(got) != 2 || version != 2 {
+		t.Errorf("Expected 2 items at version 2, got %d items at version %d", len(got), version)
+	}
+
+	ttl := mr.TTL("env:data")
+	if ttl <= 0 || mr.TTL("env:data:version") != ttl {
+		t.Errorf("Expected data and version keys to share the TTL, got %v and %v", ttl, mr.TTL("env:data:version"))
+	}
+}
+
+func TestRedisCache_VersionedEnvelopeDetectsMismatch(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	config := DefaultRedisConfig().WithKeyPrefix("env:").WithVersionedEnvelope(true)
+	cache := NewRedisCache[TestUser](client, config)
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// Version key lost (e.g. evicted)
+	mr.Del("env:data:version")
+	_, err := cache.Get()
+	var mismatch *VersionMismatchError
+	if !errors.As(err, &mismatch) || !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("Expected VersionMismatchError, got %v", err)
+	}
+	if mismatch.DataVersion != 1 || mismatch.KeyVersion != 0 {
+		t.Errorf("Unexpected mismatch %+v", mismatch)
+	}
+
+	// Data key lost, version key survived
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.Del("env:data")
+	_, err = cache.Get()
+	if !errors.As(err, &mismatch) || mismatch.DataVersion != 0 || mismatch.KeyVersion != 1 {
+		t.Errorf("Expected mismatch with missing data, got %v", err)
+	}
+
+	// Version bumped by someone else
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.Incr("env:data:version", 5)
+	if _, err := cache.Get(); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("Expected mismatch after foreign version bump, got %v", err)
+	}
+}
+
+func TestRedisCache_VersionedEnvelopeReadsLegacyPayload(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	_ = mr.Set("env:data", `[{"ID":"1"}]`)
+	config := DefaultRedisConfig().WithKeyPrefix("env:").WithVersionedEnvelope(true)
+	cache := NewRedisCache[TestUser](client, config)
+
+	got, err := cache.Get()
+	if err != nil || len(got) != 1 {
+		t.Errorf("Expected legacy payload to be readable, got %v, %v", got, err)
+	}
+}
+
+func TestRedisCache_VersionedEnvelopeMalformed(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	config := DefaultRedisConfig().WithKeyPrefix("env:").WithVersionedEnvelope(true)
+	cache := NewRedisCache[TestUser](client, config)
+
+	_ = mr.Set("env:data", "ckv1:no-separator")
+	if _, err := cache.Get(); err == nil {
+		t.Error("Expected error for envelope without separator")
+	}
+	_ = mr.Set("env:data", "ckv1:abc:[]")
+	if _, err := cache.Get(); err == nil {
+		t.Error("Expected error for envelope with invalid version")
+	}
+}
+
+func TestRedisCache_GetWithVersionPlain(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	got, version, err := cache.GetWithVersion()
+	if err != nil || len(got) != 0 || version != 0 {
+		t.Errorf("Expected empty result at version 0, got %v, %d, %v", got, version, err)
+	}
+
+	_ = cache.Set([]TestUser{{ID: "1"}})
+	_ = cache.Set([]TestUser{{ID: "1"}})
+	got, version, err = cache.GetWithVersion()
+	if err != nil || len(got) != 1 || version != 2 {
+		t.Errorf("Expected 1 item at version 2, got %v, %d, %v", got, version, err)
+	}
+}
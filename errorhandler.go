@@ -0,0 +1,45 @@
+package cache
+
+// Background tasks of a HybridCache, as reported to the WithErrorHandler handler.
+const (
+	TaskWriteBehind  = "write_behind"  // write-behind worker, see WithWriteBehind
+	TaskAutoRefresh  = "auto_refresh"  // StartAutoRefresh loop
+	TaskPubSubReload = "pubsub_reload" // WithPubSubReload subscriber
+	TaskRevalidate   = "revalidate"    // WithStaleWhileRevalidate checks
+	TaskRefreshAhead = "refresh_ahead" // WithRefreshAhead checks
+	TaskSyncRetry    = "sync_retry"    // WithSyncRetry retries
+	TaskColdStore    = "cold_store"    // WithColdStore copies
+	TaskMemoryTTL    = "memory_ttl"    // WithMemoryTTL checks
+	TaskLeader       = "leader"        // StartLeaderRefresh loop
+)
+
+// ErrorHandler receives the errors of background tasks; task is one of the Task constants.
+type ErrorHandler func(task string, err error)
+
+// WithErrorHandler sets where the errors of background tasks go, e.g. logging or
+// alerting; without one they are only logged, to RedisConfig.Logger or Config.Logger if
+// set. It is called from the goroutine of the failing
+// task, so it must be safe for concurrent use and should not block. Write-behind errors
+// reach it as well as WriteBehindConfig.OnError.
+func (c *HybridCache[V]) WithErrorHandler(handler ErrorHandler) *HybridCache[V] {
+	c.errorHandler = handler
+	return c
+}
+
+// reportError passes a background error to the ErrorHandler, if any, and logs it.
+func (c *HybridCache[V]) reportError(task string, err error) {
+	if err == nil {
+		return
+	}
+	if logger := c.logger(); logger != nil {
+		logger.Warn("cache-kit: background task failed", "task", task, "error", err)
+	}
+	if c.errorHandler != nil {
+		c.errorHandler(task, err)
+	}
+}
+
+// reportWriteBehind reports a failed write-behind write.
+func (c *HybridCache[V]) reportWriteBehind(err error) {
+	c.reportError(TaskWriteBehind, err)
+}
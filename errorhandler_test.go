@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// taskErrors collects the errors passed to an ErrorHandler.
+type taskErrors struct {
+	mu    sync.Mutex
+	tasks map[string]int
+}
+
+func (e *taskErrors) handle(task string, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tasks == nil {
+		e.tasks = make(map[string]int)
+	}
+	e.tasks[task]++
+}
+
+func (e *taskErrors) count(task string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.tasks[task]
+}
+
+func TestHybridCache_ErrorHandler(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	errs := &taskErrors{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithErrorHandler(errs.handle).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Millisecond, RetryBackoff: time.Millisecond, MaxRetries: 1}).
+		WithStaleWhileRevalidate(0)
+	defer cache.Close(context.Background())
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	cache.StartAutoRefresh(time.Millisecond)
+	cache.GetAll()
+
+	for _, task := range []string{TaskWriteBehind, TaskAutoRefresh, TaskRevalidate} {
+		waitFor(t, func() bool { return errs.count(task) > 0 })
+	}
+	cache.Stop()
+	mr.SetError("")
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// ErrNilClient is returned by RedisCache operations on a cache created without a client.
+var ErrNilClient = errors.New("cache-kit: redis client is nil")
+
+// ErrValueTooLarge is matched by the *ValueTooLargeError returned for a stored payload over
+// RedisConfig.MaxValueBytes.
+var ErrValueTooLarge = errors.New("cache-kit: cache value exceeds MaxValueBytes")
+
+// ErrDecode is matched by errors of stored payloads that cannot be decoded: a codec error
+// or a malformed envelope. The codec error stays in the chain for errors.As.
+var ErrDecode = errors.New("cache-kit: failed to decode cached value")
+
+// ErrTimeout is matched by errors of RedisCache operations that ran out of time: the context
+// deadline (the caller's, OperationTimeout or WithCallTimeout) or a network timeout of the
+// client. The original error stays in the chain, so errors.Is(err, context.DeadlineExceeded)
+// keeps working.
+var ErrTimeout = errors.New("cache-kit: redis operation timed out")
+
+// ValueTooLargeError reports a stored payload over RedisConfig.MaxValueBytes, rejected
+// before decoding to prevent OOM.
+type ValueTooLargeError struct {
+	Size int // payload size in bytes
+	Max  int // RedisConfig.MaxValueBytes
+}
+
+// Error implements error.
+func (e *ValueTooLargeError) Error() string {
+	return fmt.Sprintf("cache value size %d exceeds max allowed %d", e.Size, e.Max)
+}
+
+// Is reports whether target is ErrValueTooLarge, so errors.Is works on wrapped errors.
+func (e *ValueTooLargeError) Is(target error) bool {
+	return target == ErrValueTooLarge
+}
+
+// markedError adds a sentinel to the chain of err without changing its message.
+type markedError struct{ err, mark error }
+
+func (e *markedError) Error() string   { return e.err.Error() }
+func (e *markedError) Unwrap() []error { return []error{e.err, e.mark} }
+
+// decodeError marks err as ErrDecode.
+func decodeError(err error) error {
+	return &markedError{err: err, mark: ErrDecode}
+}
+
+// markTimeout marks err as ErrTimeout if it is a context deadline or network timeout.
+func markTimeout(err error) error {
+	if err == nil || errors.Is(err, ErrTimeout) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &markedError{err: err, mark: ErrTimeout}
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_SentinelErrors(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	noClient := NewRedisCache[TestUser](nil, DefaultRedisConfig())
+	if _, err := noClient.Get(); !errors.Is(err, ErrNilClient) {
+		t.Errorf("Expected ErrNilClient, got %v", err)
+	}
+
+	limited := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("limited:").WithMaxValueBytes(8))
+	if err := limited.Set([]TestUser{{ID: "1", Name: "a long enough name"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	_, err := limited.Get()
+	var tooLarge *ValueTooLargeError
+	if !errors.Is(err, ErrValueTooLarge) || !errors.As(err, &tooLarge) || tooLarge.Max != 8 || tooLarge.Size <= 8 {
+		t.Errorf("Expected a *ValueTooLargeError over 8 bytes, got %v", err)
+	}
+
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	mr.Set(cache.key, "not json")
+	if _, err := cache.Get(); !errors.Is(err, ErrDecode) || errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrDecode, got %v", err)
+	}
+
+	_, err = cache.ExistsCtx(WithCallTimeout(context.Background(), time.Nanosecond))
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected ErrTimeout wrapping the deadline, got %v", err)
+	}
+}
+
+func TestMarkTimeout(t *testing.T) {
+	if markTimeout(nil) != nil {
+		t.Error("Expected nil to stay nil")
+	}
+	plain := errors.New("connection refused")
+	if markTimeout(plain) != plain {
+		t.Error("Expected other errors to be returned unchanged")
+	}
+	marked := markTimeout(context.DeadlineExceeded)
+	if markTimeout(marked) != marked || marked.Error() != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected marking to be idempotent and keep the message, got %q", marked)
+	}
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// exportFormat identifies the envelope written by Export.
+const exportFormat = "cache-kit/export/v1"
+
+// ErrExportMismatch is returned by Import for an export that does not fit the cache: an
+// unknown format or a different storage mode.
+var ErrExportMismatch = errors.New("cache-kit: export does not match this cache")
+
+// exportEnvelope is the portable form of an exported dataset. Keys are recorded by role
+// (data, version, ...) rather than name, so an export can be imported under other key names,
+// e.g. another KeyPrefix or environment.
+type exportEnvelope struct {
+	Format  string               `json:"format"`
+	Storage string               `json:"storage"`
+	Keys    map[string]exportKey `json:"keys"`
+}
+
+// exportKey is one key of an export: its DUMP serialization and remaining TTL.
+type exportKey struct {
+	Dump  []byte `json:"dump"`
+	TTLMs int64  `json:"ttl_ms,omitempty"`
+}
+
+// exportKeys returns the keys of the dataset by role. Persisted indexes and locks are not
+// part of it.
+func (c *RedisCache[V]) exportKeys() map[string]string {
+	return map[string]string{
+		"data":    c.key,
+		"version": c.versionKey(),
+		"meta":    c.metadataKey(),
+		"stale":   c.staleKey(),
+		"soft":    c.softKey(),
+		"hash":    c.contentHashKey(),
+	}
+}
+
+// Export serializes the stored dataset, its version and the keys kept alongside it
+// (metadata, stale copy, soft expiry marker, content hash) with DUMP, together with their
+// remaining TTLs, so Import can restore it on another Redis instance or under other key
+// names without decoding values in the application. DUMP payloads are only portable between compatible
+// Redis versions (the RDB version must not be newer on the target). Persisted indexes are
+// not exported; rebuild them with HybridCache.SyncToRedis. Not supported in blue/green
+// storage mode.
+func (c *RedisCache[V]) Export(ctx context.Context) (_ []byte, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpExport, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+	if c.storage == storageBlueGreen {
+		return nil, fmt.Errorf("blue/green storage cannot be exported")
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	roles := c.exportKeys()
+	pipe := c.client.Pipeline()
+	dumps := make(map[string]*redis.StringCmd, len(roles))
+	ttls := make(map[string]*redis.DurationCmd, len(roles))
+	for role, key := range roles {
+		dumps[role] = pipe.Dump(ctx, key)
+		ttls[role] = pipe.PTTL(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to export: %w", err)
+	}
+
+	envelope := exportEnvelope{Format: exportFormat, Storage: c.storageName(), Keys: map[string]exportKey{}}
+	for role, cmd := range dumps {
+		dump, err := cmd.Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s key: %w", role, err)
+		}
+		key := exportKey{Dump: dump}
+		if ttl := ttls[role].Val(); ttl > 0 {
+			key.TTLMs = ttl.Milliseconds()
+		}
+		envelope.Keys[role] = key
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export: %w", err)
+	}
+	size = len(data)
+	return data, nil
+}
+
+// Import replaces the stored dataset with one produced by Export, restoring each key with
+// RESTORE under this cache's key names, with the TTL it had when exported, in one
+// transaction. Keys absent from the export are deleted. The version is restored as exported,
+// so readers comparing versions only notice the change if it differs from the old one.
+// Returns ErrExportMismatch for an export of another format or storage mode.
+func (c *RedisCache[V]) Import(ctx context.Context, data []byte) (err error) {
+	start, size := time.Now(), len(data)
+	defer func() { err = c.observe(OpImport, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+	var envelope exportEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("failed to unmarshal export: %w", err)
+	}
+	if envelope.Format != exportFormat {
+		return fmt.Errorf("%w: format %q", ErrExportMismatch, envelope.Format)
+	}
+	if envelope.Storage != c.storageName() {
+		return fmt.Errorf("%w: storage mode %s, cache uses %s", ErrExportMismatch, envelope.Storage, c.storageName())
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	pipe := c.client.TxPipeline()
+	for role, key := range c.exportKeys() {
+		pipe.Del(ctx, key)
+		if k, ok := envelope.Keys[role]; ok {
+			pipe.Restore(ctx, key, time.Duration(k.TTLMs)*time.Millisecond, string(k.Dump))
+		}
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+	return nil
+}
+
+// storageName returns the name of the storage mode recorded in exports.
+func (c *RedisCache[V]) storageName() string {
+	switch c.storage {
+	case storageHash:
+		return "hash"
+	case storageList:
+		return "list"
+	case storageJSON:
+		return "json"
+	case storageBlueGreen:
+		return "bluegreen"
+	default:
+		return "blob"
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	srcRedis, srcClient := setupMiniRedis(t)
+	dstRedis, dstClient := setupMiniRedis(t)
+
+	// miniredis only DUMPs strings, so the metadata hash is left out here.
+	src := NewRedisCache[TestUser](srcClient, DefaultRedisConfig().
+		WithKeyPrefix("prod:users:").WithSoftTTL(time.Minute))
+	dst := NewRedisCache[TestUser](dstClient, DefaultRedisConfig().
+		WithKeyPrefix("staging:users:").WithStaleTTL(time.Hour))
+
+	if err := src.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := src.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	// The target has a stale copy the export does not carry; Import removes it.
+	if err := dst.Set([]TestUser{{ID: "9"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	data, err := src.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if err := dst.Import(ctx, data); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+
+	values, version, err := dst.GetWithVersion()
+	if err != nil || idsOf(values) != "1,2,3" || version != 2 {
+		t.Errorf("Expected 1,2,3 at version 2, got %s at %d (%v)", idsOf(values), version, err)
+	}
+	if !dstRedis.Exists(dst.softKey()) {
+		t.Error("Expected the soft expiry marker to be imported")
+	}
+	if _, err := dst.GetStale(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected the stale copy to be removed, got %v", err)
+	}
+	if ttl := dstRedis.TTL(dst.key); ttl <= 0 || ttl > srcRedis.TTL(src.key) {
+		t.Errorf("Expected the exported TTL to be kept, got %v", ttl)
+	}
+}
+
+func TestRedisCache_ImportMismatch(t *testing.T) {
+	ctx := context.Background()
+	_, client := setupMiniRedis(t)
+	blob := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("blob:"))
+	list := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("list:")).WithListStorage()
+
+	data, err := blob.Export(ctx)
+	if err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+	if err := list.Import(ctx, data); !errors.Is(err, ErrExportMismatch) {
+		t.Errorf("Expected ErrExportMismatch for another storage mode, got %v", err)
+	}
+	if err := blob.Import(ctx, []byte(`{"format":"other"}`)); !errors.Is(err, ErrExportMismatch) {
+		t.Errorf("Expected ErrExportMismatch for another format, got %v", err)
+	}
+	if err := blob.Import(ctx, []byte("garbage")); err == nil {
+		t.Error("Expected an error for an invalid export")
+	}
+}
@@ -0,0 +1,216 @@
+// Package filestore provides a cache.RemoteStore kept in files, so the full HybridCache
+// flow runs locally and in CI without any external service:
+//
+//	store := filestore.New[User](t.TempDir())
+//	users := cache.NewHybridCacheWithStore(memConfig, store)
+//
+// The directory holds the dataset as JSON ("data.json", an array) or NDJSON
+// ("data.ndjson", one value per line, see WithNDJSON) next to a "version" file. Files are
+// written to a temporary name and renamed into place, so readers never see a partial
+// dataset. Writes are serialized within a process only; point each process at its own
+// directory, or accept that concurrent writers may assign the same version.
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+// versionFile is the name of the version file.
+const versionFile = "version"
+
+// Store is a cache.RemoteStore backed by files in a directory. It is safe for concurrent
+// use.
+type Store[V any] struct {
+	dir    string
+	ndjson bool
+	mu     sync.Mutex // serializes writes
+}
+
+var _ cache.RemoteStore[struct{}] = (*Store[struct{}])(nil)
+
+// New creates a Store in dir, creating the directory on the first write.
+func New[V any](dir string) *Store[V] {
+	return &Store[V]{dir: dir}
+}
+
+// WithNDJSON stores the dataset as NDJSON, one value per line, which keeps diffs of
+// fixtures under version control readable.
+func (s *Store[V]) WithNDJSON() *Store[V] {
+	s.ndjson = true
+	return s
+}
+
+// Path returns the path of the data file.
+func (s *Store[V]) Path() string {
+	if s.ndjson {
+		return filepath.Join(s.dir, "data.ndjson")
+	}
+	return filepath.Join(s.dir, "data.json")
+}
+
+// GetCtx implements cache.RemoteStore.
+func (s *Store[V]) GetCtx(ctx context.Context) ([]V, error) {
+	data, err := os.ReadFile(s.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return []V{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	values, err := s.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal values: %w", cache.ErrDecode, err)
+	}
+	return values, nil
+}
+
+// SetCtx implements cache.RemoteStore. SetOptions fail with cache.ErrUnsupportedSetOption.
+func (s *Store[V]) SetCtx(ctx context.Context, values []V, opts ...cache.SetOption) error {
+	if !cache.ApplySetOptions(opts...).IsZero() {
+		return fmt.Errorf("%w: filestore supports no set options", cache.ErrUnsupportedSetOption)
+	}
+	data, err := s.encode(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version, err := s.GetVersionCtx(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := writeFile(s.Path(), data); err != nil {
+		return fmt.Errorf("failed to write dataset: %w", err)
+	}
+	if err := writeFile(filepath.Join(s.dir, versionFile), []byte(strconv.FormatInt(version+1, 10)+"\n")); err != nil {
+		return fmt.Errorf("failed to write version: %w", err)
+	}
+	return nil
+}
+
+// ExistsCtx implements cache.RemoteStore.
+func (s *Store[V]) ExistsCtx(ctx context.Context) (bool, error) {
+	_, err := os.Stat(s.Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check dataset: %w", err)
+	}
+	return true, nil
+}
+
+// GetVersionCtx implements cache.RemoteStore.
+func (s *Store[V]) GetVersionCtx(ctx context.Context) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, versionFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read version: %w", err)
+	}
+	version, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse version: %w", err)
+	}
+	return version, nil
+}
+
+// ClearCtx implements cache.RemoteStore.
+func (s *Store[V]) ClearCtx(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range []string{s.Path(), filepath.Join(s.dir, versionFile)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to clear dataset: %w", err)
+		}
+	}
+	return nil
+}
+
+// TTLCtx implements cache.RemoteStore. Files do not expire.
+func (s *Store[V]) TTLCtx(ctx context.Context) (time.Duration, error) {
+	ok, err := s.ExistsCtx(ctx)
+	if err != nil || !ok {
+		return -2, err
+	}
+	return -1, nil
+}
+
+// encode encodes values in the file format.
+func (s *Store[V]) encode(values []V) ([]byte, error) {
+	if !s.ndjson {
+		if values == nil {
+			values = []V{}
+		}
+		return json.Marshal(values)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decode decodes a data file; blank NDJSON lines are skipped.
+func (s *Store[V]) decode(data []byte) ([]V, error) {
+	values := []V{}
+	if !s.ndjson {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var v V
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		values = append(values, v)
+	}
+	return values, scanner.Err()
+}
+
+// writeFile writes data to a temporary file next to path and renames it into place.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
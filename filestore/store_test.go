@@ -0,0 +1,123 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	for _, ndjson := range []bool{false, true} {
+		store := New[user](filepath.Join(t.TempDir(), "users"))
+		if ndjson {
+			store.WithNDJSON()
+		}
+		ctx := context.Background()
+
+		if ok, err := store.ExistsCtx(ctx); err != nil || ok {
+			t.Errorf("Expected no dataset, got %v, %v", ok, err)
+		}
+		if ttl, _ := store.TTLCtx(ctx); ttl != -2 {
+			t.Errorf("Expected TTL -2 for a missing dataset, got %v", ttl)
+		}
+		if values, err := store.GetCtx(ctx); err != nil || len(values) != 0 {
+			t.Errorf("Expected an empty dataset, got %v, %v", values, err)
+		}
+
+		for i := range 2 {
+			if err := store.SetCtx(ctx, []user{{ID: "1", Name: "a"}, {ID: "2", Name: "b"}}); err != nil {
+				t.Fatalf("SetCtx error: %v", err)
+			}
+			if version, _ := store.GetVersionCtx(ctx); version != int64(i+1) {
+				t.Errorf("Expected version %d, got %d", i+1, version)
+			}
+		}
+		values, err := store.GetCtx(ctx)
+		if err != nil || len(values) != 2 || values[1].Name != "b" {
+			t.Errorf("Expected the stored dataset, got %v, %v", values, err)
+		}
+		if ttl, _ := store.TTLCtx(ctx); ttl != -1 {
+			t.Errorf("Expected TTL -1, got %v", ttl)
+		}
+
+		if err := store.ClearCtx(ctx); err != nil {
+			t.Fatalf("ClearCtx error: %v", err)
+		}
+		if ok, _ := store.ExistsCtx(ctx); ok {
+			t.Error("Expected ClearCtx to remove the dataset")
+		}
+		if version, _ := store.GetVersionCtx(ctx); version != 0 {
+			t.Errorf("Expected version 0 after ClearCtx, got %d", version)
+		}
+	}
+}
+
+func TestStore_SetOptions(t *testing.T) {
+	store := New[user](filepath.Join(t.TempDir(), "users"))
+	err := store.SetCtx(context.Background(), []user{{ID: "1"}}, cache.WithTTL(time.Minute))
+	if !errors.Is(err, cache.ErrUnsupportedSetOption) {
+		t.Errorf("Expected ErrUnsupportedSetOption, got %v", err)
+	}
+	if ok, _ := store.ExistsCtx(context.Background()); ok {
+		t.Error("Expected a rejected write to store nothing")
+	}
+}
+
+func TestStore_NDJSONFile(t *testing.T) {
+	dir := t.TempDir()
+	store := New[user](dir).WithNDJSON()
+	if err := store.SetCtx(context.Background(), []user{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "data.ndjson"))
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if want := "{\"id\":\"1\",\"name\":\"\"}\n{\"id\":\"2\",\"name\":\"\"}\n"; string(data) != want {
+		t.Errorf("Unexpected file content %q", data)
+	}
+
+	// Hand-edited fixtures may contain blank lines; broken lines are decode errors.
+	if err := os.WriteFile(store.Path(), []byte("{\"id\":\"3\"}\n\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	if values, err := store.GetCtx(context.Background()); err != nil || len(values) != 1 {
+		t.Errorf("Expected one value, got %v, %v", values, err)
+	}
+	if err := os.WriteFile(store.Path(), []byte("{\"id\""), 0o644); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+	if _, err := store.GetCtx(context.Background()); !errors.Is(err, cache.ErrDecode) {
+		t.Errorf("Expected ErrDecode, got %v", err)
+	}
+}
+
+func TestStore_HybridCache(t *testing.T) {
+	store := New[user](t.TempDir())
+	config := cache.DefaultConfig[user]().WithPrimaryKey(func(u user) string { return u.ID })
+	writer := cache.NewHybridCacheWithStore(config, store)
+	reader := cache.NewHybridCacheWithStore(config, store)
+
+	if err := writer.Set([]user{{ID: "1", Name: "a"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if stale, err := reader.NeedsRefresh(context.Background()); err != nil || !stale {
+		t.Errorf("Expected the reader to need a refresh, got %v, %v", stale, err)
+	}
+	if err := reader.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if u, ok := reader.Get("1"); !ok || u.Name != "a" {
+		t.Errorf("Expected the reader to load the dataset, got %+v, %v", u, ok)
+	}
+}
@@ -0,0 +1,378 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// writeBatchSize is the number of hash fields or list entries sent per HSET or RPUSH command.
+const writeBatchSize = 1000
+
+// ErrNotHashStorage is returned by the per-item methods on a cache not in hash storage mode.
+var ErrNotHashStorage = errors.New("cache-kit: per-item access requires hash storage (see RedisCache.WithHashStorage)")
+
+// setItemScript stores one hash field and bumps the version, atomically.
+// KEYS: data, version. ARGV: field, value, ttl in milliseconds. Returns the number of fields.
+var setItemScript = redis.NewScript(`
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return redis.call('HLEN', KEYS[1])
+`)
+
+// deleteItemScript removes one hash field and, if it existed, bumps the version, atomically.
+// KEYS: data, version. ARGV: field, ttl in milliseconds.
+// Returns the number of remaining fields, or -1 if the field did not exist.
+var deleteItemScript = redis.NewScript(`
+if redis.call('HDEL', KEYS[1], ARGV[1]) == 0 then
+  return -1
+end
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return redis.call('HLEN', KEYS[1])
+`)
+
+// fetchItemsScript reads the hash and the version atomically, checking the total size of
+// the fields server-side first so an oversized hash is never transferred.
+// KEYS: data, version. ARGV: size limit in bytes.
+// Returns {version, size, {field, value, ...}}, without the fields if size exceeds the limit.
+var fetchItemsScript = redis.NewScript(`
+local version = redis.call('GET', KEYS[2]) or ''
+local size = 0
+for _, field in ipairs(redis.call('HKEYS', KEYS[1])) do
+  size = size + redis.call('HSTRLEN', KEYS[1], field)
+end
+if size > tonumber(ARGV[1]) then
+  return {version, size}
+end
+return {version, size, redis.call('HGETALL', KEYS[1])}
+`)
+
+// WithHashStorage switches the cache to hash storage mode: the dataset is stored as a Redis
+// HASH with one field per primary key (each value encoded on its own with RedisConfig.Codec)
+// instead of a single payload, so GetItem and GetItems can fetch single records without
+// downloading the whole dataset. Get returns values sorted by primary key; values without a
+// primary key are not stored, and for duplicate keys the last value wins.
+// VersionedEnvelope is not used in this mode; data and version are written in one MULTI/EXEC
+// transaction instead. An empty dataset leaves only the version key.
+// Replaces other storage modes. Must be called before the cache is used; all readers and writers
+// of a key must agree on the mode.
+func (c *RedisCache[V]) WithHashStorage(primaryKey KeyFunc[V]) *RedisCache[V] {
+	c.storage = storageHash
+	c.itemKey = primaryKey
+	return c
+}
+
+// writeItems replaces the hash with one field per value and bumps the version (if bump is
+// set), atomically. Returns the total size of the encoded values.
+func (c *RedisCache[V]) writeItems(ctx context.Context, values []V, ttl time.Duration, bump bool) (int, error) {
+	fields, err := c.encodeItems(values)
+	if err != nil {
+		return 0, err
+	}
+	size := 0
+	for i := 1; i < len(fields); i += 2 {
+		size += len(fields[i].([]byte))
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, c.key)
+	for i := 0; i < len(fields); i += 2 * writeBatchSize {
+		pipe.HSet(ctx, c.key, fields[i:min(i+2*writeBatchSize, len(fields))]...)
+	}
+	if len(fields) > 0 {
+		pipe.Expire(ctx, c.key, ttl)
+	}
+	if bump {
+		pipe.Incr(ctx, c.versionKey())
+	}
+	pipe.Expire(ctx, c.versionKey(), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return size, fmt.Errorf("failed to set cache: %w", err)
+	}
+	return size, nil
+}
+
+// encodeItems returns alternating primary keys and encoded values, skipping values without a key.
+func (c *RedisCache[V]) encodeItems(values []V) ([]any, error) {
+	codec := c.codec()
+	fields := make([]any, 0, 2*len(values))
+	unkeyed := 0
+	defer func() { c.logUnkeyed(unkeyed) }()
+	for _, v := range values {
+		pk := c.itemKey(v)
+		if pk == "" {
+			unkeyed++
+			continue
+		}
+		data, err := codec.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value %q: %w", pk, err)
+		}
+		fields = append(fields, pk, data)
+	}
+	return fields, nil
+}
+
+// fetchItems reads the whole hash and the version in one transaction. Under
+// MaxValueBytes the size is checked before the fields are transferred.
+// The dataset counts as found if either key exists; size is the total size of the fields.
+func (c *RedisCache[V]) fetchItems(ctx context.Context) (values []V, version int64, found bool, size int, err error) {
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	var fields map[string]string
+	if maxBytes := c.config.MaxValueBytes; maxBytes > 0 {
+		fields, version, size, err = c.fetchItemsLimited(ctx, maxBytes)
+		if err != nil {
+			return nil, version, version != 0 || size > 0, size, err
+		}
+	} else {
+		pipe := c.client.TxPipeline()
+		fieldsCmd := pipe.HGetAll(ctx, c.key)
+		versionCmd := pipe.Get(ctx, c.versionKey())
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, 0, false, 0, fmt.Errorf("failed to get cache: %w", err)
+		}
+		version, err = versionCmd.Int64()
+		if err != nil && err != redis.Nil {
+			return nil, 0, false, 0, fmt.Errorf("failed to get version: %w", err)
+		}
+		fields = fieldsCmd.Val()
+		for _, data := range fields {
+			size += len(data)
+		}
+	}
+
+	pks := make([]string, 0, len(fields))
+	for pk := range fields {
+		pks = append(pks, pk)
+	}
+	slices.Sort(pks)
+	values = make([]V, 0, len(pks))
+	for _, pk := range pks {
+		v, err := c.decodeItem(pk, fields[pk])
+		if err != nil {
+			return nil, version, true, size, err
+		}
+		values = append(values, v)
+	}
+	return values, version, len(fields) > 0 || version != 0, size, nil
+}
+
+// fetchItemsLimited runs fetchItemsScript, returning a *ValueTooLargeError without the
+// fields when they exceed maxBytes.
+func (c *RedisCache[V]) fetchItemsLimited(ctx context.Context, maxBytes int) (map[string]string, int64, int, error) {
+	result, err := fetchItemsScript.Run(ctx, c.client, []string{c.key, c.versionKey()}, maxBytes).Slice()
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get cache: %w", err)
+	}
+	var version int64
+	if s, _ := result[0].(string); s != "" {
+		if version, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to get version: %w", err)
+		}
+	}
+	size := int(result[1].(int64))
+	if len(result) < 3 {
+		return nil, version, size, &ValueTooLargeError{Size: size, Max: maxBytes}
+	}
+	pairs, _ := result[2].([]any)
+	fields := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		pk, _ := pairs[i].(string)
+		data, _ := pairs[i+1].(string)
+		fields[pk] = data
+	}
+	return fields, version, size, nil
+}
+
+// decodeItem decodes one hash field.
+func (c *RedisCache[V]) decodeItem(pk, data string) (V, error) {
+	var v V
+	if maxBytes := c.config.MaxValueBytes; maxBytes > 0 && len(data) > maxBytes {
+		return v, &ValueTooLargeError{Size: len(data), Max: maxBytes}
+	}
+	if err := c.codec().Unmarshal([]byte(data), &v); err != nil {
+		return v, decodeError(fmt.Errorf("failed to unmarshal value %q: %w", pk, err))
+	}
+	return v, nil
+}
+
+// SetItem stores value under pk in hash storage mode and bumps the version, without rewriting
+// the rest of the dataset. pk should be the value's primary key (see WithHashStorage).
+// The TTL of the data and version keys is reset to RedisConfig.TTL.
+func (c *RedisCache[V]) SetItem(pk string, value V) error {
+	return c.SetItemCtx(context.Background(), pk, value)
+}
+
+// SetItemCtx is like SetItem but uses ctx for the Redis calls.
+func (c *RedisCache[V]) SetItemCtx(ctx context.Context, pk string, value V) (err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpSetItem, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+	if c.storage != storageHash {
+		return ErrNotHashStorage
+	}
+	if pk == "" {
+		return fmt.Errorf("primary key is empty")
+	}
+	data, err := c.codec().Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value %q: %w", pk, err)
+	}
+	size = len(data)
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl := c.effectiveTTL(c.config.TTL)
+	items, err := setItemScript.Run(ctx, c.client, []string{c.key, c.versionKey()},
+		pk, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to set item: %w", err)
+	}
+	return c.afterItemWrite(ctx, items, ttl)
+}
+
+// DeleteItem removes the value stored under pk in hash storage mode and bumps the version.
+// Reports whether the value existed; deleting a missing key leaves the version unchanged.
+func (c *RedisCache[V]) DeleteItem(pk string) (bool, error) {
+	return c.DeleteItemCtx(context.Background(), pk)
+}
+
+// DeleteItemCtx is like DeleteItem but uses ctx for the Redis calls.
+func (c *RedisCache[V]) DeleteItemCtx(ctx context.Context, pk string) (_ bool, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpDeleteItem, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return false, err
+	}
+	if c.storage != storageHash {
+		return false, ErrNotHashStorage
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl := c.effectiveTTL(c.config.TTL)
+	items, err := deleteItemScript.Run(ctx, c.client, []string{c.key, c.versionKey()},
+		pk, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete item: %w", err)
+	}
+	if items < 0 {
+		return false, nil
+	}
+	return true, c.afterItemWrite(ctx, items, ttl)
+}
+
+// afterItemWrite updates the derived keys after a per-item write (SetItem, DeleteItem,
+// Append): it refreshes the published metadata and drops the content hash, which no longer
+// describes the dataset, if enabled.
+func (c *RedisCache[V]) afterItemWrite(ctx context.Context, items int, ttl time.Duration) error {
+	if c.config.ContentHash {
+		if err := c.client.Del(ctx, c.contentHashKey()).Err(); err != nil {
+			return fmt.Errorf("failed to drop content hash: %w", err)
+		}
+	}
+	if !c.config.PublishMetadata {
+		return nil
+	}
+	if err := c.writeMetadata(ctx, items, ttl); err != nil {
+		return fmt.Errorf("failed to set cache metadata: %w", err)
+	}
+	return nil
+}
+
+// GetItem fetches the value stored under pk in hash storage mode.
+// Returns false if there is no such value; ErrNotHashStorage outside hash storage mode.
+func (c *RedisCache[V]) GetItem(pk string) (V, bool, error) {
+	return c.GetItemCtx(context.Background(), pk)
+}
+
+// GetItemCtx is like GetItem but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetItemCtx(ctx context.Context, pk string) (_ V, _ bool, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpGetItem, start, size, err) }()
+
+	var zero V
+	if err := c.admit(); err != nil {
+		return zero, false, err
+	}
+	if c.storage != storageHash {
+		return zero, false, ErrNotHashStorage
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	data, err := c.client.HGet(ctx, c.key, pk).Result()
+	if err == redis.Nil {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, fmt.Errorf("failed to get item: %w", err)
+	}
+	size = len(data)
+	v, err := c.decodeItem(pk, data)
+	if err != nil {
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// GetItems fetches the values stored under pks in hash storage mode with a single HMGET.
+// Missing keys are absent from the result; ErrNotHashStorage outside hash storage mode.
+func (c *RedisCache[V]) GetItems(pks []string) (map[string]V, error) {
+	return c.GetItemsCtx(context.Background(), pks)
+}
+
+// GetItemsCtx is like GetItems but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetItemsCtx(ctx context.Context, pks []string) (_ map[string]V, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpGetItems, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+	if c.storage != storageHash {
+		return nil, ErrNotHashStorage
+	}
+	result := make(map[string]V, len(pks))
+	if len(pks) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	raw, err := c.client.HMGet(ctx, c.key, pks...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get items: %w", err)
+	}
+	for i, item := range raw {
+		data, ok := item.(string)
+		if !ok {
+			continue // missing field
+		}
+		size += len(data)
+		v, err := c.decodeItem(pks[i], data)
+		if err != nil {
+			return nil, err
+		}
+		result[pks[i]] = v
+	}
+	return result, nil
+}
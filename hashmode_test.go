@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func newHashCache(t *testing.T, config *RedisConfig) (*RedisCache[TestUser], func() ([]string, error)) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, config).
+		WithHashStorage(func(u TestUser) string { return u.ID })
+	return cache, func() ([]string, error) { return mr.HKeys("cache:data") }
+}
+
+func TestRedisCache_HashStorage(t *testing.T) {
+	cache, fields := newHashCache(t, DefaultRedisConfig())
+
+	if err := cache.Set([]TestUser{{ID: "2", Name: "B"}, {ID: "1", Name: "A"}, {Name: "no key"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if got, err := fields(); err != nil || len(got) != 2 {
+		t.Fatalf("Expected one hash field per keyed value, got %v (%v)", got, err)
+	}
+
+	values, version, err := cache.GetWithVersion()
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if version != 1 || len(values) != 2 || values[0].ID != "1" || values[1].ID != "2" {
+		t.Errorf("Expected values sorted by key at version 1, got %+v at %d", values, version)
+	}
+
+	// A full write replaces the hash rather than merging into it.
+	if err := cache.Set([]TestUser{{ID: "3", Name: "C"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if got, _ := fields(); len(got) != 1 || got[0] != "3" {
+		t.Errorf("Expected only field 3 after replace, got %v", got)
+	}
+	if v, _ := cache.GetVersion(); v != 2 {
+		t.Errorf("Expected version 2, got %d", v)
+	}
+}
+
+func TestRedisCache_GetItem(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	u, ok, err := cache.GetItem("2")
+	if err != nil || !ok || u.Name != "B" {
+		t.Errorf("Expected item B, got %+v ok=%v err=%v", u, ok, err)
+	}
+	if _, ok, err := cache.GetItem("missing"); err != nil || ok {
+		t.Errorf("Expected missing item, got ok=%v err=%v", ok, err)
+	}
+
+	items, err := cache.GetItems([]string{"1", "missing", "2"})
+	if err != nil {
+		t.Fatalf("GetItems error: %v", err)
+	}
+	if len(items) != 2 || items["1"].Name != "A" || items["2"].Name != "B" {
+		t.Errorf("Expected items 1 and 2, got %+v", items)
+	}
+	if items, err := cache.GetItems(nil); err != nil || len(items) != 0 {
+		t.Errorf("Expected empty result for no keys, got %+v (%v)", items, err)
+	}
+}
+
+func TestRedisCache_HashStorageEmpty(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig())
+
+	if _, found, err := cache.load(context.Background()); err != nil || found {
+		t.Errorf("Expected nothing found before the first write, got found=%v err=%v", found, err)
+	}
+	if err := cache.Set(nil); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, found, err := cache.load(context.Background())
+	if err != nil || !found {
+		t.Fatalf("Expected an empty dataset to be found, got found=%v err=%v", found, err)
+	}
+	if values == nil || len(values) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", values)
+	}
+}
+
+func TestRedisCache_HashStorageMaxValueBytes(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig().WithMaxValueBytes(64))
+	users := []TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}, {ID: "3", Name: "C"}}
+	if err := cache.Set(users); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// The size is checked server-side, before the fields are downloaded.
+	size := 0
+	for _, u := range users {
+		data, _ := json.Marshal(u)
+		size += len(data)
+	}
+	var tooLarge *ValueTooLargeError
+	if _, err := cache.Get(); !errors.As(err, &tooLarge) || tooLarge.Size != size || tooLarge.Max != 64 {
+		t.Errorf("Expected a size error for the whole hash of %d bytes, got %v", size, err)
+	}
+	if _, ok, err := cache.GetItem("1"); err != nil || !ok {
+		t.Errorf("Expected a single item within the limit, got ok=%v err=%v", ok, err)
+	}
+
+	cache.config.WithMaxValueBytes(0)
+	if values, version, err := cache.GetWithVersion(); err != nil || len(values) != 3 || version != 1 {
+		t.Errorf("Expected the hash without a limit, got %v at %d, %v", values, version, err)
+	}
+}
+
+func TestRedisCache_GetItemRequiresHashStorage(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if _, _, err := cache.GetItem("1"); !errors.Is(err, ErrNotHashStorage) {
+		t.Errorf("Expected ErrNotHashStorage, got %v", err)
+	}
+	if _, err := cache.GetItems([]string{"1"}); !errors.Is(err, ErrNotHashStorage) {
+		t.Errorf("Expected ErrNotHashStorage, got %v", err)
+	}
+}
+
+func TestRedisCache_SetItem(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig().WithPublishMetadata(true))
+	if err := cache.Set([]TestUser{{ID: "1", Name: "A"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	if err := cache.SetItem("2", TestUser{ID: "2", Name: "B"}); err != nil {
+		t.Fatalf("SetItem error: %v", err)
+	}
+	if err := cache.SetItem("1", TestUser{ID: "1", Name: "A2"}); err != nil {
+		t.Fatalf("SetItem error: %v", err)
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if version != 3 || len(values) != 2 || values[0].Name != "A2" || values[1].Name != "B" {
+		t.Errorf("Expected [A2 B] at version 3, got %+v at %d", values, version)
+	}
+	if meta, err := cache.Metadata(); err != nil || meta.Items != 2 {
+		t.Errorf("Expected metadata with 2 items, got %+v (%v)", meta, err)
+	}
+	if err := cache.SetItem("", TestUser{}); err == nil {
+		t.Error("Expected error for empty primary key")
+	}
+}
+
+func TestRedisCache_DeleteItem(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	deleted, err := cache.DeleteItem("1")
+	if err != nil || !deleted {
+		t.Fatalf("Expected item deleted, got %v (%v)", deleted, err)
+	}
+	if v, _ := cache.GetVersion(); v != 2 {
+		t.Errorf("Expected version 2 after delete, got %d", v)
+	}
+
+	deleted, err = cache.DeleteItem("missing")
+	if err != nil || deleted {
+		t.Errorf("Expected no-op for missing item, got %v (%v)", deleted, err)
+	}
+	if v, _ := cache.GetVersion(); v != 2 {
+		t.Errorf("Expected version unchanged by a no-op delete, got %d", v)
+	}
+
+	values, err := cache.Get()
+	if err != nil || len(values) != 1 || values[0].ID != "2" {
+		t.Errorf("Expected only item 2 left, got %+v (%v)", values, err)
+	}
+}
+
+func TestRedisCache_PartialWritesRequireHashStorage(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := cache.SetItem("1", TestUser{ID: "1"}); !errors.Is(err, ErrNotHashStorage) {
+		t.Errorf("Expected ErrNotHashStorage, got %v", err)
+	}
+	if _, err := cache.DeleteItem("1"); !errors.Is(err, ErrNotHashStorage) {
+		t.Errorf("Expected ErrNotHashStorage, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisType returns the Redis type (as reported by TYPE) of the data key in the current
+// storage mode.
+func (c *RedisCache[V]) redisType() string {
+	switch c.storage {
+	case storageHash:
+		return "hash"
+	case storageList:
+		return "list"
+	case storageJSON:
+		return "ReJSON-RL"
+	default:
+		return "string"
+	}
+}
+
+// Ping checks that Redis is reachable, for readiness probes that gate traffic on cache
+// availability. With RedisConfig.HealthCheckKeys it also checks, in the same round trip,
+// that the data and version keys are readable: the data key must have the type of the
+// storage mode and the version key must hold an integer. Missing keys pass, since an
+// empty cache is healthy.
+func (c *RedisCache[V]) Ping(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpPing, start, 0, err) }()
+
+	if c.client == nil {
+		return ErrNilClient
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	if !c.config.HealthCheckKeys {
+		if err := c.client.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("failed to ping: %w", err)
+		}
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	pipe.Ping(ctx)
+	typeCmd := pipe.Type(ctx, c.key)
+	versionCmd := pipe.Get(ctx, c.versionKey())
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to ping: %w", err)
+	}
+	if typ := typeCmd.Val(); typ != "none" && typ != c.redisType() {
+		return fmt.Errorf("cache key %q has type %s, expected %s", c.key, typ, c.redisType())
+	}
+	if _, err := versionCmd.Int64(); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to read version: %w", err)
+	}
+	return nil
+}
+
+// Healthy reports whether Ping succeeds, bounded by RedisConfig.OperationTimeout.
+func (c *RedisCache[V]) Healthy() bool {
+	return c.Ping(context.Background()) == nil
+}
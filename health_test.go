@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_Ping(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithHealthCheckKeys(true))
+
+	if err := cache.Ping(context.Background()); err != nil {
+		t.Errorf("Expected an empty cache to be healthy, got %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if !cache.Healthy() {
+		t.Error("Expected a healthy cache after Set")
+	}
+
+	// A data key of the wrong type fails the key check.
+	mr.Del(cache.key)
+	if _, err := mr.Lpush(cache.key, "x"); err != nil {
+		t.Fatalf("Lpush error: %v", err)
+	}
+	if err := cache.Ping(context.Background()); err == nil {
+		t.Error("Expected an error for a data key of the wrong type")
+	}
+	mr.Del(cache.key)
+
+	// A corrupt version key fails the key check.
+	if err := mr.Set(cache.versionKey(), "not-a-number"); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if cache.Healthy() {
+		t.Error("Expected an unreadable version key to be unhealthy")
+	}
+
+	// Without key checks only connectivity matters.
+	plain := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if !plain.Healthy() {
+		t.Error("Expected a plain ping to succeed")
+	}
+}
+
+func TestRedisCache_PingUnreachable(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithOperationTimeout(100*time.Millisecond))
+	mr.Close()
+	if cache.Healthy() {
+		t.Error("Expected an unreachable Redis to be unhealthy")
+	}
+	if NewRedisCache[TestUser](nil, DefaultRedisConfig()).Healthy() {
+		t.Error("Expected a nil client to be unhealthy")
+	}
+}
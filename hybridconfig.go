@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// HybridConfig gathers everything a HybridCache is built from, checked as a whole by
+// Validate before NewHybridCacheFromConfig builds the cache:
+//
+//	users, err := cache.NewHybridCacheFromConfig(
+//		cache.NewHybridConfig(memConfig, client).
+//			WithRedisConfig(cache.DefaultRedisConfig().WithKeyPrefix("users:")).
+//			WithLoader(loadUsers).
+//			WithErrorHandler(logTaskError))
+type HybridConfig[V any] struct {
+	// Memory configures the memory tier; it must set PrimaryKeyFunc.
+	Memory *Config[V]
+
+	// Client is the Redis client; it must not be nil.
+	Client redis.UniversalClient
+
+	// Redis configures the Redis tier; nil means DefaultRedisConfig().
+	Redis *RedisConfig
+
+	// WritePolicy selects which tiers Set writes, see HybridCache.WithWritePolicy.
+	WritePolicy WritePolicy
+
+	// EmptyRemotePolicy applies when LoadFromRedis finds no dataset, see
+	// HybridCache.WithEmptyRemotePolicy.
+	EmptyRemotePolicy EmptyRemotePolicy
+
+	// Loader is the source of truth of the dataset, see HybridCache.WithLoader.
+	Loader LoaderFunc[V]
+
+	// Merge merges Redis into memory on load, see HybridCache.WithMergeOnLoad.
+	Merge MergeFunc[V]
+
+	// ErrorHandler receives the errors of background tasks, see HybridCache.WithErrorHandler.
+	ErrorHandler ErrorHandler
+}
+
+// NewHybridConfig returns a HybridConfig for memory and client with the default Redis
+// configuration and policies.
+func NewHybridConfig[V any](memory *Config[V], client redis.UniversalClient) *HybridConfig[V] {
+	return &HybridConfig[V]{Memory: memory, Client: client, Redis: DefaultRedisConfig()}
+}
+
+// WithRedisConfig sets the Redis configuration.
+func (c *HybridConfig[V]) WithRedisConfig(config *RedisConfig) *HybridConfig[V] {
+	c.Redis = config
+	return c
+}
+
+// WithWritePolicy sets the write policy.
+func (c *HybridConfig[V]) WithWritePolicy(policy WritePolicy) *HybridConfig[V] {
+	c.WritePolicy = policy
+	return c
+}
+
+// WithEmptyRemotePolicy sets the policy for a missing Redis dataset.
+func (c *HybridConfig[V]) WithEmptyRemotePolicy(policy EmptyRemotePolicy) *HybridConfig[V] {
+	c.EmptyRemotePolicy = policy
+	return c
+}
+
+// WithLoader sets the loader.
+func (c *HybridConfig[V]) WithLoader(loader LoaderFunc[V]) *HybridConfig[V] {
+	c.Loader = loader
+	return c
+}
+
+// WithMergeOnLoad sets the merge function.
+func (c *HybridConfig[V]) WithMergeOnLoad(merge MergeFunc[V]) *HybridConfig[V] {
+	c.Merge = merge
+	return c
+}
+
+// WithErrorHandler sets the background error handler.
+func (c *HybridConfig[V]) WithErrorHandler(handler ErrorHandler) *HybridConfig[V] {
+	c.ErrorHandler = handler
+	return c
+}
+
+// WithMetricsHook sets RedisConfig.MetricsHook, creating the default Redis configuration
+// if none is set.
+func (c *HybridConfig[V]) WithMetricsHook(hook MetricsHook) *HybridConfig[V] {
+	if c.Redis == nil {
+		c.Redis = DefaultRedisConfig()
+	}
+	c.Redis.MetricsHook = hook
+	return c
+}
+
+// WithLogger sets Config.Logger of the memory configuration, if any, and
+// RedisConfig.Logger, creating the default Redis configuration if none is set.
+func (c *HybridConfig[V]) WithLogger(logger *slog.Logger) *HybridConfig[V] {
+	if c.Memory != nil {
+		c.Memory.Logger = logger
+	}
+	if c.Redis == nil {
+		c.Redis = DefaultRedisConfig()
+	}
+	c.Redis.Logger = logger
+	return c
+}
+
+// Validate reports every problem that would make NewHybridCache panic or build a cache
+// that cannot store data: a missing memory configuration or primary key, a nil client,
+// Redis key settings NewRedisCache rejects, and unknown policies. The problems are joined
+// into one error.
+func (c *HybridConfig[V]) Validate() error {
+	var errs []error
+	if c.Memory == nil || c.Memory.PrimaryKeyFunc == nil {
+		errs = append(errs, errors.New("cache-kit: HybridConfig.Memory must set PrimaryKeyFunc"))
+	}
+	if nilIfTypedNil(c.Client) == nil {
+		errs = append(errs, ErrNilClient)
+	}
+	if c.Redis != nil {
+		if _, err := redisCacheKeys(c.Redis); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.WritePolicy < WriteThrough || c.WritePolicy > WriteRedisOnly {
+		errs = append(errs, fmt.Errorf("cache-kit: unknown write policy %d", c.WritePolicy))
+	}
+	if c.EmptyRemotePolicy < EmptyRemoteClear || c.EmptyRemotePolicy > EmptyRemoteError {
+		errs = append(errs, fmt.Errorf("cache-kit: unknown empty remote policy %d", c.EmptyRemotePolicy))
+	}
+	return errors.Join(errs...)
+}
+
+// NewHybridCacheFromConfig validates config and creates the HybridCache it describes,
+// returning the Validate error instead of panicking.
+func NewHybridCacheFromConfig[V any](config *HybridConfig[V]) (*HybridCache[V], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	c := NewHybridCache(config.Memory, config.Client, config.Redis).
+		WithEmptyRemotePolicy(config.EmptyRemotePolicy).
+		WithLoader(config.Loader).
+		WithMergeOnLoad(config.Merge).
+		WithErrorHandler(config.ErrorHandler)
+	if config.WritePolicy != WriteThrough {
+		c.WithWritePolicy(config.WritePolicy)
+	}
+	return c, nil
+}
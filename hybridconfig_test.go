@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestHybridConfig_Validate(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	var nilClient *redis.Client
+
+	tests := []struct {
+		name    string
+		config  *HybridConfig[TestUser]
+		wantErr string
+	}{
+		{"valid", NewHybridConfig(userConfig(), client), ""},
+		{"nil memory", NewHybridConfig[TestUser](nil, client), "PrimaryKeyFunc"},
+		{"no primary key", NewHybridConfig(DefaultConfig[TestUser](), client), "PrimaryKeyFunc"},
+		{"nil client", NewHybridConfig(userConfig(), nil), ErrNilClient.Error()},
+		{"typed nil client", NewHybridConfig(userConfig(), redis.UniversalClient(nilClient)), ErrNilClient.Error()},
+		{"empty prefix", NewHybridConfig(userConfig(), client).WithRedisConfig(DefaultRedisConfig().WithKeyPrefix("")), "KeyPrefix"},
+		{"invalid key builder", NewHybridConfig(userConfig(), client).WithRedisConfig(DefaultRedisConfig().WithKeyBuilder(SegmentedKeys{App: "app"})), "Env"},
+		{"write policy", NewHybridConfig(userConfig(), client).WithWritePolicy(WritePolicy(9)), "write policy"},
+		{"empty remote policy", NewHybridConfig(userConfig(), client).WithEmptyRemotePolicy(EmptyRemotePolicy(-1)), "empty remote policy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Every problem is reported at once.
+	err := NewHybridConfig[TestUser](nil, nil).Validate()
+	if !errors.Is(err, ErrNilClient) || !strings.Contains(err.Error(), "PrimaryKeyFunc") {
+		t.Errorf("Expected both problems to be reported, got %v", err)
+	}
+}
+
+func TestNewHybridCacheFromConfig(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	if _, err := NewHybridCacheFromConfig(NewHybridConfig(userConfig(), nil)); !errors.Is(err, ErrNilClient) {
+		t.Errorf("Expected the Validate error instead of a panic, got %v", err)
+	}
+
+	hook := &recordingHook{}
+	config := NewHybridConfig(userConfig(), client).
+		WithRedisConfig(DefaultRedisConfig().WithKeyPrefix("users:")).
+		WithWritePolicy(WriteMemoryOnly).
+		WithEmptyRemotePolicy(EmptyRemoteError).
+		WithMetricsHook(hook)
+	cache, err := NewHybridCacheFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewHybridCacheFromConfig error: %v", err)
+	}
+	if cache.WritePolicy() != WriteMemoryOnly {
+		t.Errorf("Expected the write policy to be applied, got %v", cache.WritePolicy())
+	}
+	if err := cache.LoadFromRedis(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected the empty remote policy to be applied, got %v", err)
+	}
+	if hook.last().op == "" {
+		t.Error("Expected the metrics hook to be installed")
+	}
+}
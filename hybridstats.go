@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// HybridStats describes the traffic a HybridCache served since it was created.
+type HybridStats struct {
+	// MemoryHits is the number of Get and GetByIndex calls memory answered.
+	MemoryHits uint64
+
+	// MemoryMisses is the number of Get and GetByIndex calls that found nothing in
+	// memory: the traffic the memory tier did not absorb.
+	MemoryMisses uint64
+
+	// Loads is the number of LoadFromRedis calls, LoadErrors of those that failed.
+	Loads      uint64
+	LoadErrors uint64
+
+	// LoadDuration is the total time spent in LoadFromRedis, LastLoadDuration the time
+	// of the latest call.
+	LoadDuration     time.Duration
+	LastLoadDuration time.Duration
+
+	// LastLoad is when the latest successful LoadFromRedis finished; zero if none did.
+	LastLoad time.Time
+
+	// LastSync is when this instance last wrote the dataset to Redis (Set, SyncToRedis,
+	// RebuildRedis, Delete); zero if it never did.
+	LastSync time.Time
+
+	// LoadedVersion is the Redis version memory holds (see HybridCache.LoadedVersion).
+	LoadedVersion int64
+}
+
+// hybridCounters backs HybridStats.
+type hybridCounters struct {
+	hits, misses    atomic.Uint64
+	loads, loadErrs atomic.Uint64
+	loadNanos       atomic.Int64
+	lastLoadNanos   atomic.Int64
+	lastLoad        atomic.Int64 // unix nanoseconds
+	lastSync        atomic.Int64 // unix nanoseconds
+}
+
+// recordLoad counts a LoadFromRedis that started at start and returned err.
+func (s *hybridCounters) recordLoad(start time.Time, err error) {
+	d := time.Since(start)
+	s.loads.Add(1)
+	s.loadNanos.Add(int64(d))
+	s.lastLoadNanos.Store(int64(d))
+	if err != nil {
+		s.loadErrs.Add(1)
+		return
+	}
+	s.lastLoad.Store(time.Now().UnixNano())
+}
+
+// Stats returns the hybrid-level counters, e.g. to chart how much traffic memory absorbs.
+func (c *HybridCache[V]) Stats() HybridStats {
+	s := &c.stats
+	return HybridStats{
+		MemoryHits:       s.hits.Load(),
+		MemoryMisses:     s.misses.Load(),
+		Loads:            s.loads.Load(),
+		LoadErrors:       s.loadErrs.Load(),
+		LoadDuration:     time.Duration(s.loadNanos.Load()),
+		LastLoadDuration: time.Duration(s.lastLoadNanos.Load()),
+		LastLoad:         unixNanoTime(s.lastLoad.Load()),
+		LastSync:         unixNanoTime(s.lastSync.Load()),
+		LoadedVersion:    c.version.Load(),
+	}
+}
+
+// unixNanoTime converts unix nanoseconds to a time, mapping 0 to the zero time.
+func unixNanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
@@ -0,0 +1,43 @@
+package cache
+
+import "testing"
+
+func TestHybridCache_Stats(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	if stats := cache.Stats(); stats != (HybridStats{}) {
+		t.Errorf("Expected zero stats for a new cache, got %+v", stats)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	cache.GetByIndex("email", "a@example.com")
+	cache.GetByIndex("email", "missing@example.com")
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	mr.SetError("LOADING")
+	if err := cache.LoadFromRedis(); err == nil {
+		t.Fatal("Expected LoadFromRedis to fail")
+	}
+
+	stats := cache.Stats()
+	if stats.MemoryHits != 1 || stats.MemoryMisses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %+v", stats)
+	}
+	if stats.Loads != 2 || stats.LoadErrors != 1 {
+		t.Errorf("Expected 2 loads with 1 error, got %+v", stats)
+	}
+	if stats.LoadDuration < stats.LastLoadDuration || stats.LoadDuration == 0 {
+		t.Errorf("Unexpected load durations: %+v", stats)
+	}
+	if stats.LastLoad.IsZero() || stats.LastSync.IsZero() {
+		t.Errorf("Expected load and sync times, got %+v", stats)
+	}
+	if stats.LoadedVersion != 1 {
+		t.Errorf("Expected loaded version 1, got %d", stats.LoadedVersion)
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// indexUsage tracks how an index is queried. Fields are atomic so GetByIndex
+// can record usage while holding only the read lock.
+type indexUsage struct {
+	created  time.Time
+	lastUsed atomic.Int64 // unix nanoseconds of the last GetByIndex, 0 if never used
+	lookups  atomic.Uint64
+}
+
+// IndexUsage describes how an index has been used since it was registered.
+type IndexUsage struct {
+	// Name is the index name.
+	Name string
+
+	// Lookups is the number of GetByIndex calls against the index. Lookups are only
+	// counted while Config.IndexColdAfter is set.
+	Lookups uint64
+
+	// LastUsed is the time of the last lookup; zero if the index was never queried.
+	LastUsed time.Time
+
+	// Idle is the time since the last lookup, or since registration if never queried.
+	Idle time.Duration
+
+	// Cold reports whether Idle exceeds Config.IndexColdAfter (always false when it is not set).
+	Cold bool
+}
+
+// record notes a lookup at now.
+func (u *indexUsage) record(now time.Time) {
+	u.lookups.Add(1)
+	u.lastUsed.Store(now.UnixNano())
+}
+
+// idle returns how long the index has gone unqueried at now.
+func (u *indexUsage) idle(now time.Time) time.Duration {
+	since := u.created
+	if last := u.lastUsed.Load(); last != 0 {
+		since = time.Unix(0, last)
+	}
+	return now.Sub(since)
+}
+
+// IndexUsageReport returns usage information for every registered index, sorted by name,
+// so large deployments can find and shed indexes nobody queries anymore.
+func (c *MemoryCache[V]) IndexUsageReport() []IndexUsage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	report := make([]IndexUsage, 0, len(c.usage))
+	for name, u := range c.usage {
+		entry := IndexUsage{
+			Name:    name,
+			Lookups: u.lookups.Load(),
+			Idle:    u.idle(now),
+		}
+		if last := u.lastUsed.Load(); last != 0 {
+			entry.LastUsed = time.Unix(0, last)
+		}
+		entry.Cold = c.config.IndexColdAfter > 0 && entry.Idle > c.config.IndexColdAfter
+		report = append(report, entry)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Name < report[j].Name })
+	return report
+}
+
+// DropColdIndexes removes every index idle for longer than Config.IndexColdAfter
+// and returns the dropped names. It is a no-op when IndexColdAfter is not set.
+func (c *MemoryCache[V]) DropColdIndexes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.dropColdIndexes()
+}
+
+// dropColdIndexes removes cold indexes. The caller must hold the write lock.
+func (c *MemoryCache[V]) dropColdIndexes() []string {
+	if c.config.IndexColdAfter <= 0 {
+		return nil
+	}
+	now := c.now()
+	var dropped []string
+	for name, u := range c.usage {
+		if u.idle(now) > c.config.IndexColdAfter {
+			c.removeIndex(name)
+			dropped = append(dropped, name)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for tests.
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.t }
+
+func (f *fakeClock) Advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func TestMemoryCache_IndexUsageReport(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithColdIndexPolicy(time.Hour, false))
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache.now = clock.Now
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.AddIndex("phone", func(u TestUser) string { return u.Phone })
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com", Phone: "1"}})
+
+	clock.Advance(30 * time.Minute)
+	cache.GetByIndex("email", "a@example.com")
+	cache.GetByIndex("email", "missing@example.com")
+	clock.Advance(45 * time.Minute)
+
+	report := cache.IndexUsageReport()
+	if len(report) != 2 || report[0].Name != "email" || report[1].Name != "phone" {
+		t.Fatalf("Expected report for [email phone], got %+v", report)
+	}
+
+	email := report[0]
+	if email.Lookups != 2 {
+		t.Errorf("Expected 2 lookups, got %d", email.Lookups)
+	}
+	if email.Idle != 45*time.Minute || email.Cold {
+		t.Errorf("Expected email warm with 45m idle, got %+v", email)
+	}
+	if !email.LastUsed.Equal(clock.t.Add(-45 * time.Minute)) {
+		t.Errorf("Unexpected LastUsed %v", email.LastUsed)
+	}
+
+	phone := report[1]
+	if phone.Lookups != 0 || !phone.LastUsed.IsZero() {
+		t.Errorf("Expected phone never used, got %+v", phone)
+	}
+	if phone.Idle != 75*time.Minute || !phone.Cold {
+		t.Errorf("Expected phone cold with 75m idle, got %+v", phone)
+	}
+}
+
+func TestMemoryCache_DropColdIndexes(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithColdIndexPolicy(time.Hour, false))
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache.now = clock.Now
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.AddIndex("phone", func(u TestUser) string { return u.Phone })
+
+	clock.Advance(50 * time.Minute)
+	cache.GetByIndex("email", "x")
+	clock.Advance(20 * time.Minute)
+
+	dropped := cache.DropColdIndexes()
+	if len(dropped) != 1 || dropped[0] != "phone" {
+		t.Errorf("Expected [phone] dropped, got %v", dropped)
+	}
+	if cache.HasIndex("phone") || !cache.HasIndex("email") {
+		t.Error("Expected only email index left")
+	}
+	if len(cache.IndexUsageReport()) != 1 {
+		t.Error("Expected usage of dropped index removed")
+	}
+}
+
+func TestMemoryCache_AutoDropColdIndexesOnSet(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithColdIndexPolicy(time.Hour, true))
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache.now = clock.Now
+	cache.AddIndex("phone", func(u TestUser) string { return u.Phone })
+
+	cache.Set([]TestUser{{ID: "1", Phone: "1"}})
+	if !cache.HasIndex("phone") {
+		t.Fatal("Expected index kept while warm")
+	}
+
+	clock.Advance(2 * time.Hour)
+	cache.Set([]TestUser{{ID: "1", Phone: "1"}})
+	if cache.HasIndex("phone") {
+		t.Error("Expected cold index dropped on Set")
+	}
+}
+
+func TestMemoryCache_AbortedSetKeepsColdIndexes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := userConfig().
+		WithColdIndexPolicy(time.Hour, true).
+		WithValidateFunc(func(TestUser) error { cancel(); return nil })
+	cache := NewMultiIndexCache(config)
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache.now = clock.Now
+	cache.AddIndex("phone", func(u TestUser) string { return u.Phone })
+
+	clock.Advance(2 * time.Hour)
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "1", Phone: "1"}}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if !cache.HasIndex("phone") {
+		t.Error("Expected an aborted Set to keep the cold index")
+	}
+}
+
+func TestMemoryCache_DropColdIndexesDisabled(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig())
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache.now = clock.Now
+	cache.AddIndex("phone", func(u TestUser) string { return u.Phone })
+	clock.Advance(1000 * time.Hour)
+
+	if dropped := cache.DropColdIndexes(); len(dropped) != 0 {
+		t.Errorf("Expected nothing dropped without IndexColdAfter, got %v", dropped)
+	}
+	cache.GetByIndex("phone", "1")
+	if report := cache.IndexUsageReport(); report[0].Cold || report[0].Lookups != 0 {
+		t.Errorf("Expected usage untracked without IndexColdAfter, got %+v", report[0])
+	}
+}
+
+func TestMemoryCache_AddIndexResetsUsage(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithColdIndexPolicy(time.Hour, false))
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.GetByIndex("email", "x")
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	if report := cache.IndexUsageReport(); report[0].Lookups != 0 {
+		t.Errorf("Expected usage reset on re-registration, got %d lookups", report[0].Lookups)
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Label names of the series a Collector records, the same for every cache type.
+const (
+	LabelCacheName = "cache_name"
+	LabelTier      = "tier"
+	LabelOp        = "op"
+)
+
+// Tier label values.
+const (
+	TierMemory = "memory" // MemoryCache, or the memory tier of a HybridCache
+	TierRedis  = "redis"  // RedisCache, or the Redis tier of a HybridCache
+	TierHybrid = "hybrid" // operations spanning both tiers, such as LoadFromRedis
+)
+
+// MetricLabels identifies the series of an observation. Op is empty for TierStats.
+type MetricLabels struct {
+	CacheName string
+	Tier      string
+	Op        string
+}
+
+// TierStats is a reading of the counters of a tier, taken whenever a Collector is
+// scraped.
+type TierStats struct {
+	Items  int    // entries held
+	Hits   uint64 // lookups that found a value
+	Misses uint64 // lookups that found nothing
+
+	// Staleness is the time since the tier last loaded or wrote its dataset; 0 if the
+	// tier does not track it (a standalone MemoryCache) or never did.
+	Staleness time.Duration
+}
+
+// Collector receives the metrics of caches wired with Instrument, e.g. to export them to
+// Prometheus (see cachemetrics.NewCollector). Methods must be safe for concurrent use.
+type Collector interface {
+	// ObserveOp records one operation, after it completes, with its duration, encoded
+	// payload size (0 for operations without one) and error. The op label is one of the
+	// Op constants.
+	ObserveOp(labels MetricLabels, dur time.Duration, bytes int, err error)
+
+	// AddStats registers a tier whose counters are read by calling stats, typically on
+	// every scrape. Registering the same CacheName and Tier again replaces the earlier
+	// stats, so one series is exported per tier.
+	AddStats(labels MetricLabels, stats func() TierStats)
+}
+
+// Instrumentable is a cache Instrument can wire: *MemoryCache, *RedisCache or
+// *HybridCache.
+type Instrumentable interface {
+	instrument(name string, collector Collector)
+}
+
+// Instrument reports the metrics of c to collector under the cache_name label name:
+//
+//   - a MemoryCache registers its size and lookup counters as TierStats of TierMemory;
+//   - a RedisCache reports every operation to ObserveOp under TierRedis, next to any
+//     RedisConfig.MetricsHook;
+//   - a HybridCache does both for its tiers, with the staleness of memory (see
+//     HybridStats.LastLoad and LastSync), and reports LoadFromRedis as OpLoad under
+//     TierHybrid.
+//
+// Call it once per cache, before serving traffic: instrumenting a RedisCache again
+// replaces its collector, while stats registered with a previous collector stay there.
+func Instrument(name string, c Instrumentable, collector Collector) {
+	c.instrument(name, collector)
+}
+
+// instrumentation is the collector a cache reports to, see Instrument.
+type instrumentation struct {
+	name      string
+	collector Collector
+}
+
+// instrumentSlot holds the instrumentation of a RedisCache or HybridCache, nil until
+// Instrument is called.
+type instrumentSlot struct {
+	atomic.Pointer[instrumentation]
+}
+
+func (c *MemoryCache[V]) instrument(name string, collector Collector) {
+	collector.AddStats(MetricLabels{CacheName: name, Tier: TierMemory}, func() TierStats {
+		stats := c.LookupStats()
+		return TierStats{Items: c.Len(), Hits: stats.Hits, Misses: stats.Misses}
+	})
+}
+
+func (c *RedisCache[V]) instrument(name string, collector Collector) {
+	c.instrumented.Store(&instrumentation{name: name, collector: collector})
+}
+
+func (c *HybridCache[V]) instrument(name string, collector Collector) {
+	collector.AddStats(MetricLabels{CacheName: name, Tier: TierMemory}, func() TierStats {
+		lookups, stats := c.memory.LookupStats(), c.Stats()
+		tier := TierStats{Items: c.memory.Len(), Hits: lookups.Hits, Misses: lookups.Misses}
+		last := stats.LastLoad
+		if stats.LastSync.After(last) {
+			last = stats.LastSync
+		}
+		if !last.IsZero() {
+			tier.Staleness = time.Since(last)
+		}
+		return tier
+	})
+	if c.redis != nil {
+		c.redis.instrument(name, collector)
+	}
+	c.instrumented.Store(&instrumentation{name: name, collector: collector})
+}
+
+// observeLoad reports a LoadFromRedis that began at start to the Instrument collector.
+func (c *HybridCache[V]) observeLoad(start time.Time, err error) {
+	if in := c.instrumented.Load(); in != nil {
+		in.collector.ObserveOp(MetricLabels{CacheName: in.name, Tier: TierHybrid, Op: OpLoad}, time.Since(start), 0, err)
+	}
+}
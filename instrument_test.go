@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingCollector is a Collector keeping every observation.
+type recordingCollector struct {
+	mu    sync.Mutex
+	ops   []MetricLabels
+	errs  int
+	stats map[MetricLabels]func() TierStats
+}
+
+func (r *recordingCollector) ObserveOp(labels MetricLabels, dur time.Duration, bytes int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, labels)
+	if err != nil {
+		r.errs++
+	}
+}
+
+func (r *recordingCollector) AddStats(labels MetricLabels, stats func() TierStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stats == nil {
+		r.stats = make(map[MetricLabels]func() TierStats)
+	}
+	r.stats[labels] = stats
+}
+
+func (r *recordingCollector) has(labels MetricLabels) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, l := range r.ops {
+		if l == labels {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInstrument_Memory(t *testing.T) {
+	mem := NewMultiIndexCache(userConfig())
+	collector := &recordingCollector{}
+	Instrument("users", mem, collector)
+
+	mem.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+	mem.Get("1")
+	mem.Get("missing")
+
+	stats, ok := collector.stats[MetricLabels{CacheName: "users", Tier: TierMemory}]
+	if !ok {
+		t.Fatalf("Expected memory stats registered, got %v", collector.stats)
+	}
+	if got := stats(); got != (TierStats{Items: 2, Hits: 1, Misses: 1}) {
+		t.Errorf("Unexpected memory stats %+v", got)
+	}
+}
+
+func TestInstrument_Redis(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	var hooked int
+	rc := NewRedisCache[TestUser](client, DefaultRedisConfig().WithMetricsHook(MetricsHookFunc(
+		func(op string, dur time.Duration, bytes int, err error) { hooked++ })))
+	collector := &recordingCollector{}
+	Instrument("users", rc, collector)
+
+	if err := rc.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if !collector.has(MetricLabels{CacheName: "users", Tier: TierRedis, Op: OpSet}) {
+		t.Errorf("Expected set observed, got %v", collector.ops)
+	}
+	if hooked != 1 {
+		t.Errorf("Expected MetricsHook still called, got %d calls", hooked)
+	}
+
+	mr.SetError("LOADING")
+	if _, err := rc.Get(); err == nil {
+		t.Fatal("Expected Get to fail")
+	}
+	if collector.errs != 1 {
+		t.Errorf("Expected 1 failed op, got %d", collector.errs)
+	}
+}
+
+func TestInstrument_Hybrid(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	collector := &recordingCollector{}
+	Instrument("users", cache, collector)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	cache.Get("1")
+
+	for _, want := range []MetricLabels{
+		{CacheName: "users", Tier: TierRedis, Op: OpSet},
+		{CacheName: "users", Tier: TierRedis, Op: OpGet},
+		{CacheName: "users", Tier: TierHybrid, Op: OpLoad},
+	} {
+		if !collector.has(want) {
+			t.Errorf("Expected %+v observed, got %v", want, collector.ops)
+		}
+	}
+	stats := collector.stats[MetricLabels{CacheName: "users", Tier: TierMemory}]
+	if stats == nil {
+		t.Fatalf("Expected memory stats registered, got %v", collector.stats)
+	}
+	got := stats()
+	if got.Items != 1 || got.Hits != 1 || got.Misses != 0 {
+		t.Errorf("Unexpected memory tier stats %+v", got)
+	}
+	if got.Staleness <= 0 || got.Staleness > time.Minute {
+		t.Errorf("Expected staleness since the load, got %v", got.Staleness)
+	}
+}
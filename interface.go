@@ -15,6 +15,11 @@
 //	user, ok := cache.GetByIndex("email", "user@example.com")
 package cache
 
+import (
+	"context"
+	"time"
+)
+
 // Cache provides the basic cache interface.
 type Cache[K comparable, V any] interface {
 	// Get retrieves a value by its primary key.
@@ -76,6 +81,56 @@ type MultiIndexCache[V any] interface {
 	Iterate(fn func(value V) bool)
 }
 
+// Hybrid is the dataset API of *HybridCache. Code that depends on Hybrid instead of
+// *HybridCache can be unit-tested against cachetest.FakeHybrid without a Redis server.
+type Hybrid[V any] interface {
+	// Set stores values in memory and Redis.
+	Set(values []V) error
+
+	// Get retrieves a value by its primary key.
+	Get(pk string) (V, bool)
+
+	// GetByIndex retrieves a value by a named index.
+	GetByIndex(indexName string, key string) (V, bool)
+
+	// GetAll returns all cached values in insertion order.
+	GetAll() []V
+
+	// Delete removes the value stored under pk from memory and Redis.
+	Delete(pk string) error
+
+	// LoadFromRedis replaces memory with the Redis dataset.
+	LoadFromRedis() error
+
+	// SyncToRedis writes memory to Redis.
+	SyncToRedis() error
+}
+
+// RemoteStore is the shared tier of a HybridCache: a versioned dataset readable by every
+// instance. RedisCache implements it; other backends plug in with NewHybridCacheWithStore.
+type RemoteStore[V any] interface {
+	// GetCtx returns the stored dataset, or an empty slice if there is none.
+	GetCtx(ctx context.Context) ([]V, error)
+
+	// SetCtx replaces the dataset and bumps the version. HybridCache passes no options;
+	// read those of other callers with ApplySetOptions, and fail with
+	// ErrUnsupportedSetOption for any the store cannot apply rather than ignoring it.
+	SetCtx(ctx context.Context, values []V, opts ...SetOption) error
+
+	// ExistsCtx reports whether a dataset, possibly empty, is stored.
+	ExistsCtx(ctx context.Context) (bool, error)
+
+	// GetVersionCtx returns the dataset version, 0 if none is stored.
+	GetVersionCtx(ctx context.Context) (int64, error)
+
+	// ClearCtx deletes the dataset and its version.
+	ClearCtx(ctx context.Context) error
+
+	// TTLCtx returns the remaining lifetime of the dataset: -2 if none is stored, -1 if it
+	// does not expire.
+	TTLCtx(ctx context.Context) (time.Duration, error)
+}
+
 // HashFunc defines a function that computes a hash for a value.
 type HashFunc[V any] func(values []V) string
 
@@ -89,3 +144,7 @@ type ValidateFunc[V any] func(value V) error
 // NormalizeFunc defines a function that normalizes a value.
 // Returns the normalized value.
 type NormalizeFunc[V any] func(value V) V
+
+// LoaderFunc defines a function that loads the full dataset from its source of truth,
+// such as a database or an API.
+type LoaderFunc[V any] func(ctx context.Context) ([]V, error)
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyspacePrefix is the channel prefix of Redis keyspace notifications: __keyspace@<db>__:<key>.
+const keyspacePrefix = "__keyspace@"
+
+// InvalidationEvent is a change reported by an InvalidationSubscriber.
+type InvalidationEvent struct {
+	// Channel is the pub/sub channel the message arrived on.
+	Channel string
+	// Key is the key that changed, for keyspace notifications; empty for channel messages.
+	Key string
+	// Event is the keyspace event (e.g. "set", "hset", "del", "expired") or, for
+	// channel messages, the message payload.
+	Event string
+}
+
+// Expired reports whether the event is a keyspace expiry or eviction.
+func (e InvalidationEvent) Expired() bool {
+	return e.Key != "" && (e.Event == "expired" || e.Event == "evicted")
+}
+
+// InvalidationSubscriber listens on Redis pub/sub for changes to cached keys: either keyspace
+// notifications (NewKeyspaceSubscriber) or an application channel (NewChannelSubscriber).
+//
+// Keyspace notifications are off by default in Redis; enable them with e.g.
+// `CONFIG SET notify-keyspace-events Kgx$h` (K: keyspace channel, g: del/expire, x: expired,
+// $: strings, h: hashes). On Redis Cluster they are only delivered by the node holding the key,
+// so prefer a channel there.
+type InvalidationSubscriber struct {
+	client   redis.UniversalClient
+	channels []string
+	keys     map[string]string // keyspace channel -> key
+}
+
+// NewKeyspaceSubscriber returns a subscriber for keyspace notifications on keys, in the
+// database selected by client (0 for cluster and ring clients).
+func NewKeyspaceSubscriber(client redis.UniversalClient, keys ...string) *InvalidationSubscriber {
+	prefix := fmt.Sprintf("%s%d__:", keyspacePrefix, clientDB(client))
+	s := &InvalidationSubscriber{client: nilIfTypedNil(client), keys: make(map[string]string, len(keys))}
+	for _, key := range keys {
+		channel := prefix + key
+		s.channels = append(s.channels, channel)
+		s.keys[channel] = key
+	}
+	return s
+}
+
+// NewChannelSubscriber returns a subscriber for messages published on channels, e.g. by
+// producers running `PUBLISH <channel> <version>` after a write.
+func NewChannelSubscriber(client redis.UniversalClient, channels ...string) *InvalidationSubscriber {
+	return &InvalidationSubscriber{client: nilIfTypedNil(client), channels: channels}
+}
+
+// clientDB returns the database selected by client, or 0 if it has no single database.
+func clientDB(client redis.UniversalClient) int {
+	if c, ok := client.(interface{ Options() *redis.Options }); ok {
+		return c.Options().DB
+	}
+	return 0
+}
+
+// InvalidationSubscriber returns a subscriber for changes to this cache: messages on
+// RedisConfig.InvalidationChannel if set, otherwise keyspace notifications for the data
+// and version keys.
+func (c *RedisCache[V]) InvalidationSubscriber() *InvalidationSubscriber {
+	if c.config.InvalidationChannel != "" {
+		return NewChannelSubscriber(c.client, c.config.InvalidationChannel)
+	}
+	return NewKeyspaceSubscriber(c.client, c.key, c.versionKey())
+}
+
+// Run subscribes and calls fn for every event until ctx is done, then returns ctx.Err().
+// fn runs on the subscriber goroutine; events arriving while it runs are queued by the client.
+// Returns an error if the subscription cannot be established. Lost connections are
+// re-established by the client; events published meanwhile are lost.
+func (s *InvalidationSubscriber) Run(ctx context.Context, fn func(InvalidationEvent)) error {
+	if s.client == nil {
+		return ErrNilClient
+	}
+	if len(s.channels) == 0 {
+		return fmt.Errorf("no channels to subscribe to")
+	}
+
+	pubsub := s.client.Subscribe(ctx, s.channels...)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return ctx.Err()
+			}
+			fn(s.event(msg))
+		}
+	}
+}
+
+// Events runs the subscriber in the background and signals events on the returned channel,
+// which is closed once ctx is done or the subscription fails. Events arriving while one is
+// pending are coalesced, so the channel suits EventDriven refresh policies.
+func (s *InvalidationSubscriber) Events(ctx context.Context) <-chan InvalidationEvent {
+	events := make(chan InvalidationEvent, 1)
+	go func() {
+		defer close(events)
+		_ = s.Run(ctx, func(e InvalidationEvent) {
+			select {
+			case events <- e:
+			default:
+			}
+		})
+	}()
+	return events
+}
+
+// event converts a pub/sub message.
+func (s *InvalidationSubscriber) event(msg *redis.Message) InvalidationEvent {
+	e := InvalidationEvent{Channel: msg.Channel, Event: msg.Payload}
+	e.Key = s.keys[msg.Channel]
+	return e
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// waitSubscribed blocks until channel has a subscriber.
+func waitSubscribed(t *testing.T, mr *miniredis.Miniredis, channel string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for mr.PubSubNumSub(channel)[channel] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for a subscriber on %s", channel)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestInvalidationSubscriber_Keyspace(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	sub := cache.InvalidationSubscriber()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	got := make(chan InvalidationEvent, 2)
+	done := make(chan error, 1)
+	go func() { done <- sub.Run(ctx, func(e InvalidationEvent) { got <- e }) }()
+
+	// miniredis does not emit keyspace notifications; publish them as Redis would.
+	waitSubscribed(t, mr, "__keyspace@0__:cache:data")
+	mr.Publish("__keyspace@0__:cache:data", "expired")
+	mr.Publish("__keyspace@0__:cache:data:version", "incrby")
+
+	e := <-got
+	if e.Key != "cache:data" || e.Event != "expired" || !e.Expired() {
+		t.Errorf("Expected data key expiry, got %+v", e)
+	}
+	e = <-got
+	if e.Key != "cache:data:version" || e.Event != "incrby" || e.Expired() {
+		t.Errorf("Expected version key incrby, got %+v", e)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInvalidationSubscriber_Channel(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithInvalidationChannel("users:changed"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := cache.InvalidationSubscriber().Events(ctx)
+
+	waitSubscribed(t, mr, "users:changed")
+	mr.Publish("users:changed", "42")
+	select {
+	case e := <-events:
+		if e.Channel != "users:changed" || e.Key != "" || e.Event != "42" {
+			t.Errorf("Expected channel message 42, got %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestInvalidationSubscriber_Errors(t *testing.T) {
+	if err := NewChannelSubscriber(nil, "x").Run(context.Background(), func(InvalidationEvent) {}); err == nil {
+		t.Error("Expected error for nil client")
+	}
+	_, client := setupMiniRedis(t)
+	if err := NewKeyspaceSubscriber(client).Run(context.Background(), func(InvalidationEvent) {}); err == nil {
+		t.Error("Expected error without channels")
+	}
+}
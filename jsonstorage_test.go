@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2/server"
+)
+
+// idFilter matches the one JSONPath filter the fake JSON.GET understands.
+var idFilter = regexp.MustCompile(`^\$\[\?\(@\.ID=="([^"]*)"\)\]$`)
+
+// newJSONCache returns a JSON storage cache on miniredis with minimal JSON.SET and JSON.GET
+// commands registered. Documents live outside the miniredis keyspace, which handlers cannot
+// touch while a script holds it.
+func newJSONCache(t *testing.T, config *RedisConfig) *RedisCache[TestUser] {
+	mr, client := setupMiniRedis(t)
+
+	var mu sync.Mutex
+	docs := map[string]string{}
+	err := mr.Server().Register("JSON.SET", func(c *server.Peer, cmd string, args []string) {
+		if len(args) != 3 || args[1] != "$" {
+			c.WriteError("ERR unsupported JSON.SET")
+			return
+		}
+		mu.Lock()
+		docs[args[0]] = args[2]
+		mu.Unlock()
+		c.WriteOK()
+	})
+	if err != nil {
+		t.Fatalf("Register JSON.SET: %v", err)
+	}
+	err = mr.Server().Register("JSON.GET", func(c *server.Peer, cmd string, args []string) {
+		mu.Lock()
+		doc, ok := docs[args[0]]
+		mu.Unlock()
+		switch {
+		case !ok:
+			c.WriteNull()
+		case len(args) == 1:
+			c.WriteBulk(doc)
+		case args[1] == "$":
+			c.WriteBulk("[" + doc + "]")
+		case idFilter.MatchString(args[1]):
+			var records []map[string]any
+			if err := json.Unmarshal([]byte(doc), &records); err != nil {
+				c.WriteError("ERR " + err.Error())
+				return
+			}
+			id := idFilter.FindStringSubmatch(args[1])[1]
+			matched := []map[string]any{}
+			for _, r := range records {
+				if r["ID"] == id {
+					matched = append(matched, r)
+				}
+			}
+			data, _ := json.Marshal(matched)
+			c.WriteBulk(string(data))
+		default:
+			c.WriteError("ERR unsupported path")
+		}
+	})
+	if err != nil {
+		t.Fatalf("Register JSON.GET: %v", err)
+	}
+
+	return NewRedisCache[TestUser](client, config).WithJSONStorage()
+}
+
+func TestRedisCache_JSONStorage(t *testing.T) {
+	cache := newJSONCache(t, DefaultRedisConfig())
+
+	if values, err := cache.GetPath(`$[?(@.ID=="1")]`); err != nil || len(values) != 0 {
+		t.Errorf("Expected empty read of a missing key, got %+v (%v)", values, err)
+	}
+
+	users := []TestUser{{ID: "1", Name: "Alice"}, {ID: "2", Name: "Bob"}}
+	if err := cache.Set(users); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if ids := idsOf(values); ids != "1,2" || version != 1 {
+		t.Errorf("Expected 1,2 at version 1, got %s at %d", ids, version)
+	}
+
+	match, err := cache.GetPath(`$[?(@.ID=="2")]`)
+	if err != nil {
+		t.Fatalf("GetPath error: %v", err)
+	}
+	if len(match) != 1 || match[0].Name != "Bob" {
+		t.Errorf("Expected Bob, got %+v", match)
+	}
+	if none, err := cache.GetPath(`$[?(@.ID=="9")]`); err != nil || len(none) != 0 {
+		t.Errorf("Expected no match, got %+v (%v)", none, err)
+	}
+	if _, err := cache.GetPath("[0]"); err == nil {
+		t.Error("Expected an error for a path without $")
+	}
+
+	if err := cache.Set(nil); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, _ := cache.Get(); values == nil || len(values) != 0 {
+		t.Errorf("Expected an empty dataset, got %+v", values)
+	}
+}
+
+func TestRedisCache_JSONStorageSetIfVersion(t *testing.T) {
+	cache := newJSONCache(t, DefaultRedisConfig())
+
+	ok, err := cache.SetIfVersion([]TestUser{{ID: "1"}}, 0)
+	if err != nil || !ok {
+		t.Fatalf("Expected first SetIfVersion to succeed, got %v (%v)", ok, err)
+	}
+	if ok, _ := cache.SetIfVersion([]TestUser{{ID: "2"}}, 0); ok {
+		t.Error("Expected SetIfVersion with a stale version to fail")
+	}
+	if values, _ := cache.Get(); idsOf(values) != "1" {
+		t.Errorf("Expected 1, got %s", idsOf(values))
+	}
+}
+
+func TestRedisCache_GetPathRequiresJSONStorage(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if _, err := cache.GetPath("$"); !errors.Is(err, ErrNotJSONStorage) {
+		t.Errorf("Expected ErrNotJSONStorage, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyBuilder generates the Redis key names of a RedisCache, for organizations with a key
+// naming convention (see SegmentedKeys). Set it with RedisConfig.WithKeyBuilder; it
+// replaces KeyPrefix and VersionKeySuffix. Keys kept alongside the dataset (stale copy,
+// soft expiry marker, rebuild lock, persisted indexes) are named by appending a suffix to
+// DataKey. The names are read once, by NewRedisCache.
+type KeyBuilder interface {
+	// DataKey returns the key holding the dataset.
+	DataKey() string
+	// VersionKey returns the key holding the version counter.
+	VersionKey() string
+	// MetadataKey returns the key of the metadata hash (see RedisConfig.PublishMetadata).
+	MetadataKey() string
+	// Prefix returns the namespace all keys of the cache start with, for ClearPrefix.
+	Prefix() string
+}
+
+// SegmentedKeys is a KeyBuilder naming keys from components, in the order
+// app, env, tenant, dataset: e.g. "billing:prod:acme:plans:data", with ":version" and
+// ":meta" for the version and metadata keys. Tenant may be empty for single-tenant
+// services and is then left out; the other components are required. Components must not
+// contain the separator, braces, glob characters or whitespace, so every cache following
+// the convention gets unambiguous keys.
+type SegmentedKeys struct {
+	App     string
+	Env     string
+	Tenant  string
+	Dataset string
+	// Separator joins the components. Default: ":"
+	Separator string
+	// HashTag wraps the components in a Redis Cluster hash tag (e.g. "{billing:prod:plans}:data"),
+	// so all keys of the cache hash to the same slot. Required with RedisConfig.ClusterHashTag.
+	HashTag bool
+}
+
+// separator returns the configured separator, ":" if unset.
+func (k SegmentedKeys) separator() string {
+	if k.Separator != "" {
+		return k.Separator
+	}
+	return ":"
+}
+
+// base returns the joined components, hash-tagged if configured.
+func (k SegmentedKeys) base() string {
+	parts := []string{k.App, k.Env}
+	if k.Tenant != "" {
+		parts = append(parts, k.Tenant)
+	}
+	parts = append(parts, k.Dataset)
+	base := strings.Join(parts, k.separator())
+	if k.HashTag {
+		base = "{" + base + "}"
+	}
+	return base
+}
+
+// DataKey implements KeyBuilder.
+func (k SegmentedKeys) DataKey() string { return k.base() + k.separator() + "data" }
+
+// VersionKey implements KeyBuilder.
+func (k SegmentedKeys) VersionKey() string { return k.base() + k.separator() + "version" }
+
+// MetadataKey implements KeyBuilder.
+func (k SegmentedKeys) MetadataKey() string { return k.base() + k.separator() + "meta" }
+
+// Prefix implements KeyBuilder.
+func (k SegmentedKeys) Prefix() string { return k.base() }
+
+// Validate reports whether the components follow the convention. NewRedisCache panics
+// with this error for an invalid SegmentedKeys.
+func (k SegmentedKeys) Validate() error {
+	sep := k.separator()
+	components := []struct{ name, value string }{
+		{"App", k.App}, {"Env", k.Env}, {"Tenant", k.Tenant}, {"Dataset", k.Dataset},
+	}
+	for _, c := range components {
+		if c.value == "" {
+			if c.name == "Tenant" {
+				continue
+			}
+			return fmt.Errorf("cache-kit: key component %s must not be empty", c.name)
+		}
+		if strings.Contains(c.value, sep) || strings.ContainsAny(c.value, "{}*?[] \t\r\n") {
+			return fmt.Errorf("cache-kit: key component %s %q must not contain %q, braces, glob characters or whitespace", c.name, c.value, sep)
+		}
+	}
+	return nil
+}
+
+// validateKeyBuilder reports whether a KeyBuilder reports itself invalid or, with
+// ClusterHashTag, its keys do not share a hash tag.
+func validateKeyBuilder(builder KeyBuilder, clusterHashTag bool) error {
+	if v, ok := builder.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	if !clusterHashTag {
+		return nil
+	}
+	tag := hashTag(builder.DataKey())
+	if tag == "" || hashTag(builder.VersionKey()) != tag || hashTag(builder.MetadataKey()) != tag {
+		return errors.New("cache-kit: with ClusterHashTag, KeyBuilder keys must share a hash tag")
+	}
+	return nil
+}
+
+// hashTag returns the Redis Cluster hash tag of key: what is between the first "{" and the
+// next "}", or "" if that is empty or missing.
+func hashTag(key string) string {
+	open := strings.IndexByte(key, '{')
+	if open < 0 {
+		return ""
+	}
+	end := strings.IndexByte(key[open+1:], '}')
+	if end <= 0 {
+		return ""
+	}
+	return key[open+1 : open+1+end]
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSegmentedKeys(t *testing.T) {
+	keys := SegmentedKeys{App: "billing", Env: "prod", Tenant: "acme", Dataset: "plans"}
+	if err := keys.Validate(); err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+	if got := keys.DataKey(); got != "billing:prod:acme:plans:data" {
+		t.Errorf("Unexpected data key %q", got)
+	}
+	if got := keys.VersionKey(); got != "billing:prod:acme:plans:version" {
+		t.Errorf("Unexpected version key %q", got)
+	}
+	if got := keys.MetadataKey(); got != "billing:prod:acme:plans:meta" {
+		t.Errorf("Unexpected metadata key %q", got)
+	}
+
+	single := SegmentedKeys{App: "billing", Env: "prod", Dataset: "plans", Separator: ".", HashTag: true}
+	if got := single.DataKey(); got != "{billing.prod.plans}.data" {
+		t.Errorf("Unexpected data key %q", got)
+	}
+
+	invalid := []SegmentedKeys{
+		{Env: "prod", Dataset: "plans"},
+		{App: "billing", Env: "prod"},
+		{App: "bill:ing", Env: "prod", Dataset: "plans"},
+		{App: "billing", Env: "prod", Tenant: "a*", Dataset: "plans"},
+		{App: "billing", Env: "pr od", Dataset: "plans"},
+	}
+	for _, k := range invalid {
+		if err := k.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", k)
+		}
+	}
+}
+
+func TestRedisCache_KeyBuilder(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	keys := SegmentedKeys{App: "billing", Env: "prod", Tenant: "acme", Dataset: "plans"}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithKeyBuilder(keys).WithPublishMetadata(true))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	for _, key := range []string{keys.DataKey(), keys.VersionKey(), keys.MetadataKey()} {
+		if !mr.Exists(key) {
+			t.Errorf("Expected key %q to exist", key)
+		}
+	}
+
+	other := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithKeyBuilder(SegmentedKeys{App: "billing", Env: "prod", Tenant: "other", Dataset: "plans"}))
+	if err := other.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	deleted, err := cache.ClearPrefix(context.Background())
+	if err != nil || deleted != 3 {
+		t.Errorf("Expected ClearPrefix to delete 3 keys of the tenant, got %d (%v)", deleted, err)
+	}
+	if values, _ := other.Get(); idsOf(values) != "2" {
+		t.Errorf("Expected the other tenant to survive, got %s", idsOf(values))
+	}
+}
+
+func TestRedisCache_KeyBuilderValidation(t *testing.T) {
+	expectPanic := func(name string, config *RedisConfig) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected a panic", name)
+			}
+		}()
+		NewRedisCache[TestUser](nil, config)
+	}
+	expectPanic("invalid components", DefaultRedisConfig().
+		WithKeyBuilder(SegmentedKeys{App: "billing", Dataset: "plans"}))
+	expectPanic("missing hash tag", DefaultRedisConfig().WithClusterHashTag(true).
+		WithKeyBuilder(SegmentedKeys{App: "billing", Env: "prod", Dataset: "plans"}))
+
+	cache := NewRedisCache[TestUser](nil, DefaultRedisConfig().WithClusterHashTag(true).
+		WithKeyBuilder(SegmentedKeys{App: "billing", Env: "prod", Dataset: "plans", HashTag: true}))
+	if cache.versionKey() != "{billing:prod:plans}:version" {
+		t.Errorf("Unexpected version key %q", cache.versionKey())
+	}
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// lazyLoad is the state of HybridCache.WithLazyLoad.
+type lazyLoad struct {
+	maxAge   time.Duration
+	loads    singleFlight
+	loadedAt atomic.Int64 // unix nanoseconds of the last successful lazy load, 0 if none
+}
+
+// errFlightPanicked is what callers waiting on a singleFlight call get when it panicked.
+var errFlightPanicked = errors.New("cache-kit: shared call panicked")
+
+// singleFlight runs a function once for all the callers arriving while it runs.
+type singleFlight struct {
+	mu   sync.Mutex
+	call *flightCall // the call in flight, if any
+}
+
+// flightCall is a call shared by singleFlight callers.
+type flightCall struct {
+	done chan struct{}
+	err  error
+}
+
+// do runs fn, or waits for the run in flight, and returns its error.
+func (f *singleFlight) do(fn func() error) error {
+	f.mu.Lock()
+	if call := f.call; call != nil {
+		f.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	f.call = call
+	f.mu.Unlock()
+
+	// A panicking fn must not leave the waiters blocked; they get errFlightPanicked.
+	defer func() {
+		f.mu.Lock()
+		f.call = nil
+		f.mu.Unlock()
+		close(call.done)
+	}()
+	call.err = errFlightPanicked
+	call.err = fn()
+	return call.err
+}
+
+// WithLazyLoad makes a GetByIndex miss load memory with LoadFromRedis and retry the lookup
+// when memory was never populated, or, if maxAge is positive, was last populated more
+// than maxAge ago, so an instance right after startup does not answer false negatives.
+// Concurrent misses share one load. The loaded memory then answers further misses until
+// it ages past maxAge again; a successful load counts even if it left memory untouched
+// (see EmptyRemoteKeep).
+func (c *HybridCache[V]) WithLazyLoad(maxAge time.Duration) *HybridCache[V] {
+	c.lazy = &lazyLoad{maxAge: maxAge}
+	return c
+}
+
+// loadOnMiss loads memory after a lookup miss if WithLazyLoad says so, and reports
+// whether the lookup is worth retrying.
+func (c *HybridCache[V]) loadOnMiss() bool {
+	l := c.lazy
+	if l == nil {
+		return false
+	}
+	last := c.memory.LastRefreshed()
+	if loaded := l.loadedAt.Load(); loaded != 0 && time.Unix(0, loaded).After(last) {
+		last = time.Unix(0, loaded)
+	}
+	if !last.IsZero() && (l.maxAge <= 0 || c.memory.now().Sub(last) <= l.maxAge) {
+		return false
+	}
+	return l.loads.do(func() error {
+		if err := c.LoadFromRedis(); err != nil {
+			return err
+		}
+		l.loadedAt.Store(c.memory.now().UnixNano())
+		return nil
+	}) == nil
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_LazyLoad(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if err := writer.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithLazyLoad(0)
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := cache.GetByIndex("email", "a@example.com"); !ok {
+				t.Error("Expected the lazy load to find the value")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A miss on populated memory does not load again.
+	cache.GetByIndex("email", "missing@example.com")
+	loads := 0
+	for _, op := range hook.ops {
+		if op.op == OpGet {
+			loads++
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected one shared load, got %d", loads)
+	}
+}
+
+func TestHybridCache_LazyLoadMaxAge(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithLazyLoad(time.Millisecond)
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+
+	if err := writer.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.GetByIndex("email", "a@example.com"); !ok {
+		t.Error("Expected a miss on aged memory to reload")
+	}
+}
+
+func TestHybridCache_LazyLoadEmptyRemoteKeep(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithEmptyRemotePolicy(EmptyRemoteKeep).
+		WithLazyLoad(0)
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	for i := 0; i < 3; i++ {
+		cache.GetByIndex("email", "missing@example.com")
+	}
+	loads := len(hook.ops)
+	cache.GetByIndex("email", "missing@example.com")
+	if loads == 0 || len(hook.ops) != loads {
+		t.Errorf("Expected one load for a missing remote dataset, got %d then %d operations", loads, len(hook.ops))
+	}
+}
+
+func TestSingleFlight_Panic(t *testing.T) {
+	var f singleFlight
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to reach the caller")
+			}
+		}()
+		f.do(func() error { panic("boom") })
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- f.do(func() error { return nil }) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected the next call to run, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the next call not to wait for the panicked one")
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// leaderKeySuffix is appended to the data key to name the StartLeaderRefresh lease.
+const leaderKeySuffix = ":leader"
+
+// leaseIntervals is the leader lease TTL in refresh intervals: a leader that stops
+// renewing is replaced after at most that many intervals.
+const leaseIntervals = 3
+
+// leaderKey returns the leader lease key for this cache.
+func (c *RedisCache[V]) leaderKey() string {
+	return c.key + leaderKeySuffix
+}
+
+// StartLeaderRefresh starts a background goroutine that refreshes the dataset every
+// interval with one origin load for the whole fleet: the instance holding the leader
+// lease (a lock like RedisCache.AcquireRebuildLock, renewed every interval) runs
+// RefreshFromSource, which calls the loader set by WithLoader and writes Redis, while
+// the other instances only reload memory from Redis when the Redis version changed,
+// never calling the loader themselves. A follower takes over once the leader stops
+// renewing, within leaseIntervals*interval, or right away when the leader's loop ends.
+// Errors go to the ErrorHandler as TaskLeader. Like StartAutoRefresh, calling it again
+// replaces the running loop, and Stop (or Close) ends it and gives up the lease.
+func (c *HybridCache[V]) StartLeaderRefresh(interval time.Duration) {
+	c.replaceRefreshLoop(startLoop(func(ctx context.Context) { c.runLeaderRefresh(ctx, interval) }))
+}
+
+// IsLeader reports whether this instance held the StartLeaderRefresh lease at its last
+// renewal.
+func (c *HybridCache[V]) IsLeader() bool {
+	return c.leading.Load()
+}
+
+// runLeaderRefresh is the StartLeaderRefresh loop.
+func (c *HybridCache[V]) runLeaderRefresh(ctx context.Context, interval time.Duration) {
+	var lease *RebuildLock
+	defer func() {
+		c.leading.Store(false)
+		if lease != nil {
+			_ = lease.Release(context.Background())
+		}
+	}()
+
+	loaded, ok := int64(0), false
+	for {
+		var err error
+		lease, err = c.renewLease(ctx, lease, leaseIntervals*interval)
+		c.reportError(TaskLeader, err)
+		leading := lease != nil && err == nil
+		c.leading.Store(leading)
+
+		if leading {
+			err = c.RefreshFromSource(ctx)
+			c.reportError(TaskLeader, err)
+			if err == nil {
+				loaded, ok = c.version.Load(), true
+			}
+		} else {
+			// A missing dataset is the leader's to fill, so followers never fall back to
+			// the cold store or the loader.
+			version, err := c.remote.GetVersionCtx(ctx)
+			switch {
+			case err != nil:
+				c.reportError(TaskLeader, err)
+			case version != 0 && (!ok || version != loaded):
+				found, err := c.loadFromRedis(ctx, false)
+				c.reportError(TaskLeader, err)
+				if found && err == nil {
+					loaded, ok = version, true
+				}
+			}
+		}
+		if sleepCtx(ctx, interval) != nil {
+			return
+		}
+	}
+}
+
+// renewLease extends the held lease, or tries to take it when none is held or it was
+// lost. Returns a nil lease while another instance leads. A lease whose extension failed
+// for another reason is returned with the error, to be extended again or released.
+func (c *HybridCache[V]) renewLease(ctx context.Context, lease *RebuildLock, ttl time.Duration) (*RebuildLock, error) {
+	// Stopping the loop must not abandon a lease taken by a command it interrupted.
+	ctx = context.WithoutCancel(ctx)
+	if lease != nil {
+		err := lease.Extend(ctx, ttl)
+		if err == nil {
+			return lease, nil
+		}
+		if !errors.Is(err, ErrLockLost) {
+			return lease, err
+		}
+	}
+	if c.redis == nil {
+		return nil, ErrRedisRequired
+	}
+	lease, err := c.redis.acquireLock(ctx, c.redis.leaderKey(), ttl)
+	if errors.Is(err, ErrLockHeld) {
+		return nil, nil
+	}
+	return lease, err
+}
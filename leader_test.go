@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_StartLeaderRefresh(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	var loads [2]atomic.Int32
+	newInstance := func(i int) *HybridCache[TestUser] {
+		return NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+			WithLoader(func(context.Context) ([]TestUser, error) {
+				loads[i].Add(1)
+				return []TestUser{{ID: "1"}}, nil
+			})
+	}
+	a, b := newInstance(0), newInstance(1)
+	a.StartLeaderRefresh(5 * time.Millisecond)
+	b.StartLeaderRefresh(5 * time.Millisecond)
+	defer a.Stop()
+	defer b.Stop()
+
+	waitFor(t, func() bool { return idsOf(a.GetAll()) == "1" && idsOf(b.GetAll()) == "1" })
+	leader, follower, followerLoads := a, b, &loads[1]
+	if b.IsLeader() {
+		leader, follower, followerLoads = b, a, &loads[0]
+	}
+	if !leader.IsLeader() || follower.IsLeader() {
+		t.Fatalf("Expected exactly one leader, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+	waitFor(t, func() bool { return loads[0].Load()+loads[1].Load() >= 3 })
+	if n := followerLoads.Load(); n != 0 {
+		t.Errorf("Expected the follower never to call the loader, got %d calls", n)
+	}
+
+	// Stopping the leader releases the lease for the follower.
+	leader.Stop()
+	if leader.IsLeader() {
+		t.Error("Expected Stop to give up leadership")
+	}
+	waitFor(t, func() bool { return follower.IsLeader() && followerLoads.Load() > 0 })
+}
+
+func TestHybridCache_StartLeaderRefreshNoLoader(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	errs := &taskErrors{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithErrorHandler(errs.handle)
+
+	cache.StartLeaderRefresh(5 * time.Millisecond)
+	defer cache.Stop()
+	waitFor(t, func() bool { return errs.count(TaskLeader) > 0 })
+}
+
+func TestHybridCache_StartLeaderRefreshFollowerMissingData(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	var loads atomic.Int32
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithLoader(func(context.Context) ([]TestUser, error) {
+			loads.Add(1)
+			return []TestUser{{ID: "2"}}, nil
+		})
+	cache.Memory().Set([]TestUser{{ID: "1"}})
+
+	// Another instance leads, and the data key is gone while the version key remains.
+	lease, err := cache.redis.acquireLock(context.Background(), cache.redis.leaderKey(), time.Minute)
+	if err != nil {
+		t.Fatalf("acquireLock error: %v", err)
+	}
+	defer lease.Release(context.Background())
+	if err := cache.Redis().Set([]TestUser{{ID: "3"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.Del(cache.redis.key)
+
+	// Wait for a few follower reads of the missing dataset.
+	gets := func() int {
+		hook.mu.Lock()
+		defer hook.mu.Unlock()
+		n := 0
+		for _, op := range hook.ops {
+			if op.op == OpGet {
+				n++
+			}
+		}
+		return n
+	}
+	cache.StartLeaderRefresh(5 * time.Millisecond)
+	defer cache.Stop()
+	waitFor(t, func() bool { return gets() >= 3 })
+	cache.Stop()
+
+	if cache.IsLeader() || loads.Load() != 0 {
+		t.Errorf("Expected the follower never to call the loader, got %d calls", loads.Load())
+	}
+	if idsOf(cache.Memory().GetAll()) != "1" {
+		t.Errorf("Expected the follower to keep memory, got %v", cache.Memory().GetAll())
+	}
+}
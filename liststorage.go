@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotListStorage is returned by Append and GetRange on a cache not in list storage mode.
+var ErrNotListStorage = errors.New("cache-kit: append and range reads require list storage (see RedisCache.WithListStorage)")
+
+// setListIfVersionScript is setIfVersionScript for list storage mode: it replaces the list
+// with the given entries. KEYS: data, version. ARGV: expected version, ttl in milliseconds,
+// then the encoded entries. Returns the new version, or -1 on mismatch.
+var setListIfVersionScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current ~= tonumber(ARGV[1]) then
+  return -1
+end
+redis.call('DEL', KEYS[1])
+for i = 3, #ARGV do
+  redis.call('RPUSH', KEYS[1], ARGV[i])
+end
+if #ARGV > 2 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+local v = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[2])
+return v
+`)
+
+// WithListStorage switches the cache to list storage mode: the dataset is stored as a Redis
+// LIST with one entry per value (each encoded on its own with RedisConfig.Codec), in order.
+// Append adds values at the end without rewriting the list, and GetRange reads a slice of
+// it, which suits event-like datasets that grow incrementally. Set still replaces the whole
+// list and Get returns all of it. VersionedEnvelope is not used in this mode. Replaces
+// other storage modes. Must be called before the cache is used; all readers and writers of a
+// key must agree on the mode.
+func (c *RedisCache[V]) WithListStorage() *RedisCache[V] {
+	c.storage = storageList
+	c.itemKey = nil
+	return c
+}
+
+// encodeEntries encodes each value as a list entry. Returns the entries and their total size.
+func (c *RedisCache[V]) encodeEntries(values []V) ([]any, int, error) {
+	codec := c.codec()
+	entries := make([]any, 0, len(values))
+	size := 0
+	for i, v := range values {
+		data, err := codec.Marshal(v)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal value %d: %w", i, err)
+		}
+		entries = append(entries, data)
+		size += len(data)
+	}
+	return entries, size, nil
+}
+
+// writeList replaces the list with values and bumps the version (if bump is set), atomically.
+// Returns the total size of the encoded values.
+func (c *RedisCache[V]) writeList(ctx context.Context, values []V, ttl time.Duration, bump bool) (int, error) {
+	entries, size, err := c.encodeEntries(values)
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, c.key)
+	for i := 0; i < len(entries); i += writeBatchSize {
+		pipe.RPush(ctx, c.key, entries[i:min(i+writeBatchSize, len(entries))]...)
+	}
+	if len(entries) > 0 {
+		pipe.Expire(ctx, c.key, ttl)
+	}
+	if bump {
+		pipe.Incr(ctx, c.versionKey())
+	}
+	pipe.Expire(ctx, c.versionKey(), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return size, fmt.Errorf("failed to set cache: %w", err)
+	}
+	return size, nil
+}
+
+// fetchList reads the whole list and the version in one transaction.
+// The dataset counts as found if either key exists; size is the total size of the entries.
+func (c *RedisCache[V]) fetchList(ctx context.Context) (values []V, version int64, found bool, size int, err error) {
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	pipe := c.client.TxPipeline()
+	entriesCmd := pipe.LRange(ctx, c.key, 0, -1)
+	versionCmd := pipe.Get(ctx, c.versionKey())
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, false, 0, fmt.Errorf("failed to get cache: %w", err)
+	}
+	version, err = versionCmd.Int64()
+	if err != nil && err != redis.Nil {
+		return nil, 0, false, 0, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	entries := entriesCmd.Val()
+	values, size, err = c.decodeEntries(entries)
+	if err != nil {
+		return nil, version, true, size, err
+	}
+	return values, version, len(entries) > 0 || version != 0, size, nil
+}
+
+// decodeEntries checks the total size against MaxValueBytes and decodes list entries.
+func (c *RedisCache[V]) decodeEntries(entries []string) ([]V, int, error) {
+	size := 0
+	for _, data := range entries {
+		size += len(data)
+	}
+	if maxBytes := c.config.MaxValueBytes; maxBytes > 0 && size > maxBytes {
+		return nil, size, &ValueTooLargeError{Size: size, Max: maxBytes}
+	}
+
+	codec := c.codec()
+	values := make([]V, len(entries))
+	for i, data := range entries {
+		if err := codec.Unmarshal([]byte(data), &values[i]); err != nil {
+			return nil, size, decodeError(fmt.Errorf("failed to unmarshal value %d: %w", i, err))
+		}
+	}
+	return values, size, nil
+}
+
+// Append adds values to the end of the list in list storage mode and bumps the version,
+// without rewriting existing entries. The TTL of the data and version keys is reset to
+// RedisConfig.TTL. Appending nothing is a no-op.
+func (c *RedisCache[V]) Append(values []V) error {
+	return c.AppendCtx(context.Background(), values)
+}
+
+// AppendCtx is like Append but uses ctx for the Redis calls.
+func (c *RedisCache[V]) AppendCtx(ctx context.Context, values []V) (err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpAppend, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+	if c.storage != storageList {
+		return ErrNotListStorage
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	entries, size, err := c.encodeEntries(values)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ttl := c.effectiveTTL(c.config.TTL)
+	pipe := c.client.TxPipeline()
+	var length *redis.IntCmd
+	for i := 0; i < len(entries); i += writeBatchSize {
+		length = pipe.RPush(ctx, c.key, entries[i:min(i+writeBatchSize, len(entries))]...)
+	}
+	pipe.Expire(ctx, c.key, ttl)
+	pipe.Incr(ctx, c.versionKey())
+	pipe.Expire(ctx, c.versionKey(), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append: %w", err)
+	}
+	return c.afterItemWrite(ctx, int(length.Val()), ttl)
+}
+
+// GetRange returns the entries from start to stop (inclusive, zero-based) in list storage
+// mode. Negative indexes count from the end, as with LRANGE: GetRange(-10, -1) reads the
+// last ten entries. Out-of-range indexes are clamped; a missing key reads as empty.
+func (c *RedisCache[V]) GetRange(start, stop int64) ([]V, error) {
+	return c.GetRangeCtx(context.Background(), start, stop)
+}
+
+// GetRangeCtx is like GetRange but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetRangeCtx(ctx context.Context, start, stop int64) (_ []V, err error) {
+	began, size := time.Now(), 0
+	defer func() { err = c.observe(OpGetRange, began, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+	if c.storage != storageList {
+		return nil, ErrNotListStorage
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	entries, err := c.client.LRange(ctx, c.key, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get range: %w", err)
+	}
+	values, size, err := c.decodeEntries(entries)
+	return values, err
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newListCache(t *testing.T, config *RedisConfig) *RedisCache[TestUser] {
+	_, client := setupMiniRedis(t)
+	return NewRedisCache[TestUser](client, config).WithListStorage()
+}
+
+func TestRedisCache_ListStorage(t *testing.T) {
+	cache := newListCache(t, DefaultRedisConfig().WithPublishMetadata(true))
+
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.Append([]TestUser{{ID: "3"}, {ID: "4"}}); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+	if err := cache.Append(nil); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+
+	values, version, err := cache.GetWithVersion()
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if ids := idsOf(values); ids != "1,2,3,4" || version != 2 {
+		t.Errorf("Expected 1,2,3,4 at version 2, got %s at %d", ids, version)
+	}
+	if meta, _ := cache.Metadata(); meta.Items != 4 {
+		t.Errorf("Expected metadata with 4 items, got %d", meta.Items)
+	}
+
+	last, err := cache.GetRange(-2, -1)
+	if err != nil || idsOf(last) != "3,4" {
+		t.Errorf("Expected last two entries 3,4, got %s (%v)", idsOf(last), err)
+	}
+	if first, _ := cache.GetRange(0, 0); idsOf(first) != "1" {
+		t.Errorf("Expected first entry 1, got %s", idsOf(first))
+	}
+	if none, err := cache.GetRange(10, 20); err != nil || len(none) != 0 {
+		t.Errorf("Expected empty out-of-range read, got %+v (%v)", none, err)
+	}
+
+	// Set replaces the list.
+	if err := cache.Set([]TestUser{{ID: "5"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, _ := cache.Get(); idsOf(values) != "5" {
+		t.Errorf("Expected list replaced with 5, got %s", idsOf(values))
+	}
+}
+
+func TestRedisCache_ListStorageSetIfVersion(t *testing.T) {
+	cache := newListCache(t, DefaultRedisConfig())
+	if err := cache.Append([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "x"}}, 0); err != nil || ok {
+		t.Errorf("Expected mismatch to be skipped, got %v (%v)", ok, err)
+	}
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "2"}, {ID: "3"}}, 1); err != nil || !ok {
+		t.Fatalf("Expected write at current version, got %v (%v)", ok, err)
+	}
+	if values, _ := cache.Get(); idsOf(values) != "2,3" {
+		t.Errorf("Expected 2,3, got %s", idsOf(values))
+	}
+}
+
+func TestRedisCache_ListStorageTTLAndLimits(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithTTL(time.Minute).
+		WithMaxValueBytes(100)).WithListStorage()
+
+	if err := cache.Append([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+	if ttl, _ := cache.TTL(); ttl != time.Minute {
+		t.Errorf("Expected Append to set TTL 1m, got %v", ttl)
+	}
+	if err := cache.Append([]TestUser{{ID: "2"}, {ID: "3"}, {ID: "4"}}); err != nil {
+		t.Fatalf("Append error: %v", err)
+	}
+	if _, err := cache.Get(); err == nil {
+		t.Error("Expected size error for the whole list")
+	}
+	if values, err := cache.GetRange(0, 0); err != nil || len(values) != 1 {
+		t.Errorf("Expected a small range within the limit, got %+v (%v)", values, err)
+	}
+}
+
+func TestRedisCache_AppendRequiresListStorage(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := cache.Append([]TestUser{{ID: "1"}}); !errors.Is(err, ErrNotListStorage) {
+		t.Errorf("Expected ErrNotListStorage, got %v", err)
+	}
+	if _, err := cache.GetRange(0, -1); !errors.Is(err, ErrNotListStorage) {
+		t.Errorf("Expected ErrNotListStorage, got %v", err)
+	}
+
+	// Switching to hash storage leaves list mode.
+	cache.WithListStorage().WithHashStorage(func(u TestUser) string { return u.ID })
+	if err := cache.Append([]TestUser{{ID: "1"}}); !errors.Is(err, ErrNotListStorage) {
+		t.Errorf("Expected ErrNotListStorage after WithHashStorage, got %v", err)
+	}
+}
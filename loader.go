@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoLoader is returned by HybridCache.RefreshFromSource when no loader is set.
+var ErrNoLoader = errors.New("cache-kit: no loader set (see HybridCache.WithLoader)")
+
+// WithLoader sets the source of truth of the dataset, making HybridCache a read-through
+// unit: RefreshFromSource reloads memory and Redis from it, and LoadFromRedis falls back
+// to it when the Redis key is missing or expired, instead of applying the
+// EmptyRemotePolicy.
+func (c *HybridCache[V]) WithLoader(loader LoaderFunc[V]) *HybridCache[V] {
+	c.loader = loader
+	return c
+}
+
+// RefreshFromSource loads the dataset with the loader set by WithLoader and stores it
+// with Set, following the WritePolicy. A failed load leaves both tiers untouched and
+// returns the loader error; a failed Redis write returns its error with memory already
+// updated, like Set.
+func (c *HybridCache[V]) RefreshFromSource(ctx context.Context) error {
+	if c.loader == nil {
+		return ErrNoLoader
+	}
+	values, err := c.loader(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load from source: %w", err)
+	}
+	return c.Set(values)
+}
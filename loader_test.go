@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHybridCache_RefreshFromSource(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	ctx := context.Background()
+	source := []TestUser{{ID: "1"}, {ID: "2"}}
+	var loadErr error
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithLoader(func(context.Context) ([]TestUser, error) { return source, loadErr })
+
+	if err := cache.RefreshFromSource(ctx); err != nil {
+		t.Fatalf("RefreshFromSource error: %v", err)
+	}
+	if got := idsOf(cache.GetAll()); got != "1,2" {
+		t.Errorf("Expected memory 1,2, got %s", got)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "1,2" {
+		t.Errorf("Expected Redis 1,2, got %s", idsOf(remote))
+	}
+
+	loadErr = errors.New("db down")
+	source = nil
+	if err := cache.RefreshFromSource(ctx); !errors.Is(err, loadErr) {
+		t.Errorf("Expected the loader error, got %v", err)
+	}
+	if got := idsOf(cache.GetAll()); got != "1,2" {
+		t.Errorf("Expected a failed load to leave memory untouched, got %s", got)
+	}
+}
+
+func TestHybridCache_LoadFromRedisFallsBackToLoader(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	loads := 0
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithLoader(func(context.Context) ([]TestUser, error) {
+			loads++
+			return []TestUser{{ID: "1"}}, nil
+		})
+
+	for i := 0; i < 2; i++ {
+		if err := cache.LoadFromRedis(); err != nil {
+			t.Fatalf("LoadFromRedis error: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected one load from the source, then reads from Redis; got %d loads", loads)
+	}
+	if got := idsOf(cache.GetAll()); got != "1" {
+		t.Errorf("Expected 1, got %s", got)
+	}
+}
+
+func TestHybridCache_RefreshFromSourceWithoutLoader(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	if err := cache.RefreshFromSource(context.Background()); !errors.Is(err, ErrNoLoader) {
+		t.Errorf("Expected ErrNoLoader, got %v", err)
+	}
+}
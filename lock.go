@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockKeySuffix is appended to the data key to name the rebuild lock.
+const lockKeySuffix = ":lock"
+
+// ErrLockHeld is returned by AcquireRebuildLock when another holder owns the lock.
+var ErrLockHeld = errors.New("cache-kit: rebuild lock is held by another instance")
+
+// ErrLockLost is returned by RebuildLock.Release and Extend when the lock expired or was
+// taken over by another holder.
+var ErrLockLost = errors.New("cache-kit: rebuild lock is no longer held")
+
+// releaseLockScript deletes the lock if it still holds our token. KEYS: lock. ARGV: token.
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// extendLockScript resets the lock TTL if it still holds our token.
+// KEYS: lock. ARGV: token, ttl in milliseconds.
+var extendLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// RebuildLock is a held rebuild lock, see RedisCache.AcquireRebuildLock.
+type RebuildLock struct {
+	owner lockOwner
+	key   string
+	token string
+}
+
+// lockOwner runs the commands of a RebuildLock like the RedisCache that took it runs its
+// own: rate limited, under OperationTimeout and observed as op.
+type lockOwner interface {
+	runLockOp(ctx context.Context, op string, fn func(ctx context.Context, client redis.UniversalClient) error) error
+}
+
+// runLockOp implements lockOwner.
+func (c *RedisCache[V]) runLockOp(ctx context.Context, op string, fn func(ctx context.Context, client redis.UniversalClient) error) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(op, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	return fn(ctx, c.client)
+}
+
+// lockKey returns the rebuild lock key for this cache.
+func (c *RedisCache[V]) lockKey() string {
+	return c.key + lockKeySuffix
+}
+
+// AcquireRebuildLock tries once to take the fleet-wide lock for regenerating this cache
+// (SET NX PX with a random token), so that on expiry only one instance rebuilds the dataset
+// while others wait (WaitForRebuild) or serve stale data. Returns ErrLockHeld if another
+// holder owns it. The lock expires after ttl unless released or extended first; pick a ttl
+// longer than a rebuild takes.
+func (c *RedisCache[V]) AcquireRebuildLock(ctx context.Context, ttl time.Duration) (_ *RebuildLock, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpAcquireLock, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+	return c.acquireLock(ctx, c.lockKey(), ttl)
+}
+
+// acquireLock takes the lock stored under key for ttl, or returns ErrLockHeld.
+func (c *RedisCache[V]) acquireLock(ctx context.Context, key string, ttl time.Duration) (*RebuildLock, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("lock ttl must be positive, got %v", ttl)
+	}
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	ok, err := c.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire rebuild lock: %w", err)
+	}
+	if !ok {
+		return nil, ErrLockHeld
+	}
+	return &RebuildLock{owner: c, key: key, token: token}, nil
+}
+
+// WaitForRebuild blocks until no rebuild lock is held, checking every interval, or until
+// ctx is done. Callers typically reload the dataset afterwards.
+func (c *RedisCache[V]) WaitForRebuild(ctx context.Context, interval time.Duration) error {
+	if c.client == nil {
+		return ErrNilClient
+	}
+	for {
+		n, err := c.client.Exists(ctx, c.lockKey()).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check rebuild lock: %w", err)
+		}
+		if n == 0 {
+			return nil
+		}
+		if err := sleepCtx(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// newLockToken returns a random token identifying one lock holder.
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Release frees the lock if it is still ours, as an operation of the cache that took it
+// (OpReleaseLock). Returns ErrLockLost if it expired or was
+// taken by another holder meanwhile; that holder's lock is left untouched.
+func (l *RebuildLock) Release(ctx context.Context) error {
+	return l.owner.runLockOp(ctx, OpReleaseLock, func(ctx context.Context, client redis.UniversalClient) error {
+		n, err := releaseLockScript.Run(ctx, client, []string{l.key}, l.token).Int()
+		if err != nil {
+			return fmt.Errorf("failed to release rebuild lock: %w", err)
+		}
+		if n == 0 {
+			return ErrLockLost
+		}
+		return nil
+	})
+}
+
+// Extend resets the lock to expire ttl from now, for rebuilds running longer than planned,
+// as an operation of the cache that took it (OpExtendLock). Returns ErrLockLost if the lock is no longer ours.
+func (l *RebuildLock) Extend(ctx context.Context, ttl time.Duration) error {
+	return l.owner.runLockOp(ctx, OpExtendLock, func(ctx context.Context, client redis.UniversalClient) error {
+		n, err := extendLockScript.Run(ctx, client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+		if err != nil {
+			return fmt.Errorf("failed to extend rebuild lock: %w", err)
+		}
+		if n == 0 {
+			return ErrLockLost
+		}
+		return nil
+	})
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_AcquireRebuildLock(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	a := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	b := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	ctx := context.Background()
+
+	lock, err := a.AcquireRebuildLock(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	if ttl := mr.TTL("cache:data" + lockKeySuffix); ttl != time.Minute {
+		t.Errorf("Expected lock TTL 1m, got %v", ttl)
+	}
+	if _, err := b.AcquireRebuildLock(ctx, time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Errorf("Expected ErrLockHeld for a second holder, got %v", err)
+	}
+
+	if err := lock.Extend(ctx, 2*time.Minute); err != nil {
+		t.Errorf("Extend error: %v", err)
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release error: %v", err)
+	}
+	if err := lock.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Expected ErrLockLost on double release, got %v", err)
+	}
+
+	if _, err := b.AcquireRebuildLock(ctx, time.Minute); err != nil {
+		t.Errorf("Expected lock free after release, got %v", err)
+	}
+}
+
+func TestRebuildLock_ReleaseAfterTakeover(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	ctx := context.Background()
+
+	stale, err := cache.AcquireRebuildLock(ctx, time.Second)
+	if err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	mr.FastForward(2 * time.Second)
+	if _, err := cache.AcquireRebuildLock(ctx, time.Minute); err != nil {
+		t.Fatalf("Expected lock free after expiry, got %v", err)
+	}
+
+	if err := stale.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Expected ErrLockLost for an expired holder, got %v", err)
+	}
+	if err := stale.Extend(ctx, time.Minute); !errors.Is(err, ErrLockLost) {
+		t.Errorf("Expected ErrLockLost extending an expired lock, got %v", err)
+	}
+	if !mr.Exists("cache:data" + lockKeySuffix) {
+		t.Error("Expected the new holder's lock to survive a stale release")
+	}
+}
+
+func TestRebuildLock_RateLimited(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithRateLimiter(NewTokenBucket(0.001, 1)).WithMetricsHook(hook))
+	ctx := context.Background()
+
+	lock, err := cache.AcquireRebuildLock(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	if err := lock.Extend(ctx, time.Minute); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected Extend to share the cache's rate limit, got %v", err)
+	}
+	if last := hook.last(); last.op != OpExtendLock || !errors.Is(last.err, ErrRateLimited) {
+		t.Errorf("Expected a rate limited extend_lock observed, got %+v", last)
+	}
+	if err := lock.Release(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected Release to share the cache's rate limit, got %v", err)
+	}
+	if last := hook.last(); last.op != OpReleaseLock || !errors.Is(last.err, ErrRateLimited) {
+		t.Errorf("Expected a rate limited release_lock observed, got %+v", last)
+	}
+}
+
+func TestRedisCache_WaitForRebuild(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	ctx := context.Background()
+
+	if err := cache.WaitForRebuild(ctx, time.Millisecond); err != nil {
+		t.Errorf("Expected immediate return without a lock, got %v", err)
+	}
+
+	lock, err := cache.AcquireRebuildLock(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = lock.Release(ctx)
+	}()
+	if err := cache.WaitForRebuild(ctx, 5*time.Millisecond); err != nil {
+		t.Errorf("WaitForRebuild error: %v", err)
+	}
+
+	if _, err := cache.AcquireRebuildLock(ctx, time.Minute); err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := cache.WaitForRebuild(short, 5*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded while the lock is held, got %v", err)
+	}
+}
+
+func TestRedisCache_AcquireRebuildLockErrors(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	if _, err := NewRedisCache[TestUser](client, DefaultRedisConfig()).AcquireRebuildLock(context.Background(), 0); err == nil {
+		t.Error("Expected error for zero ttl")
+	}
+	if _, err := NewRedisCache[TestUser](nil, DefaultRedisConfig()).AcquireRebuildLock(context.Background(), time.Second); err == nil {
+		t.Error("Expected error for nil client")
+	}
+}
@@ -0,0 +1,53 @@
+package cache
+
+import "log/slog"
+
+// skipped counts the values a Set or Upsert did not store, for Config.Logger.
+type skipped struct {
+	invalid  int   // rejected by ValidateFunc
+	firstErr error // the first rejection
+	unkeyed  int   // without a primary key, or not encodable by the arena codec
+}
+
+// reject counts a value ValidateFunc rejected with err.
+func (s *skipped) reject(err error) {
+	if s.invalid == 0 {
+		s.firstErr = err
+	}
+	s.invalid++
+}
+
+// logSkipped warns Config.Logger about the values op skipped, if any.
+func (c *MemoryCache[V]) logSkipped(op string, s *skipped) {
+	if c.config.Logger == nil || s.invalid+s.unkeyed == 0 {
+		return
+	}
+	attrs := []any{"op", op, "invalid", s.invalid, "unkeyed", s.unkeyed}
+	if s.firstErr != nil {
+		attrs = append(attrs, "error", s.firstErr)
+	}
+	c.config.Logger.Warn("cache-kit: skipped values", attrs...)
+}
+
+// logEvicted warns Config.Logger about entries evicted under memory pressure, if any.
+func (c *MemoryCache[V]) logEvicted(evicted int) {
+	if c.config.Logger != nil && evicted > 0 {
+		c.config.Logger.Warn("cache-kit: evicted stale entries under memory pressure", "evicted", evicted)
+	}
+}
+
+// logUnkeyed warns RedisConfig.Logger about values hash storage skipped, if any.
+func (c *RedisCache[V]) logUnkeyed(unkeyed int) {
+	if c.config.Logger != nil && unkeyed > 0 {
+		c.config.Logger.Warn("cache-kit: skipped values without a primary key", "key", c.key, "unkeyed", unkeyed)
+	}
+}
+
+// logger returns where the HybridCache logs warnings: RedisConfig.Logger, falling back to
+// the memory Config.Logger.
+func (c *HybridCache[V]) logger() *slog.Logger {
+	if c.redis != nil && c.redis.config.Logger != nil {
+		return c.redis.config.Logger
+	}
+	return c.memory.config.Logger
+}
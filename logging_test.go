@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// logBuffer collects log output, safe for the background goroutines of a HybridCache.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newTestLogger returns a logger writing text records to the returned buffer.
+func newTestLogger() (*slog.Logger, *logBuffer) {
+	buf := &logBuffer{}
+	return slog.New(slog.NewTextHandler(buf, nil)), buf
+}
+
+func TestConfig_LoggerSkippedValues(t *testing.T) {
+	logger, buf := newTestLogger()
+	cache := NewMultiIndexCache(userConfig().
+		WithValidateFunc(func(u TestUser) error {
+			if u.Email == "" {
+				return errors.New("email required")
+			}
+			return nil
+		}).
+		WithLogger(logger))
+
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2"}, {Email: "b@example.com"}})
+	out := buf.String()
+	for _, want := range []string{"level=WARN", "skipped values", "op=Set", "invalid=1", "unkeyed=1", "email required"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in log, got %q", want, out)
+		}
+	}
+
+	cache.Upsert([]TestUser{{ID: "3"}})
+	if !strings.Contains(buf.String(), "op=Upsert") {
+		t.Errorf("Expected Upsert skip logged, got %q", buf.String())
+	}
+
+	before := buf.String()
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	if buf.String() != before {
+		t.Errorf("Expected nothing logged without skips, got %q", buf.String())
+	}
+}
+
+func TestConfig_LoggerEvictions(t *testing.T) {
+	logger, buf := newTestLogger()
+	cache := NewMultiIndexCache(userConfig().
+		WithMemoryPressure(func() bool { return true }, 1).
+		WithLogger(logger))
+
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+	cache.Upsert([]TestUser{{ID: "2"}})
+	if relief := cache.RelievePressure(); relief.Evicted != 1 {
+		t.Fatalf("Expected 1 eviction, got %+v", relief)
+	}
+	if out := buf.String(); !strings.Contains(out, "evicted stale entries") || !strings.Contains(out, "evicted=1") {
+		t.Errorf("Expected eviction logged, got %q", out)
+	}
+}
+
+func TestRedisConfig_LoggerUnkeyedItems(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	logger, buf := newTestLogger()
+	rc := NewRedisCache[TestUser](client, DefaultRedisConfig().WithLogger(logger)).
+		WithHashStorage(func(u TestUser) string { return u.ID })
+
+	if err := rc.Set([]TestUser{{ID: "1"}, {Name: "no id"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "without a primary key") || !strings.Contains(out, "unkeyed=1") {
+		t.Errorf("Expected skipped item logged, got %q", out)
+	}
+}
+
+func TestHybridCache_LoggerBackgroundFailures(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	logger, buf := newTestLogger()
+	config := NewHybridConfig(userConfig(), client).WithLogger(logger)
+	cache, err := NewHybridCacheFromConfig(config)
+	if err != nil {
+		t.Fatalf("NewHybridCacheFromConfig error: %v", err)
+	}
+	cache.WithWriteBehind(WriteBehindConfig{FlushDelay: time.Millisecond, MaxRetries: 1, RetryBackoff: time.Millisecond})
+	defer func() { _ = cache.Close(context.Background()) }()
+
+	var handled int
+	var mu sync.Mutex
+	cache.WithErrorHandler(func(task string, err error) {
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool { return strings.Contains(buf.String(), "task="+TaskWriteBehind) })
+	if !strings.Contains(buf.String(), "background task failed") {
+		t.Errorf("Expected background failure logged, got %q", buf.String())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if handled == 0 {
+		t.Error("Expected the error handler still called")
+	}
+}
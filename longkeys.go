@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// hashedKeyReadableLen is how many bytes of an over-long key base RedisConfig.HashLongKeys
+// keeps readable in front of its hash.
+const hashedKeyReadableLen = 64
+
+// keysTooLong reports whether the data or version key exceeds maxRedisKeyLen.
+func keysTooLong(dataKey, versionKey string) bool {
+	return len(dataKey) > maxRedisKeyLen || len(versionKey) > maxRedisKeyLen
+}
+
+// shortenKey returns the first hashedKeyReadableLen bytes of base, braces removed so no
+// broken hash tag is left, followed by "~" and the hex SHA-256 of the whole base. Distinct
+// bases keep distinct keys while the readable prefix still shows in SCAN and MONITOR.
+func shortenKey(base string) string {
+	readable := strings.NewReplacer("{", "", "}", "").Replace(base)
+	if len(readable) > hashedKeyReadableLen {
+		readable = readable[:hashedKeyReadableLen]
+	}
+	sum := sha256.Sum256([]byte(base))
+	return readable + "~" + hex.EncodeToString(sum[:])
+}
+
+// shortenBuilderKeys applies shortenKey to the common Prefix of the keys of a KeyBuilder,
+// keeping the part of each key after it, and the hash tag if the prefix had one. Keys not
+// starting with the prefix are left as-is.
+func shortenBuilderKeys(builder KeyBuilder) (dataKey, versionKey, metadataKey, prefix string) {
+	prefix = builder.Prefix()
+	if prefix == "" {
+		return builder.DataKey(), builder.VersionKey(), builder.MetadataKey(), prefix
+	}
+	short := shortenKey(prefix)
+	if hashTag(prefix) != "" {
+		short = "{" + short + "}"
+	}
+	rename := func(key string) string {
+		if strings.HasPrefix(key, prefix) {
+			return short + key[len(prefix):]
+		}
+		return key
+	}
+	return rename(builder.DataKey()), rename(builder.VersionKey()), rename(builder.MetadataKey()), short
+}
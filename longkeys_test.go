@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedisCache_HashLongKeys(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	long := "tenant:" + strings.Repeat("x", 600) + ":"
+
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix(long).WithHashLongKeys(true))
+	if len(cache.key) > maxRedisKeyLen || !strings.HasPrefix(cache.key, "tenant:xxx") || !strings.HasSuffix(cache.key, ":data") {
+		t.Errorf("Expected a shortened readable data key, got %q", cache.key)
+	}
+	if !strings.HasPrefix(cache.versionKey(), cache.prefix) || !strings.HasPrefix(cache.key, cache.prefix) {
+		t.Errorf("Expected the keys to share the shortened prefix %q", cache.prefix)
+	}
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, _ := cache.Get(); idsOf(values) != "1" {
+		t.Errorf("Expected 1, got %s", idsOf(values))
+	}
+
+	other := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithKeyPrefix("tenant:"+strings.Repeat("x", 601)+":").WithHashLongKeys(true))
+	if other.key == cache.key {
+		t.Error("Expected distinct long prefixes to keep distinct keys")
+	}
+
+	short := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("app:").WithHashLongKeys(true))
+	if short.key != "app:data" {
+		t.Errorf("Expected keys within the limit to be kept, got %q", short.key)
+	}
+}
+
+func TestRedisCache_HashLongKeysTagged(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().WithHashLongKeys(true).WithClusterHashTag(true)
+
+	cache := NewRedisCacheWithKey[TestUser](client, strings.Repeat("k", 600), config)
+	if len(cache.versionKey()) > maxRedisKeyLen || hashTag(cache.key) == "" || hashTag(cache.versionKey()) != hashTag(cache.key) {
+		t.Errorf("Expected shortened keys sharing a hash tag, got %q and %q", cache.key, cache.versionKey())
+	}
+
+	builder := SegmentedKeys{App: "app", Env: "prod", Tenant: strings.Repeat("t", 600), Dataset: "plans", HashTag: true}
+	built := NewRedisCache[TestUser](client, config.WithKeyBuilder(builder))
+	if !strings.HasSuffix(built.key, "}:data") || hashTag(built.metadataKey()) != hashTag(built.key) || len(built.key) > maxRedisKeyLen {
+		t.Errorf("Expected shortened builder keys sharing a hash tag, got %q and %q", built.key, built.metadataKey())
+	}
+}
+
+func TestRedisCache_LongKeyPanicsByDefault(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for a key over 512 bytes")
+		}
+	}()
+	NewRedisCacheWithKey[TestUser](client, strings.Repeat("k", 600), DefaultRedisConfig())
+}
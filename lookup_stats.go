@@ -0,0 +1,42 @@
+package cache
+
+import "sync/atomic"
+
+// LookupStats counts lookups served by a MemoryCache since it was created.
+type LookupStats struct {
+	// Hits is the number of Get and GetByIndex calls that found a value.
+	Hits uint64
+
+	// Misses is the number of Get and GetByIndex calls that found nothing, including
+	// lookups against an unknown index.
+	Misses uint64
+
+	// HashChanges is the number of modifications that changed the cache hash.
+	HashChanges uint64
+}
+
+// lookupCounters backs LookupStats. Fields are atomic so lookups can record outcomes
+// while holding only the read lock.
+type lookupCounters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	hashChanges atomic.Uint64
+}
+
+// record counts a lookup outcome.
+func (l *lookupCounters) record(found bool) {
+	if found {
+		l.hits.Add(1)
+	} else {
+		l.misses.Add(1)
+	}
+}
+
+// LookupStats returns the lookup counters, e.g. to derive a hit ratio.
+func (c *MemoryCache[V]) LookupStats() LookupStats {
+	return LookupStats{
+		Hits:        c.lookups.hits.Load(),
+		Misses:      c.lookups.misses.Load(),
+		HashChanges: c.lookups.hashChanges.Load(),
+	}
+}
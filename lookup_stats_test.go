@@ -0,0 +1,37 @@
+package cache
+
+import "testing"
+
+func TestMemoryCache_LookupStats(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email }))
+
+	if stats := cache.LookupStats(); stats != (LookupStats{}) {
+		t.Errorf("Expected zero stats for a new cache, got %+v", stats)
+	}
+
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	cache.Get("1")
+	cache.Get("missing")
+	cache.GetByIndex("email", "a@example.com")
+	cache.GetByIndex("email", "missing@example.com")
+	cache.GetByIndex("unknown", "x")
+
+	stats := cache.LookupStats()
+	if stats.Hits != 2 || stats.Misses != 3 {
+		t.Errorf("Expected 2 hits and 3 misses, got %+v", stats)
+	}
+	if stats.HashChanges != 1 {
+		t.Errorf("Expected 1 hash change, got %d", stats.HashChanges)
+	}
+
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	if got := cache.LookupStats().HashChanges; got != 1 {
+		t.Errorf("Expected identical data to leave the hash unchanged, got %d changes", got)
+	}
+	cache.Clear()
+	if got := cache.LookupStats().HashChanges; got != 2 {
+		t.Errorf("Expected Clear to change the hash, got %d changes", got)
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrCacheRegistered is returned by Manager.Register for a name already taken.
+var ErrCacheRegistered = errors.New("cache-kit: a cache is already registered under this name")
+
+// ManagedCache is the part of a cache a Manager drives, whatever its value type; every
+// *HybridCache implements it.
+type ManagedCache interface {
+	Stats() HybridStats
+	Bootstrap(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Manager holds the named caches of a service in one place instead of package globals,
+// and runs their lifecycle together:
+//
+//	caches := cache.NewManager()
+//	_ = caches.Register("users", users)
+//	_ = caches.Register("plans", plans)
+//	err := caches.WarmAll(ctx, 4)
+//	defer caches.Close(context.Background())
+//
+//	users, ok := cache.GetCache[User](caches, "users")
+//
+// It is safe for concurrent use.
+type Manager struct {
+	mu     sync.RWMutex
+	caches map[string]ManagedCache
+	order  []string // registration order
+}
+
+// NewManager creates an empty Manager.
+func NewManager() *Manager {
+	return &Manager{caches: make(map[string]ManagedCache)}
+}
+
+// Register adds c under name, or returns ErrCacheRegistered if name is taken.
+func (m *Manager) Register(name string, c ManagedCache) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.caches[name]; ok {
+		return fmt.Errorf("%w: %q", ErrCacheRegistered, name)
+	}
+	m.caches[name] = c
+	m.order = append(m.order, name)
+	return nil
+}
+
+// Unregister removes the cache under name, without closing it, and reports whether one
+// was registered.
+func (m *Manager) Unregister(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.caches[name]; !ok {
+		return false
+	}
+	delete(m.caches, name)
+	m.order = slices.DeleteFunc(m.order, func(n string) bool { return n == name })
+	return true
+}
+
+// Lookup returns the cache registered under name.
+func (m *Manager) Lookup(name string) (ManagedCache, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c, ok := m.caches[name]
+	return c, ok
+}
+
+// GetCache returns the HybridCache registered under name in m; ok is false if there is
+// none or it holds another value type.
+func GetCache[V any](m *Manager, name string) (c *HybridCache[V], ok bool) {
+	managed, found := m.Lookup(name)
+	if !found {
+		return nil, false
+	}
+	c, ok = managed.(*HybridCache[V])
+	return c, ok
+}
+
+// Names returns the registered names in registration order.
+func (m *Manager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.order)
+}
+
+// Stats returns the HybridStats of every registered cache, by name.
+func (m *Manager) Stats() map[string]HybridStats {
+	m.mu.RLock()
+	caches := make(map[string]ManagedCache, len(m.caches))
+	for name, c := range m.caches {
+		caches[name] = c
+	}
+	m.mu.RUnlock()
+
+	stats := make(map[string]HybridStats, len(caches))
+	for name, c := range caches {
+		stats[name] = c.Stats()
+	}
+	return stats
+}
+
+// WarmAll bootstraps every registered cache (see HybridCache.Bootstrap), at most
+// concurrency at a time, with a WarmGroup. Returns the errors of the failed caches
+// joined, each naming the cache.
+func (m *Manager) WarmAll(ctx context.Context, concurrency int) error {
+	group := NewWarmGroup(concurrency)
+	for _, name := range m.Names() {
+		if c, ok := m.Lookup(name); ok {
+			group.Add(name, c.Bootstrap)
+		}
+	}
+	return group.Run(ctx)
+}
+
+// Close closes every registered cache, in reverse registration order so caches built on
+// others close first, and keeps them registered. Returns the errors of the failed caches
+// joined, each naming the cache.
+func (m *Manager) Close(ctx context.Context) error {
+	names := m.Names()
+	var errs []error
+	for i := len(names) - 1; i >= 0; i-- {
+		c, ok := m.Lookup(names[i])
+		if !ok {
+			continue
+		}
+		if err := c.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close cache %q: %w", names[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// closeRecorder is a ManagedCache appending its name to closed when closed.
+type closeRecorder struct {
+	name   string
+	closed *[]string
+	err    error
+}
+
+func (r closeRecorder) Stats() HybridStats                  { return HybridStats{LoadedVersion: 7} }
+func (r closeRecorder) Bootstrap(ctx context.Context) error { return r.err }
+func (r closeRecorder) Close(ctx context.Context) error {
+	*r.closed = append(*r.closed, r.name)
+	return r.err
+}
+
+func TestManager_Registry(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	users := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+	m := NewManager()
+
+	if err := m.Register("users", users); err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if err := m.Register("users", users); !errors.Is(err, ErrCacheRegistered) {
+		t.Errorf("Expected ErrCacheRegistered, got %v", err)
+	}
+
+	if got, ok := GetCache[TestUser](m, "users"); !ok || got != users {
+		t.Errorf("Expected the registered cache, got %v, %v", got, ok)
+	}
+	if _, ok := GetCache[string](m, "users"); ok {
+		t.Error("Expected no cache for another value type")
+	}
+	if _, ok := GetCache[TestUser](m, "missing"); ok {
+		t.Error("Expected no cache for an unknown name")
+	}
+
+	if !m.Unregister("users") || m.Unregister("users") {
+		t.Error("Expected Unregister to remove the cache once")
+	}
+	if len(m.Names()) != 0 {
+		t.Errorf("Expected no names after Unregister, got %v", m.Names())
+	}
+}
+
+func TestManager_Lifecycle(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	users := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithKeyPrefix("users:"))
+	if err := users.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	users.Memory().Clear()
+
+	var closed []string
+	m := NewManager()
+	_ = m.Register("users", users)
+	_ = m.Register("plans", closeRecorder{name: "plans", closed: &closed})
+	_ = m.Register("flags", closeRecorder{name: "flags", closed: &closed, err: errors.New("boom")})
+
+	err := m.WarmAll(context.Background(), 2)
+	if err == nil || !strings.Contains(err.Error(), `"flags"`) || strings.Contains(err.Error(), `"plans"`) {
+		t.Errorf("Expected only flags to fail warming, got %v", err)
+	}
+	if users.Memory().Len() != 1 {
+		t.Errorf("Expected users warmed from Redis, got %d items", users.Memory().Len())
+	}
+
+	stats := m.Stats()
+	if len(stats) != 3 || stats["plans"].LoadedVersion != 7 || stats["users"].LoadedVersion != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	err = m.Close(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `failed to close cache "flags"`) {
+		t.Errorf("Expected the flags close error, got %v", err)
+	}
+	if strings.Join(closed, ",") != "flags,plans" {
+		t.Errorf("Expected reverse registration order, got %v", closed)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+// MapView is a read-only view of the cache keyed by primary key.
+// It captures the dataset at the time it was created: later Sets publish new storage
+// instead of mutating the one a view references, so a view never changes and is safe
+// for concurrent use without locking.
+type MapView[V any] struct {
+	data  map[string]V
+	arena *valueArena[V]
+	order []string
+}
+
+// Get returns the value stored under pk.
+func (m MapView[V]) Get(pk string) (V, bool) {
+	if m.arena != nil {
+		return m.arena.get(pk)
+	}
+	v, exists := m.data[pk]
+	return v, exists
+}
+
+// Has reports whether pk is present in the view.
+func (m MapView[V]) Has(pk string) bool {
+	if m.arena != nil {
+		_, exists := m.arena.spans[pk]
+		return exists
+	}
+	_, exists := m.data[pk]
+	return exists
+}
+
+// Len returns the number of entries in the view.
+func (m MapView[V]) Len() int {
+	return len(m.order)
+}
+
+// Keys returns the primary keys of the view in insertion order.
+func (m MapView[V]) Keys() []string {
+	keys := make([]string, len(m.order))
+	copy(keys, m.order)
+	return keys
+}
+
+// Range calls fn for each entry in insertion order. If fn returns false, iteration stops.
+func (m MapView[V]) Range(fn func(pk string, value V) bool) {
+	for _, pk := range m.order {
+		if v, exists := m.Get(pk); exists {
+			if !fn(pk, v) {
+				return
+			}
+		}
+	}
+}
+
+// AsMap returns a copy of the cached data keyed by primary key.
+// The caller owns the returned map.
+func (c *MemoryCache[V]) AsMap() map[string]V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]V, len(c.order))
+	for _, pk := range c.order {
+		if v, exists := c.value(pk); exists {
+			result[pk] = v
+		}
+	}
+	return result
+}
+
+// AsMapRef returns a read-only view of the cached data keyed by primary key without copying it.
+// The view reflects the dataset at the time of the call; see MapView.
+func (c *MemoryCache[V]) AsMapRef() MapView[V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return MapView[V]{data: c.data, arena: c.arena, order: c.order}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoryCache_AsMap(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}})
+
+	m := cache.AsMap()
+	if len(m) != 2 || m["1"].Name != "A" || m["2"].Name != "B" {
+		t.Errorf("Unexpected map %+v", m)
+	}
+
+	// The copy is owned by the caller
+	delete(m, "1")
+	if _, ok := cache.Get("1"); !ok {
+		t.Error("Expected modifying the copy not to affect the cache")
+	}
+}
+
+func TestMemoryCache_AsMapRef(t *testing.T) {
+	for _, arena := range []bool{false, true} {
+		config := DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID })
+		if arena {
+			config.WithArenaStorage(nil)
+		}
+		cache := NewMultiIndexCache(config)
+		cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}})
+
+		view := cache.AsMapRef()
+		if view.Len() != 2 {
+			t.Errorf("arena=%v: expected view length 2, got %d", arena, view.Len())
+		}
+		if v, ok := view.Get("2"); !ok || v.Name != "B" {
+			t.Errorf("arena=%v: expected B, got %+v", arena, v)
+		}
+		if !view.Has("1") || view.Has("3") {
+			t.Errorf("arena=%v: unexpected Has results", arena)
+		}
+		if keys := view.Keys(); strings.Join(keys, ",") != "1,2" {
+			t.Errorf("arena=%v: expected keys 1,2, got %v", arena, keys)
+		}
+
+		var visited []string
+		view.Range(func(pk string, v TestUser) bool {
+			visited = append(visited, pk+"="+v.Name)
+			return false
+		})
+		if len(visited) != 1 || visited[0] != "1=A" {
+			t.Errorf("arena=%v: expected Range to stop after 1=A, got %v", arena, visited)
+		}
+
+		// Later mutations do not affect the view
+		cache.Set([]TestUser{{ID: "3", Name: "C"}})
+		if view.Len() != 2 || view.Has("3") {
+			t.Errorf("arena=%v: expected view unchanged after Set", arena)
+		}
+	}
+}
+
+func TestMemoryCache_AsMapRefUnchangedByInvalidate(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{u.Tenant} })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}, {ID: "2", Tenant: "globex"}})
+
+	view := cache.AsMapRef()
+	cache.InvalidateByTag("acme")
+
+	if !view.Has("1") || view.Len() != 2 {
+		t.Error("Expected view to keep entries removed after it was taken")
+	}
+	if cache.AsMapRef().Has("1") {
+		t.Error("Expected new view to reflect the removal")
+	}
+}
@@ -1,8 +1,16 @@
 package cache
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"maps"
+	"math/rand/v2"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 // MemoryCache provides a thread-safe, multi-index memory cache.
@@ -12,13 +20,31 @@ import (
 //
 //nolint:govet // field order optimized for alignment
 type MemoryCache[V any] struct {
-	mu       sync.RWMutex
-	config   *Config[V]
-	data     map[string]V                 // primary key -> value
-	order    []string                     // insertion order (primary keys)
-	indexes  map[string]map[string]string // index name -> index key -> primary key
-	indexFns map[string]KeyFunc[V]        // index name -> key extraction function
-	hash     string                       // cached hash value
+	mu        sync.RWMutex
+	config    *Config[V]
+	data      map[string]V                   // primary key -> value (nil in arena mode)
+	arena     *valueArena[V]                 // encoded values when config.ArenaStorage is set
+	order     []string                       // insertion order (primary keys)
+	indexes   map[string]map[string]string   // index name -> index key -> primary key
+	indexFns  map[string]KeyFunc[V]          // index name -> key extraction function
+	tags      map[string]map[string]struct{} // tag -> primary keys carrying the tag
+	hash      string                         // cached hash value
+	hashBytes [32]byte                       // binary form of hash (see GetHashBytes)
+
+	lastTimings SetTimings                             // phase timings of the last measured Set
+	watchers    map[string]map[*keyWatcher[V]]struct{} // primary key -> WatchKey subscribers
+	usage       map[string]*indexUsage                 // index name -> lookup statistics
+	lookups     lookupCounters                         // Get/GetByIndex outcomes and hash changes (atomic)
+	hashSubs    map[*hashSubscriber]struct{}           // Subscribe callbacks
+	pages       pageCache                              // pagination lookups, rebuilt per revision
+	history     []*Snapshot[V]                         // retained snapshots, oldest first (Config.SnapshotRetention)
+	now         func() time.Time                       // clock, replaceable in tests
+	updatedAt   time.Time                              // time of the last modification of the dataset
+	refreshedAt time.Time                              // time of the last successful Set or Upsert
+	createdAt   time.Time                              // time the cache was created (Age before the first load)
+	revision    uint64                                 // incremented on every modification of the dataset
+	generation  uint64                                 // incremented on every Set or Upsert
+	seen        map[string]uint64                      // primary key -> generation it was last written in
 }
 
 // NewMultiIndexCache creates a new multi-index memory cache.
@@ -27,13 +53,69 @@ func NewMultiIndexCache[V any](config *Config[V]) *MemoryCache[V] {
 	if config == nil {
 		config = DefaultConfig[V]()
 	}
-	return &MemoryCache[V]{
+	size := max(config.ExpectedSize, 0)
+	c := &MemoryCache[V]{
 		config:   config,
-		data:     make(map[string]V),
-		order:    make([]string, 0),
+		order:    make([]string, 0, size),
 		indexes:  make(map[string]map[string]string),
 		indexFns: make(map[string]KeyFunc[V]),
+		tags:     make(map[string]map[string]struct{}),
+		usage:    make(map[string]*indexUsage),
+		seen:     make(map[string]uint64, size),
+		now:      time.Now,
 	}
+	c.createdAt = c.now()
+	c.resetStorage(size)
+	for _, spec := range config.Indexes {
+		c.AddIndex(spec.Name, spec.KeyFunc)
+	}
+	return c
+}
+
+// expectedSize returns the capacity hint for an empty cache (Config.ExpectedSize).
+func (c *MemoryCache[V]) expectedSize() int {
+	return max(c.config.ExpectedSize, 0)
+}
+
+// resetStorage replaces the value storage with an empty one sized for capacity values.
+func (c *MemoryCache[V]) resetStorage(capacity int) {
+	if c.config.ArenaStorage {
+		c.data = nil
+		c.arena = newValueArena[V](c.config.ArenaCodec, capacity)
+		return
+	}
+	c.arena = nil
+	c.data = make(map[string]V, capacity)
+}
+
+// cloneStorage replaces the value storage with a copy, so it can be modified in place
+// without affecting views of the previous storage. Arena chunk bytes are shared: they are
+// append-only and the bytes of existing values never change.
+func (c *MemoryCache[V]) cloneStorage() {
+	if c.arena != nil {
+		c.arena = &valueArena[V]{codec: c.arena.codec, chunks: slices.Clone(c.arena.chunks), spans: maps.Clone(c.arena.spans)}
+		return
+	}
+	c.data = maps.Clone(c.data)
+}
+
+// value returns the value stored under pk, decoding it in arena mode.
+func (c *MemoryCache[V]) value(pk string) (V, bool) {
+	if c.arena != nil {
+		return c.arena.get(pk)
+	}
+	v, exists := c.data[pk]
+	return v, exists
+}
+
+// contains reports whether pk is stored, without decoding it.
+func (c *MemoryCache[V]) contains(pk string) bool {
+	if c.arena != nil {
+		_, exists := c.arena.spans[pk]
+		return exists
+	}
+	_, exists := c.data[pk]
+	return exists
 }
 
 // AddIndex registers a new index with a key extraction function.
@@ -44,10 +126,16 @@ func (c *MemoryCache[V]) AddIndex(name string, keyFunc KeyFunc[V]) {
 	defer c.mu.Unlock()
 
 	c.indexFns[name] = keyFunc
-	c.indexes[name] = make(map[string]string)
+	c.pages.invalidateIndex(name)
+	c.indexes[name] = make(map[string]string, max(len(c.order), c.expectedSize()))
+	c.usage[name] = &indexUsage{created: c.now()}
 
 	// Rebuild index for existing data
-	for pk, v := range c.data {
+	for _, pk := range c.order {
+		v, exists := c.value(pk)
+		if !exists {
+			continue
+		}
 		indexKey := keyFunc(v)
 		if indexKey != "" {
 			c.indexes[name][c.normalizeKey(indexKey)] = pk
@@ -60,8 +148,15 @@ func (c *MemoryCache[V]) RemoveIndex(name string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.removeIndex(name)
+}
+
+// removeIndex deletes an index and its usage statistics. The caller must hold the write lock.
+func (c *MemoryCache[V]) removeIndex(name string) {
+	c.pages.invalidateIndex(name)
 	delete(c.indexFns, name)
 	delete(c.indexes, name)
+	delete(c.usage, name)
 }
 
 // HasIndex checks if an index exists.
@@ -82,16 +177,22 @@ func (c *MemoryCache[V]) GetByIndex(indexName string, key string) (V, bool) {
 	var zero V
 	index, exists := c.indexes[indexName]
 	if !exists {
+		c.lookups.record(false)
 		return zero, false
 	}
+	if c.config.IndexColdAfter > 0 {
+		c.usage[indexName].record(c.now())
+	}
 
 	pk, exists := index[c.normalizeKey(key)]
 	if !exists {
+		c.lookups.record(false)
 		return zero, false
 	}
 
-	value, exists := c.data[pk]
-	return value, exists
+	v, found := c.value(pk)
+	c.lookups.record(found)
+	return v, found
 }
 
 // Get retrieves a value by its primary key.
@@ -99,8 +200,9 @@ func (c *MemoryCache[V]) Get(key string) (V, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	value, exists := c.data[key]
-	return value, exists
+	v, found := c.value(key)
+	c.lookups.record(found)
+	return v, found
 }
 
 // Set stores all values and rebuilds all indexes.
@@ -108,64 +210,217 @@ func (c *MemoryCache[V]) Get(key string) (V, bool) {
 // Duplicate primary keys will be updated (last one wins).
 // Panics if PrimaryKeyFunc is nil and len(values) > 0; set PrimaryKeyFunc via config before use with non-empty data.
 func (c *MemoryCache[V]) Set(values []V) {
+	_ = c.SetCtx(context.Background(), values)
+}
+
+// setCtxCheckInterval is how many values SetCtx processes between context checks.
+const setCtxCheckInterval = 1024
+
+// SetCtx is like Set but checks ctx periodically while normalizing, validating and indexing,
+// so ingesting a huge slice can be aborted (e.g. during shutdown). If ctx is done before
+// the new dataset is complete, the cache is left unchanged and ctx.Err() is returned.
+// Panics if PrimaryKeyFunc is nil and len(values) > 0.
+func (c *MemoryCache[V]) SetCtx(ctx context.Context, values []V) error {
 	if len(values) > 0 && c.config.PrimaryKeyFunc == nil {
 		panic("cache-kit: MultiIndexCache requires PrimaryKeyFunc when setting non-empty data; set it via config.WithPrimaryKey()")
 	}
 
+	var skip skipped
+	timings, err := c.set(ctx, values, &skip)
+	if err != nil {
+		return err
+	}
+	c.logSkipped("Set", &skip)
+	if c.config.SetTimingsFunc != nil {
+		c.config.SetTimingsFunc(timings)
+	}
+	return nil
+}
+
+// set replaces the cache contents under the write lock, aborting (and restoring the
+// previous contents) if ctx is done, and counts the values it skips in skip. Phase timings
+// are only measured when Config.SetTimingsFunc is set.
+func (c *MemoryCache[V]) set(ctx context.Context, values []V, skip *skipped) (SetTimings, error) {
+	clock := phaseClock{enabled: c.config.SetTimingsFunc != nil}
+	timings := SetTimings{Values: len(values)}
+	start := clock.now()
+
+	if err := ctx.Err(); err != nil {
+		return timings, err
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	previous := c.watchedValues()
+	saved := c.saveState()
+
 	// Clear existing data
-	c.data = make(map[string]V, len(values))
+	c.resetStorage(len(values))
 	c.order = make([]string, 0, len(values))
 
-	// Clear all indexes
+	// Clear all indexes and tags
 	for name := range c.indexes {
 		c.indexes[name] = make(map[string]string, len(values))
 	}
+	c.tags = make(map[string]map[string]struct{})
+	c.seen = make(map[string]uint64, len(values))
+	c.generation++
 
 	// Process each value
-	for _, v := range values {
+	for i, v := range values {
+		if i%setCtxCheckInterval == 0 && i > 0 {
+			if err := ctx.Err(); err != nil {
+				c.restoreState(saved)
+				return timings, err
+			}
+		}
+
 		// Normalize if function is set
 		if c.config.NormalizeFunc != nil {
+			t := clock.now()
 			v = c.config.NormalizeFunc(v)
+			clock.add(&timings.Normalize, t)
 		}
 
 		// Validate if function is set
 		if c.config.ValidateFunc != nil {
-			if err := c.config.ValidateFunc(v); err != nil {
+			t := clock.now()
+			err := c.config.ValidateFunc(v)
+			clock.add(&timings.Validate, t)
+			if err != nil {
+				skip.reject(err)
 				continue // Skip invalid values
 			}
 		}
 
-		// Get primary key
-		var pk string
-		if c.config.PrimaryKeyFunc != nil {
-			pk = c.config.PrimaryKeyFunc(v)
-		}
-		if pk == "" {
-			continue // Skip values without primary key
+		t := clock.now()
+		if !c.storeLocked(v) {
+			skip.unkeyed++
 		}
+		clock.add(&timings.Index, t)
+	}
 
-		// Track insertion order
-		if _, exists := c.data[pk]; !exists {
-			c.order = append(c.order, pk)
-		}
+	if err := ctx.Err(); err != nil {
+		c.restoreState(saved)
+		return timings, err
+	}
+	if c.config.AutoDropColdIndexes {
+		c.dropColdIndexes()
+	}
+
+	// Calculate and cache hash
+	c.setHash(c.calculateHashTimed(clock, &timings))
+	timings.Stored = len(c.order)
+	c.updatedAt = c.now()
+	c.refreshedAt = c.updatedAt
+	c.revision++
+	c.retainSnapshot()
+	c.notifyWatchers(previous)
+	clock.add(&timings.Total, start)
+	if clock.enabled {
+		c.lastTimings = timings
+	}
+	return timings, nil
+}
+
+// savedState holds the dataset fields replaced by set, so an aborted Set can restore them.
+type savedState[V any] struct {
+	data       map[string]V
+	arena      *valueArena[V]
+	order      []string
+	indexes    map[string]map[string]string
+	tags       map[string]map[string]struct{}
+	seen       map[string]uint64
+	generation uint64
+}
+
+// saveState captures the dataset fields. The caller must hold the write lock.
+func (c *MemoryCache[V]) saveState() savedState[V] {
+	return savedState[V]{
+		data:       c.data,
+		arena:      c.arena,
+		order:      c.order,
+		indexes:    maps.Clone(c.indexes),
+		tags:       c.tags,
+		seen:       c.seen,
+		generation: c.generation,
+	}
+}
+
+// restoreState reinstates fields captured by saveState. The caller must hold the write lock.
+func (c *MemoryCache[V]) restoreState(s savedState[V]) {
+	c.data = s.data
+	c.arena = s.arena
+	c.order = s.order
+	c.indexes = s.indexes
+	c.tags = s.tags
+	c.seen = s.seen
+	c.generation = s.generation
+}
+
+// storeLocked stores a normalized, validated value and updates indexes and tags, and
+// reports whether it did: values without a primary key (or that the arena codec cannot
+// encode) are skipped. The caller must hold the write lock.
+func (c *MemoryCache[V]) storeLocked(v V) bool {
+	// Get primary key
+	var pk string
+	if c.config.PrimaryKeyFunc != nil {
+		pk = c.config.PrimaryKeyFunc(v)
+	}
+	if pk == "" {
+		return false // Skip values without primary key
+	}
 
-		// Store value
+	// Store value
+	previous, existed := c.value(pk)
+	if c.arena != nil {
+		if err := c.arena.put(pk, v); err != nil {
+			return false
+		}
+	} else {
 		c.data[pk] = v
+	}
 
-		// Update all indexes
-		for name, keyFunc := range c.indexFns {
-			indexKey := keyFunc(v)
-			if indexKey != "" {
-				c.indexes[name][c.normalizeKey(indexKey)] = pk
-			}
+	// A replaced value drops the tags and index keys of the previous one
+	if existed {
+		c.untag(pk, previous)
+		c.unindex(pk, previous)
+	}
+	c.tag(pk, v)
+
+	// Track insertion order and the generation the entry was last seen in
+	if !existed {
+		c.order = append(c.order, pk)
+	}
+	c.seen[pk] = c.generation
+
+	// Update all indexes
+	for name, keyFunc := range c.indexFns {
+		indexKey := keyFunc(v)
+		if indexKey != "" {
+			c.indexes[name][c.normalizeKey(indexKey)] = pk
 		}
 	}
+	return true
+}
 
-	// Calculate and cache hash
-	c.hash = c.calculateHash()
+// unindex removes the index keys of v that still point to pk. The caller must hold the write lock.
+func (c *MemoryCache[V]) unindex(pk string, v V) {
+	for name, keyFunc := range c.indexFns {
+		indexKey := c.normalizeKey(keyFunc(v))
+		if c.indexes[name][indexKey] == pk {
+			delete(c.indexes[name], indexKey)
+		}
+	}
+}
+
+// cloneIndexes replaces every index map with a copy, so indexes can be modified in place
+// without affecting snapshots. The caller must hold the write lock.
+func (c *MemoryCache[V]) cloneIndexes() {
+	for name, index := range c.indexes {
+		c.indexes[name] = maps.Clone(index)
+	}
 }
 
 // GetAll returns all cached values in insertion order.
@@ -175,10 +430,59 @@ func (c *MemoryCache[V]) GetAll() []V {
 
 	result := make([]V, 0, len(c.order))
 	for _, pk := range c.order {
-		if v, exists := c.data[pk]; exists {
+		if v, exists := c.value(pk); exists {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// GetAllInto appends all cached values in insertion order to dst[:0] and returns the result.
+// Passing the slice returned by a previous call reuses its backing array, so hot paths
+// can read the full dataset without a per-call allocation once the buffer is large enough.
+func (c *MemoryCache[V]) GetAllInto(dst []V) []V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dst = dst[:0]
+	for _, pk := range c.order {
+		if v, exists := c.value(pk); exists {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+// Sample returns up to n distinct entries chosen uniformly at random, under a single lock acquisition.
+// It is intended for health probes and spot-check auditing of large datasets.
+// Returns all entries (in random order) when n >= Len, and nil when n <= 0.
+func (c *MemoryCache[V]) Sample(n int) []V {
+	if n <= 0 {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	total := len(c.order)
+	if n > total {
+		n = total
+	}
+
+	// Floyd's algorithm: n distinct positions in O(n) without copying the order slice
+	selected := make(map[int]struct{}, n)
+	result := make([]V, 0, n)
+	for j := total - n; j < total; j++ {
+		pos := rand.IntN(j + 1)
+		if _, taken := selected[pos]; taken {
+			pos = j
+		}
+		selected[pos] = struct{}{}
+		if v, exists := c.value(c.order[pos]); exists {
 			result = append(result, v)
 		}
 	}
+	rand.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
 	return result
 }
 
@@ -187,7 +491,7 @@ func (c *MemoryCache[V]) Len() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	return len(c.data)
+	return len(c.order)
 }
 
 // Clear removes all items from the cache.
@@ -195,12 +499,20 @@ func (c *MemoryCache[V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data = make(map[string]V)
-	c.order = make([]string, 0)
+	previous := c.watchedValues()
+	size := c.expectedSize()
+	c.resetStorage(size)
+	c.order = make([]string, 0, size)
 	for name := range c.indexes {
-		c.indexes[name] = make(map[string]string)
+		c.indexes[name] = make(map[string]string, size)
 	}
-	c.hash = ""
+	c.tags = make(map[string]map[string]struct{})
+	c.seen = make(map[string]uint64, size)
+	c.setHash("")
+	c.updatedAt = c.now()
+	c.revision++
+	c.retainSnapshot()
+	c.notifyWatchers(previous)
 }
 
 // GetHash returns a hash representing the current cache state.
@@ -211,15 +523,60 @@ func (c *MemoryCache[V]) GetHash() string {
 	return c.hash
 }
 
-// Iterate applies a function to each cached value in insertion order.
-// If the function returns false, iteration stops.
-// The callback must not panic; if it does, the read lock may block other goroutines until recovery.
-func (c *MemoryCache[V]) Iterate(fn func(value V) bool) {
+// GetHashBytes returns the binary form of GetHash, for allocation-free comparison in hot loops.
+// For the default hash (and any HashFunc returning 64 hex characters) these are the decoded
+// SHA-256 bytes; other hash strings are reduced with SHA-256. Returns all zeros before the first Set.
+func (c *MemoryCache[V]) GetHashBytes() [32]byte {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	for _, pk := range c.order {
-		if v, exists := c.data[pk]; exists {
+	return c.hashBytes
+}
+
+// GetHashU64 returns the first 8 bytes of GetHashBytes as a big-endian uint64.
+// It is suitable for cheap change detection; use GetHash or GetHashBytes where collisions matter.
+func (c *MemoryCache[V]) GetHashU64() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return binary.BigEndian.Uint64(c.hashBytes[:8])
+}
+
+// setHash stores the hash and its binary form. The caller must hold the write lock.
+func (c *MemoryCache[V]) setHash(hash string) {
+	changed := hash != c.hash
+	c.hash = hash
+	c.hashBytes = hashToBytes(hash)
+	if changed {
+		c.lookups.hashChanges.Add(1)
+		c.notifyHashSubscribers(hash)
+	}
+}
+
+// hashToBytes converts a hash string into 32 bytes: hex-decoded if it is a 64-character
+// hex string, otherwise its SHA-256 digest. The empty string maps to all zeros.
+func hashToBytes(hash string) [32]byte {
+	var out [32]byte
+	if hash == "" {
+		return out
+	}
+	if len(hash) == hex.EncodedLen(len(out)) {
+		if _, err := hex.Decode(out[:], []byte(hash)); err == nil {
+			return out
+		}
+	}
+	return sha256.Sum256([]byte(hash))
+}
+
+// Iterate applies a function to each cached value in insertion order.
+// If the function returns false, iteration stops.
+// Iteration runs over a snapshot taken when Iterate is called: it observes the dataset
+// present at that moment in full, even if a Set runs concurrently, and no lock is held
+// while fn runs, so fn may call other cache methods.
+func (c *MemoryCache[V]) Iterate(fn func(value V) bool) {
+	view := c.AsMapRef()
+	for _, pk := range view.order {
+		if v, exists := view.Get(pk); exists {
 			if !fn(v) {
 				return
 			}
@@ -227,26 +584,44 @@ func (c *MemoryCache[V]) Iterate(fn func(value V) bool) {
 	}
 }
 
+// Range applies fn to each cached primary key and value in insertion order.
+// If fn returns false, iteration stops. It has the same snapshot semantics as Iterate
+// and, like Iterate, does not allocate (except for decoding in arena mode).
+func (c *MemoryCache[V]) Range(fn func(pk string, value V) bool) {
+	c.AsMapRef().Range(fn)
+}
+
 // calculateHash computes the hash of the current cache contents.
 func (c *MemoryCache[V]) calculateHash() string {
-	if len(c.data) == 0 {
+	var untimed SetTimings
+	return c.calculateHashTimed(phaseClock{}, &untimed)
+}
+
+// calculateHashTimed computes the hash, adding sort and hash durations to timings when clock is enabled.
+// timings must not be nil.
+func (c *MemoryCache[V]) calculateHashTimed(clock phaseClock, timings *SetTimings) string {
+	if len(c.order) == 0 {
 		return sha256Hash("empty")
 	}
 
 	// Get values in order
 	values := make([]V, 0, len(c.order))
 	for _, pk := range c.order {
-		if v, exists := c.data[pk]; exists {
+		if v, exists := c.value(pk); exists {
 			values = append(values, v)
 		}
 	}
 
 	// Sort if sort function is provided
 	if c.config.SortFunc != nil {
+		t := clock.now()
 		values = c.config.SortFunc(values)
+		clock.add(&timings.Sort, t)
 	}
 
 	// Use custom hash function if provided
+	t := clock.now()
+	defer clock.add(&timings.Hash, t)
 	if c.config.HashFunc != nil {
 		return c.config.HashFunc(values)
 	}
@@ -256,6 +631,11 @@ func (c *MemoryCache[V]) calculateHash() string {
 
 // normalizeKey normalizes an index key (lowercase, trimmed).
 func (c *MemoryCache[V]) normalizeKey(key string) string {
+	return normalizeIndexKey(key)
+}
+
+// normalizeIndexKey normalizes an index key (lowercase, trimmed).
+func normalizeIndexKey(key string) string {
 	return strings.ToLower(strings.TrimSpace(key))
 }
 
@@ -278,3 +658,14 @@ func (c *MemoryCache[V]) IndexNames() []string {
 	}
 	return names
 }
+
+// ArenaBytes returns the number of bytes held by arena storage, or 0 when arena storage is disabled.
+func (c *MemoryCache[V]) ArenaBytes() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.arena == nil {
+		return 0
+	}
+	return c.arena.size()
+}
@@ -1,6 +1,11 @@
 package cache
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -752,3 +757,271 @@ func BenchmarkMemoryCache_ConcurrentRead(b *testing.B) {
 		}
 	})
 }
+
+func TestMemoryCache_Sample(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+
+	if got := cache.Sample(3); len(got) != 0 {
+		t.Errorf("Expected empty sample from empty cache, got %d", len(got))
+	}
+
+	users := make([]TestUser, 100)
+	for i := range users {
+		users[i] = TestUser{ID: fmt.Sprintf("%d", i)}
+	}
+	cache.Set(users)
+
+	sample := cache.Sample(10)
+	if len(sample) != 10 {
+		t.Fatalf("Expected 10 sampled items, got %d", len(sample))
+	}
+	seen := make(map[string]bool)
+	for _, u := range sample {
+		if seen[u.ID] {
+			t.Errorf("Expected distinct entries, got duplicate %s", u.ID)
+		}
+		seen[u.ID] = true
+		if _, ok := cache.Get(u.ID); !ok {
+			t.Errorf("Sampled entry %s not in cache", u.ID)
+		}
+	}
+
+	if got := cache.Sample(1000); len(got) != 100 {
+		t.Errorf("Expected all 100 items when n exceeds Len, got %d", len(got))
+	}
+	if got := cache.Sample(0); got != nil {
+		t.Errorf("Expected nil for n=0, got %v", got)
+	}
+	if got := cache.Sample(-1); got != nil {
+		t.Errorf("Expected nil for negative n, got %v", got)
+	}
+}
+
+func TestMemoryCache_SampleCoversAllEntries(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 500 && len(seen) < 4; i++ {
+		for _, u := range cache.Sample(1) {
+			seen[u.ID] = true
+		}
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected every entry to be sampled eventually, saw %v", seen)
+	}
+}
+
+func TestMemoryCache_GetAllInto(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+
+	buf := make([]TestUser, 5, 10)
+	buf = cache.GetAllInto(buf)
+	if len(buf) != 2 || buf[0].ID != "1" || buf[1].ID != "2" {
+		t.Errorf("Expected [1 2], got %+v", buf)
+	}
+	if cap(buf) != 10 {
+		t.Errorf("Expected buffer to be reused, cap=%d", cap(buf))
+	}
+
+	if got := cache.GetAllInto(nil); len(got) != 2 {
+		t.Errorf("Expected 2 items into nil buffer, got %d", len(got))
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = cache.GetAllInto(buf)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with a reused buffer, got %v", allocs)
+	}
+}
+
+func TestMemoryCache_Range(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}, {ID: "3", Name: "C"}})
+
+	var visited []string
+	cache.Range(func(pk string, u TestUser) bool {
+		visited = append(visited, pk+"="+u.Name)
+		return pk != "2"
+	})
+	if len(visited) != 2 || visited[0] != "1=A" || visited[1] != "2=B" {
+		t.Errorf("Expected Range to visit 1=A,2=B and stop, got %v", visited)
+	}
+}
+
+func TestMemoryCache_IterateDoesNotAllocate(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+
+	count := 0
+	visit := func(TestUser) bool {
+		count++
+		return true
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		cache.Iterate(visit)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected Iterate not to allocate, got %v", allocs)
+	}
+}
+
+func BenchmarkMemoryCache_GetAllInto(b *testing.B) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+
+	users := make([]TestUser, 1000)
+	for i := 0; i < 1000; i++ {
+		users[i] = TestUser{ID: fmt.Sprintf("%d", i)}
+	}
+	cache.Set(users)
+
+	var buf []TestUser
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = cache.GetAllInto(buf)
+	}
+}
+
+func TestMemoryCache_GetHashBytesAndU64(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+
+	if cache.GetHashBytes() != ([32]byte{}) || cache.GetHashU64() != 0 {
+		t.Error("Expected zero hash forms before the first Set")
+	}
+
+	cache.Set([]TestUser{{ID: "1"}})
+	b := cache.GetHashBytes()
+	if hex.EncodeToString(b[:]) != cache.GetHash() {
+		t.Error("Expected hash bytes to be the decoded hex hash")
+	}
+	if cache.GetHashU64() != binary.BigEndian.Uint64(b[:8]) {
+		t.Error("Expected uint64 hash to be the first 8 bytes big-endian")
+	}
+
+	u64 := cache.GetHashU64()
+	cache.Set([]TestUser{{ID: "2"}})
+	if cache.GetHashU64() == u64 {
+		t.Error("Expected uint64 hash to change with content")
+	}
+
+	cache.Clear()
+	if cache.GetHashBytes() != ([32]byte{}) {
+		t.Error("Expected zero hash bytes after Clear")
+	}
+}
+
+func TestMemoryCache_GetHashBytesCustomHash(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithHashFunc(func(values []TestUser) string { return fmt.Sprintf("v%d", len(values)) })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}})
+
+	if cache.GetHashBytes() != sha256.Sum256([]byte("v1")) {
+		t.Error("Expected non-hex custom hash to be reduced with SHA-256")
+	}
+}
+
+func TestMemoryCache_GetHashU64DoesNotAllocate(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = cache.GetHashU64()
+		_ = cache.GetHashBytes()
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+func TestMemoryCache_SetCtx(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+
+	if err := cache.SetCtx(context.Background(), []TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 item, got %d", cache.Len())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "2"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, ok := cache.Get("1"); !ok || cache.Len() != 1 {
+		t.Error("Expected cache unchanged after canceled SetCtx")
+	}
+}
+
+func TestMemoryCache_SetCtxAbortsMidway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	processed := 0
+	timingsCalls := 0
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithNormalizeFunc(func(u TestUser) TestUser {
+			processed++
+			if processed == 1500 {
+				cancel()
+			}
+			return u
+		}).
+		WithSetTimingsFunc(func(SetTimings) { timingsCalls++ })
+	cache := NewMultiIndexCache(config)
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.Set([]TestUser{{ID: "old", Email: "old@example.com"}})
+	hash := cache.GetHash()
+	generation := cache.Generation()
+	processed = 0
+	timingsCalls = 0
+
+	users := make([]TestUser, 5000)
+	for i := range users {
+		users[i] = TestUser{ID: fmt.Sprintf("%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	err := cache.SetCtx(ctx, users)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if processed >= len(users) {
+		t.Errorf("Expected ingest to stop early, processed %d", processed)
+	}
+	if cache.Len() != 1 || cache.GetHash() != hash || cache.Generation() != generation {
+		t.Error("Expected previous contents restored after abort")
+	}
+	if _, ok := cache.GetByIndex("email", "old@example.com"); !ok {
+		t.Error("Expected previous index restored after abort")
+	}
+	if _, ok := cache.GetByIndex("email", "user1@example.com"); ok {
+		t.Error("Expected partial index entries discarded")
+	}
+	if timingsCalls != 0 {
+		t.Error("Expected no timings callback for an aborted Set")
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// memoryTTL is the state of HybridCache.WithMemoryTTL.
+type memoryTTL struct {
+	ttl         time.Duration
+	validatedAt atomic.Int64 // unix nanoseconds memory was last known to match Redis
+	checks      singleFlight
+}
+
+// WithMemoryTTL bounds how long the memory copy is trusted, independently of
+// RedisConfig.TTL: the first read (GetAll, GetByIndex) after memory has gone ttl without
+// being loaded, written or confirmed against Redis checks the Redis version and, if
+// another instance wrote, reloads with LoadFromRedis before answering. Concurrent reads
+// share the check. If the check fails, the read serves memory, the error reaches the
+// WithErrorHandler handler as TaskMemoryTTL, and the next check is due after another ttl.
+// Unlike WithStaleWhileRevalidate, reads never serve an expired copy while Redis answers.
+func (c *HybridCache[V]) WithMemoryTTL(ttl time.Duration) *HybridCache[V] {
+	c.memTTL = &memoryTTL{ttl: ttl}
+	return c
+}
+
+// validateMemory re-validates memory against Redis if its TTL expired.
+func (c *HybridCache[V]) validateMemory() {
+	m := c.memTTL
+	if m == nil || time.Now().UnixNano()-m.validatedAt.Load() < int64(m.ttl) {
+		return
+	}
+	c.reportError(TaskMemoryTTL, m.checks.do(func() error {
+		if time.Now().UnixNano()-m.validatedAt.Load() < int64(m.ttl) {
+			return nil // validated by the call this one waited for
+		}
+		defer c.markValidated()
+		version, err := c.remote.GetVersionCtx(context.Background())
+		if err != nil || version == c.version.Load() {
+			return err
+		}
+		return c.LoadFromRedis()
+	}))
+}
+
+// markValidated records that memory matches Redis now, under WithMemoryTTL.
+func (c *HybridCache[V]) markValidated() {
+	if c.memTTL != nil {
+		c.memTTL.validatedAt.Store(time.Now().UnixNano())
+	}
+}
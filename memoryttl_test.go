@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHybridCache_MemoryTTL(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithMemoryTTL(20 * time.Millisecond)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := writer.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	// Within the TTL, memory is trusted without asking Redis.
+	before := len(hook.ops)
+	if got := idsOf(cache.GetAll()); got != "1" {
+		t.Errorf("Expected memory within its TTL, got %s", got)
+	}
+	if len(hook.ops) != before {
+		t.Error("Expected no Redis call within the memory TTL")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if got := idsOf(cache.GetAll()); got != "2" {
+		t.Errorf("Expected an expired copy to be revalidated before answering, got %s", got)
+	}
+}
+
+func TestHybridCache_MemoryTTLUnchanged(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithMemoryTTL(time.Millisecond)
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	before := len(hook.ops)
+	cache.GetAll()
+	if got := len(hook.ops) - before; got != 1 || hook.last().op != OpGetVersion {
+		t.Errorf("Expected a single version check when Redis did not change, got %d operations", got)
+	}
+}
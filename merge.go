@@ -0,0 +1,68 @@
+package cache
+
+import "time"
+
+// MergeFunc returns the value HybridCache.LoadFromRedis keeps for a primary key held by
+// both memory and Redis, under WithMergeOnLoad.
+type MergeFunc[V any] func(local, remote V) V
+
+// MergeKeepRemote resolves every conflict in favour of Redis.
+func MergeKeepRemote[V any]() MergeFunc[V] {
+	return func(_, remote V) V { return remote }
+}
+
+// MergeKeepLocal resolves every conflict in favour of memory.
+func MergeKeepLocal[V any]() MergeFunc[V] {
+	return func(local, _ V) V { return local }
+}
+
+// MergeKeepNewer keeps the memory value when updatedAt reports it strictly newer than the
+// Redis value, and the Redis value otherwise.
+func MergeKeepNewer[V any](updatedAt func(V) time.Time) MergeFunc[V] {
+	return func(local, remote V) V {
+		if updatedAt(local).After(updatedAt(remote)) {
+			return local
+		}
+		return remote
+	}
+}
+
+// WithMergeOnLoad makes LoadFromRedis merge the Redis dataset into memory instead of
+// replacing it, for instances whose memory holds writes not synced yet: values only
+// memory holds are kept, values only Redis holds are added, and merge resolves the
+// values both hold. Values keep the Redis order, followed by the memory-only ones. The
+// merged dataset stays local; call SyncToRedis to publish it. A nil merge restores
+// replacing. The EmptyRemotePolicy still applies when the Redis key is missing.
+func (c *HybridCache[V]) WithMergeOnLoad(merge MergeFunc[V]) *HybridCache[V] {
+	c.merge = merge
+	return c
+}
+
+// mergeLocal merges the memory values into remote with c.merge.
+func (c *HybridCache[V]) mergeLocal(remote []V) []V {
+	pkFunc := c.memory.config.PrimaryKeyFunc
+	local := make(map[string]V)
+	var localOrder []string
+	c.memory.Range(func(pk string, v V) bool {
+		local[pk] = v
+		localOrder = append(localOrder, pk)
+		return true
+	})
+
+	merged := make([]V, 0, len(remote)+len(local))
+	seen := make(map[string]struct{}, len(remote))
+	for _, v := range remote {
+		pk := pkFunc(v)
+		if l, ok := local[pk]; ok {
+			v = c.merge(l, v)
+		}
+		seen[pk] = struct{}{}
+		merged = append(merged, v)
+	}
+	for _, pk := range localOrder {
+		if _, ok := seen[pk]; !ok {
+			merged = append(merged, local[pk])
+		}
+	}
+	return merged
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHybridCache_MergeOnLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		merge MergeFunc[TestUser]
+		want  string // name of user 2 after the merge
+	}{
+		{"keep remote", MergeKeepRemote[TestUser](), "remote"},
+		{"keep local", MergeKeepLocal[TestUser](), "local"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := setupMiniRedis(t)
+			cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithMergeOnLoad(tt.merge)
+			if err := cache.Redis().Set([]TestUser{{ID: "1"}, {ID: "2", Name: "remote"}}); err != nil {
+				t.Fatalf("Set error: %v", err)
+			}
+			cache.Memory().Set([]TestUser{{ID: "2", Name: "local"}, {ID: "3"}})
+
+			if err := cache.LoadFromRedis(); err != nil {
+				t.Fatalf("LoadFromRedis error: %v", err)
+			}
+			if got := idsOf(cache.GetAll()); got != "1,2,3" {
+				t.Errorf("Expected the Redis order followed by memory-only values, got %s", got)
+			}
+			if u, _ := cache.Memory().Get("2"); u.Name != tt.want {
+				t.Errorf("Expected %q to win, got %q", tt.want, u.Name)
+			}
+		})
+	}
+}
+
+func TestMergeKeepNewer(t *testing.T) {
+	now := time.Now()
+	updatedAt := func(u TestUser) time.Time {
+		if u.Name == "new" {
+			return now
+		}
+		return now.Add(-time.Minute)
+	}
+	merge := MergeKeepNewer(updatedAt)
+	if got := merge(TestUser{Name: "new"}, TestUser{Name: "old"}); got.Name != "new" {
+		t.Errorf("Expected the newer local value, got %q", got.Name)
+	}
+	if got := merge(TestUser{Name: "old"}, TestUser{Name: "new"}); got.Name != "new" {
+		t.Errorf("Expected the newer remote value, got %q", got.Name)
+	}
+}
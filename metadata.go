@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// metadataKeySuffix is appended to the data key to name the metadata hash.
+const metadataKeySuffix = ":meta"
+
+// Fields of the metadata hash.
+const (
+	metaFieldUpdatedAt = "updated_at" // Unix milliseconds
+	metaFieldItems     = "items"
+)
+
+// CacheMetadata describes the dataset stored in Redis, as published by RedisConfig.PublishMetadata.
+type CacheMetadata struct {
+	// UpdatedAt is when the dataset was last written; zero if no metadata exists.
+	UpdatedAt time.Time
+
+	// Items is the number of values written.
+	Items int
+}
+
+// Age returns how long before now the dataset was written, or 0 if UpdatedAt is zero.
+func (m CacheMetadata) Age(now time.Time) time.Duration {
+	if m.UpdatedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(m.UpdatedAt)
+}
+
+// metadataKey returns the metadata hash key for this cache.
+func (c *RedisCache[V]) metadataKey() string {
+	return c.metaKey
+}
+
+// writeMetadata records the write time and item count with the given TTL.
+func (c *RedisCache[V]) writeMetadata(ctx context.Context, items int, ttl time.Duration) error {
+	pipe := c.client.Pipeline()
+	c.queueMetadata(ctx, pipe, items, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// queueMetadata queues the commands of writeMetadata on pipe.
+func (c *RedisCache[V]) queueMetadata(ctx context.Context, pipe redis.Pipeliner, items int, ttl time.Duration) {
+	pipe.HSet(ctx, c.metadataKey(),
+		metaFieldUpdatedAt, time.Now().UnixMilli(),
+		metaFieldItems, items)
+	pipe.Expire(ctx, c.metadataKey(), ttl)
+}
+
+// Metadata returns the published metadata of the stored dataset.
+// Returns a zero CacheMetadata if none has been published (or it expired).
+func (c *RedisCache[V]) Metadata() (CacheMetadata, error) {
+	return c.MetadataCtx(context.Background())
+}
+
+// MetadataCtx is like Metadata but uses ctx for the Redis calls.
+func (c *RedisCache[V]) MetadataCtx(ctx context.Context) (_ CacheMetadata, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpMetadata, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return CacheMetadata{}, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	fields, err := c.client.HGetAll(ctx, c.metadataKey()).Result()
+	if err != nil {
+		return CacheMetadata{}, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	var meta CacheMetadata
+	if v, ok := fields[metaFieldUpdatedAt]; ok {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return CacheMetadata{}, fmt.Errorf("failed to parse metadata %s: %w", metaFieldUpdatedAt, err)
+		}
+		meta.UpdatedAt = time.UnixMilli(ms)
+	}
+	if v, ok := fields[metaFieldItems]; ok {
+		if meta.Items, err = strconv.Atoi(v); err != nil {
+			return CacheMetadata{}, fmt.Errorf("failed to parse metadata %s: %w", metaFieldItems, err)
+		}
+	}
+	return meta, nil
+}
+
+// LastRefreshed returns when the dataset was last loaded by a successful Set or Upsert;
+// zero if it never was. Removals (Clear, InvalidateByTag, RemoveStale) do not count.
+func (c *MemoryCache[V]) LastRefreshed() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.refreshedAt
+}
+
+// Age returns the time since the last successful Set or Upsert, suitable for alerting on
+// stale datasets. A cache that was never loaded ages from its creation, so a node stuck
+// at startup is reported too.
+func (c *MemoryCache[V]) Age() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.refreshedAt.IsZero() {
+		return c.now().Sub(c.createdAt)
+	}
+	return c.now().Sub(c.refreshedAt)
+}
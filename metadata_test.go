@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedisCache_Metadata(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithPublishMetadata(true))
+
+	meta, err := cache.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata error: %v", err)
+	}
+	if !meta.UpdatedAt.IsZero() || meta.Age(time.Now()) != 0 {
+		t.Errorf("Expected zero metadata before the first write, got %+v", meta)
+	}
+
+	before := time.Now().Add(-time.Second)
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	meta, err = cache.Metadata()
+	if err != nil {
+		t.Fatalf("Metadata error: %v", err)
+	}
+	if meta.Items != 2 {
+		t.Errorf("Expected 2 items, got %d", meta.Items)
+	}
+	if meta.UpdatedAt.Before(before) {
+		t.Errorf("Expected recent UpdatedAt, got %v", meta.UpdatedAt)
+	}
+	if age := meta.Age(meta.UpdatedAt.Add(30 * time.Minute)); age != 30*time.Minute {
+		t.Errorf("Expected age 30m, got %v", age)
+	}
+	if ttl := mr.TTL("cache:data" + metadataKeySuffix); ttl != time.Hour {
+		t.Errorf("Expected metadata TTL 1h, got %v", ttl)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if mr.Exists("cache:data" + metadataKeySuffix) {
+		t.Error("Expected metadata removed by Clear")
+	}
+}
+
+func TestRedisCache_MetadataDisabled(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if mr.Exists("cache:data" + metadataKeySuffix) {
+		t.Error("Expected no metadata key unless PublishMetadata is enabled")
+	}
+}
+
+func TestRedisCache_MetadataWithEnvelope(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithPublishMetadata(true).WithVersionedEnvelope(true))
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if meta, err := cache.Metadata(); err != nil || meta.Items != 1 {
+		t.Errorf("Expected metadata with 1 item, got %+v (err %v)", meta, err)
+	}
+}
+
+func TestMemoryCache_Age(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.now = clock.Now
+	cache.createdAt = clock.Now()
+
+	clock.Advance(time.Minute)
+	if !cache.LastRefreshed().IsZero() {
+		t.Error("Expected zero LastRefreshed before the first Set")
+	}
+	if age := cache.Age(); age != time.Minute {
+		t.Errorf("Expected age since creation 1m, got %v", age)
+	}
+
+	cache.Set([]TestUser{{ID: "1"}})
+	clock.Advance(10 * time.Minute)
+	if age := cache.Age(); age != 10*time.Minute {
+		t.Errorf("Expected age 10m, got %v", age)
+	}
+
+	cache.InvalidateByTag("none")
+	cache.Clear()
+	if age := cache.Age(); age != 10*time.Minute {
+		t.Errorf("Expected removals not to reset age, got %v", age)
+	}
+
+	cache.Upsert([]TestUser{{ID: "2"}})
+	if age := cache.Age(); age != 0 {
+		t.Errorf("Expected Upsert to reset age, got %v", age)
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import "time"
+
+// Operation names reported to MetricsHook.ObserveOp.
+const (
+	OpSet              = "set"                 // Set, SetWithTTL
+	OpGet              = "get"                 // Get, GetWithVersion, HybridCache loads
+	OpGetStale         = "get_stale"           // GetStale
+	OpExists           = "exists"              // Exists
+	OpGetVersion       = "get_version"         // GetVersion, Hash, Subscribe polling
+	OpBumpVersion      = "bump_version"        // BumpVersion
+	OpResetVersion     = "reset_version"       // ResetVersion
+	OpClear            = "clear"               // Clear
+	OpClearPrefix      = "clear_prefix"        // ClearPrefix
+	OpTTL              = "ttl"                 // TTL
+	OpSoftExpired      = "soft_expired"        // IsSoftExpired
+	OpRefresh          = "refresh"             // Refresh, sliding TTL resets
+	OpMetadata         = "metadata"            // Metadata
+	OpGetItem          = "get_item"            // GetItem
+	OpGetItems         = "get_items"           // GetItems
+	OpSetItem          = "set_item"            // SetItem
+	OpDeleteItem       = "delete_item"         // DeleteItem
+	OpPatchItems       = "patch_items"         // HybridCache diff syncs, see WithDiffSync
+	OpSetIfVersion     = "set_if_version"      // SetIfVersion
+	OpAppend           = "append"              // Append
+	OpGetRange         = "get_range"           // GetRange
+	OpGetPath          = "get_path"            // GetPath
+	OpAcquireLock      = "acquire_lock"        // AcquireRebuildLock
+	OpReleaseLock      = "release_lock"        // RebuildLock.Release
+	OpExtendLock       = "extend_lock"         // RebuildLock.Extend
+	OpWriteIndexes     = "write_indexes"       // HybridCache.WithPersistedIndexes writes
+	OpLookupIndex      = "lookup_index"        // LookupIndex
+	OpGetByIndex       = "get_by_index"        // GetByIndex
+	OpPing             = "ping"                // Ping, Healthy
+	OpExport           = "export"              // Export
+	OpImport           = "import"              // Import
+	OpGetIfHashDiffers = "get_if_hash_differs" // GetIfHashDiffers
+	OpRollback         = "rollback"            // Rollback
+	OpValueSize        = "value_size"          // ValueSize
+	OpLoad             = "load"                // HybridCache.LoadFromRedis, reported by Instrument only
+)
+
+// MetricsHook observes RedisCache operations, e.g. to record latency, payload size and
+// error rates. ObserveOp is called once per operation, after it completes, with its name
+// (one of the Op constants), duration, encoded payload size sent or received (0 for
+// operations without a payload) and error. It is called from the goroutine running the
+// operation and must be safe for concurrent use.
+type MetricsHook interface {
+	ObserveOp(op string, dur time.Duration, bytes int, err error)
+}
+
+// MetricsHookFunc adapts a function to MetricsHook.
+type MetricsHookFunc func(op string, dur time.Duration, bytes int, err error)
+
+// ObserveOp calls f.
+func (f MetricsHookFunc) ObserveOp(op string, dur time.Duration, bytes int, err error) {
+	f(op, dur, bytes, err)
+}
+
+// observe reports an operation that began at start to RedisConfig.MetricsHook and the
+// Instrument collector, if set, and returns err marked as ErrTimeout if it is one (see
+// markTimeout), for the operation to return.
+func (c *RedisCache[V]) observe(op string, start time.Time, bytes int, err error) error {
+	err = markTimeout(err)
+	dur := time.Since(start)
+	if hook := c.config.MetricsHook; hook != nil {
+		hook.ObserveOp(op, dur, bytes, err)
+	}
+	if in := c.instrumented.Load(); in != nil {
+		in.collector.ObserveOp(MetricLabels{CacheName: in.name, Tier: TierRedis, Op: op}, dur, bytes, err)
+	}
+	return err
+}
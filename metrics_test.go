@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type observedOp struct {
+	op    string
+	bytes int
+	err   error
+}
+
+// recordingHook collects observed operations.
+type recordingHook struct {
+	mu  sync.Mutex
+	ops []observedOp
+}
+
+func (h *recordingHook) ObserveOp(op string, dur time.Duration, bytes int, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ops = append(h.ops, observedOp{op, bytes, err})
+}
+
+func (h *recordingHook) last() observedOp {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.ops[len(h.ops)-1]
+}
+
+func TestRedisCache_MetricsHook(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithMetricsHook(hook))
+
+	if err := cache.Set([]TestUser{{ID: "1", Name: "Alice"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	set := hook.last()
+	if set.op != OpSet || set.bytes == 0 || set.err != nil {
+		t.Errorf("Expected successful set with payload size, got %+v", set)
+	}
+
+	if _, err := cache.Get(); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if get := hook.last(); get.op != OpGet || get.bytes != set.bytes {
+		t.Errorf("Expected get of %d bytes, got %+v", set.bytes, get)
+	}
+
+	var lock *RebuildLock
+	steps := []struct {
+		op  string
+		run func() error
+	}{
+		{OpExists, func() error { _, err := cache.Exists(); return err }},
+		{OpGetVersion, func() error { _, err := cache.GetVersion(); return err }},
+		{OpTTL, func() error { _, err := cache.TTL(); return err }},
+		{OpRefresh, cache.Refresh},
+		{OpMetadata, func() error { _, err := cache.Metadata(); return err }},
+		{OpSetIfVersion, func() error { _, err := cache.SetIfVersion(nil, 1); return err }},
+		{OpAcquireLock, func() (err error) {
+			lock, err = cache.AcquireRebuildLock(context.Background(), time.Second)
+			return err
+		}},
+		{OpExtendLock, func() error { return lock.Extend(context.Background(), time.Minute) }},
+		{OpReleaseLock, func() error { return lock.Release(context.Background()) }},
+		{OpClear, cache.Clear},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s error: %v", step.op, err)
+		}
+		if got := hook.last(); got.op != step.op || got.err != nil {
+			t.Errorf("Expected successful %s, got %+v", step.op, got)
+		}
+	}
+}
+
+func TestRedisCache_MetricsHookItems(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	var ops []string
+	hook := MetricsHookFunc(func(op string, dur time.Duration, bytes int, err error) {
+		if dur < 0 {
+			t.Errorf("Expected non-negative duration for %s", op)
+		}
+		ops = append(ops, op)
+	})
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithHashStorage(func(u TestUser) string { return u.ID })
+
+	_ = cache.SetItem("1", TestUser{ID: "1"})
+	_, _, _ = cache.GetItem("1")
+	_, _ = cache.GetItems([]string{"1"})
+	_, _ = cache.DeleteItem("1")
+	_, _ = cache.Get()
+
+	want := []string{OpSetItem, OpGetItem, OpGetItems, OpDeleteItem, OpGet}
+	if len(ops) != len(want) {
+		t.Fatalf("Expected ops %v, got %v", want, ops)
+	}
+	for i := range want {
+		if ops[i] != want[i] {
+			t.Errorf("Expected op %d to be %s, got %s", i, want[i], ops[i])
+		}
+	}
+}
+
+func TestRedisCache_MetricsHookError(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithMetricsHook(hook).
+		WithOperationTimeout(100*time.Millisecond))
+	mr.Close()
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err == nil {
+		t.Fatal("Expected Set to fail with Redis down")
+	}
+	if got := hook.last(); got.op != OpSet || got.err == nil {
+		t.Errorf("Expected failed set to be observed, got %+v", got)
+	}
+}
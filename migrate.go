@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// Migration moves a cached dataset from type Old to type New without downtime on a
+// mixed-version fleet. The two shapes live under separate Redis keys (use different
+// KeyPrefix values): upgraded nodes read through the Migration, which falls back to the
+// old key and converts while the new key is still empty; with dual-write enabled, writes
+// also refresh the old key so nodes that have not been upgraded keep working.
+//
+// Once every node runs the new type, stop dual-writing and drop the old key.
+type Migration[Old, New any] struct {
+	old       *RedisCache[Old]
+	new       *RedisCache[New]
+	upgrade   func(Old) (New, error)
+	downgrade func(New) (Old, error)
+}
+
+// NewMigration creates a migration from oldCache to newCache using upgrade to convert values.
+func NewMigration[Old, New any](oldCache *RedisCache[Old], newCache *RedisCache[New], upgrade func(Old) (New, error)) *Migration[Old, New] {
+	return &Migration[Old, New]{old: oldCache, new: newCache, upgrade: upgrade}
+}
+
+// WithDualWrite enables writing the old shape alongside the new one on Set, converting each
+// value with downgrade. Must be called before the migration is used concurrently.
+func (m *Migration[Old, New]) WithDualWrite(downgrade func(New) (Old, error)) *Migration[Old, New] {
+	m.downgrade = downgrade
+	return m
+}
+
+// Get returns the dataset in the new shape. If the new key is missing, the old key is read
+// and converted; a conversion error aborts the read rather than returning partial data.
+func (m *Migration[Old, New]) Get(ctx context.Context) ([]New, error) {
+	values, found, err := m.new.load(ctx)
+	if err != nil || found {
+		return values, err
+	}
+	return m.readOld(ctx)
+}
+
+// Set writes values under the new key and, with dual-write enabled, the converted values
+// under the old key. Values are converted before anything is written, so a conversion error
+// leaves both keys untouched.
+func (m *Migration[Old, New]) Set(ctx context.Context, values []New) error {
+	var old []Old
+	if m.downgrade != nil {
+		var err error
+		if old, err = convertAll(values, m.downgrade); err != nil {
+			return err
+		}
+	}
+	if err := m.new.SetCtx(ctx, values); err != nil {
+		return err
+	}
+	if m.downgrade != nil {
+		if err := m.old.SetCtx(ctx, old); err != nil {
+			return fmt.Errorf("dual-write old shape: %w", err)
+		}
+	}
+	return nil
+}
+
+// Migrate copies the old dataset into the new key, converting every value, and returns
+// the number of values written. It is safe to run repeatedly.
+func (m *Migration[Old, New]) Migrate(ctx context.Context) (int, error) {
+	values, err := m.readOld(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.new.SetCtx(ctx, values); err != nil {
+		return 0, err
+	}
+	return len(values), nil
+}
+
+// LoadInto replaces the contents of memory with the dataset returned by Get.
+func (m *Migration[Old, New]) LoadInto(ctx context.Context, memory *MemoryCache[New]) error {
+	values, err := m.Get(ctx)
+	if err != nil {
+		return err
+	}
+	return memory.SetCtx(ctx, values)
+}
+
+// readOld reads the old key and converts its values to the new shape.
+func (m *Migration[Old, New]) readOld(ctx context.Context) ([]New, error) {
+	old, err := m.old.GetCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return convertAll(old, m.upgrade)
+}
+
+// convertAll converts every value, failing on the first error.
+func convertAll[From, To any](values []From, convert func(From) (To, error)) ([]To, error) {
+	result := make([]To, 0, len(values))
+	for i, v := range values {
+		converted, err := convert(v)
+		if err != nil {
+			return nil, fmt.Errorf("convert value %d: %w", i, err)
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type legacyUser struct {
+	ID   string
+	Name string // "First Last"
+}
+
+type splitUser struct {
+	ID    string
+	First string
+	Last  string
+}
+
+func upgradeUser(u legacyUser) (splitUser, error) {
+	first, last, ok := strings.Cut(u.Name, " ")
+	if !ok {
+		return splitUser{}, errors.New("name without a space")
+	}
+	return splitUser{ID: u.ID, First: first, Last: last}, nil
+}
+
+func downgradeUser(u splitUser) (legacyUser, error) {
+	return legacyUser{ID: u.ID, Name: u.First + " " + u.Last}, nil
+}
+
+func newTestMigration(t *testing.T) (*RedisCache[legacyUser], *RedisCache[splitUser], *Migration[legacyUser, splitUser]) {
+	_, client := setupMiniRedis(t)
+	oldCache := NewRedisCache[legacyUser](client, DefaultRedisConfig().WithKeyPrefix("users:v1:"))
+	newCache := NewRedisCache[splitUser](client, DefaultRedisConfig().WithKeyPrefix("users:v2:"))
+	return oldCache, newCache, NewMigration(oldCache, newCache, upgradeUser)
+}
+
+func TestMigration_GetFallsBackToOldKey(t *testing.T) {
+	oldCache, _, m := newTestMigration(t)
+	ctx := context.Background()
+	if err := oldCache.Set([]legacyUser{{ID: "1", Name: "Ada Lovelace"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	values, err := m.Get(ctx)
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if len(values) != 1 || values[0].First != "Ada" || values[0].Last != "Lovelace" {
+		t.Errorf("Expected converted value, got %+v", values)
+	}
+}
+
+func TestMigration_GetPrefersNewKey(t *testing.T) {
+	oldCache, newCache, m := newTestMigration(t)
+	_ = oldCache.Set([]legacyUser{{ID: "old", Name: "Old User"}})
+	_ = newCache.Set([]splitUser{{ID: "new"}})
+
+	values, err := m.Get(context.Background())
+	if err != nil || len(values) != 1 || values[0].ID != "new" {
+		t.Errorf("Expected new key to win, got %+v (err %v)", values, err)
+	}
+}
+
+func TestMigration_ConversionError(t *testing.T) {
+	oldCache, _, m := newTestMigration(t)
+	_ = oldCache.Set([]legacyUser{{ID: "1", Name: "Ada Lovelace"}, {ID: "2", Name: "Plato"}})
+
+	if _, err := m.Get(context.Background()); err == nil || !strings.Contains(err.Error(), "value 1") {
+		t.Errorf("Expected conversion error for value 1, got %v", err)
+	}
+	if n, err := m.Migrate(context.Background()); err == nil || n != 0 {
+		t.Errorf("Expected Migrate to fail without writing, got n=%d err=%v", n, err)
+	}
+}
+
+func TestMigration_Migrate(t *testing.T) {
+	oldCache, newCache, m := newTestMigration(t)
+	_ = oldCache.Set([]legacyUser{{ID: "1", Name: "Ada Lovelace"}, {ID: "2", Name: "Alan Turing"}})
+
+	n, err := m.Migrate(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 migrated values, got %d (err %v)", n, err)
+	}
+	values, _ := newCache.Get()
+	if len(values) != 2 || values[1].Last != "Turing" {
+		t.Errorf("Expected new key populated, got %+v", values)
+	}
+}
+
+func TestMigration_DualWrite(t *testing.T) {
+	oldCache, newCache, m := newTestMigration(t)
+	m.WithDualWrite(downgradeUser)
+
+	if err := m.Set(context.Background(), []splitUser{{ID: "1", First: "Grace", Last: "Hopper"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, _ := newCache.Get(); len(values) != 1 || values[0].First != "Grace" {
+		t.Errorf("Expected new shape written, got %+v", values)
+	}
+	if values, _ := oldCache.Get(); len(values) != 1 || values[0].Name != "Grace Hopper" {
+		t.Errorf("Expected old shape dual-written, got %+v", values)
+	}
+}
+
+func TestMigration_LoadInto(t *testing.T) {
+	oldCache, _, m := newTestMigration(t)
+	_ = oldCache.Set([]legacyUser{{ID: "1", Name: "Ada Lovelace"}})
+
+	memory := NewMultiIndexCache(DefaultConfig[splitUser]().WithPrimaryKey(func(u splitUser) string { return u.ID }))
+	memory.AddIndex("last", func(u splitUser) string { return u.Last })
+	if err := m.LoadInto(context.Background(), memory); err != nil {
+		t.Fatalf("LoadInto error: %v", err)
+	}
+	if _, ok := memory.GetByIndex("last", "Lovelace"); !ok {
+		t.Error("Expected converted value loaded into memory")
+	}
+}
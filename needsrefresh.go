@@ -0,0 +1,35 @@
+package cache
+
+import "context"
+
+// LoadedVersion returns the Redis version the memory tier holds: the one read by the last
+// LoadFromRedis, written by this instance's last Set, SyncToRedis, Delete or Clear, or
+// passed to MarkLoaded.
+// 0 before the first load or write.
+func (c *HybridCache[V]) LoadedVersion() int64 {
+	return c.version.Load()
+}
+
+// NeedsRefresh reports whether Redis holds a version other than LoadedVersion, i.e.
+// another instance wrote since memory was loaded, at the cost of one GET: request handlers
+// can call it to decide whether to trigger LoadFromRedis.
+func (c *HybridCache[V]) NeedsRefresh(ctx context.Context) (bool, error) {
+	version, err := c.remote.GetVersionCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version != c.version.Load(), nil
+}
+
+// recordVersion stores the Redis version memory holds.
+func (c *HybridCache[V]) recordVersion(version int64) {
+	c.version.Store(version)
+	c.markValidated()
+}
+
+// recordWrittenVersion reads and records the version after this instance wrote Redis.
+func (c *HybridCache[V]) recordWrittenVersion() {
+	if version, err := c.remote.GetVersionCtx(context.Background()); err == nil {
+		c.recordVersion(version)
+	}
+}
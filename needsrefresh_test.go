@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHybridCache_NeedsRefresh(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	ctx := context.Background()
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+
+	check := func(want bool) {
+		t.Helper()
+		got, err := cache.NeedsRefresh(ctx)
+		if err != nil {
+			t.Fatalf("NeedsRefresh error: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected NeedsRefresh %v, got %v (loaded version %d)", want, got, cache.LoadedVersion())
+		}
+	}
+
+	check(false) // nothing written yet
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	check(true)
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	check(false)
+	if cache.LoadedVersion() != 1 {
+		t.Errorf("Expected loaded version 1, got %d", cache.LoadedVersion())
+	}
+
+	// The instance's own writes keep memory current.
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	check(false)
+}
@@ -0,0 +1,25 @@
+package cache
+
+import "time"
+
+// emptyMarker is stored instead of an encoded empty slice when RedisConfig.EmptyTTL is set.
+// It is independent of the codec, so an empty dataset reads back the same with any codec.
+var emptyMarker = []byte("ckempty")
+
+// encode marshals values for a single-payload write, using emptyMarker for an empty
+// dataset under negative caching.
+func (c *RedisCache[V]) encode(values []V) ([]byte, error) {
+	if len(values) == 0 && c.config.EmptyTTL > 0 {
+		return emptyMarker, nil
+	}
+	return c.codec().Marshal(values)
+}
+
+// setTTL returns the TTL for storing values with Set: RedisConfig.EmptyTTL for an empty
+// dataset under negative caching, RedisConfig.TTL otherwise.
+func (c *RedisCache[V]) setTTL(values []V) time.Duration {
+	if len(values) == 0 && c.config.EmptyTTL > 0 {
+		return c.config.EmptyTTL
+	}
+	return c.config.TTL
+}
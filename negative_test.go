@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_EmptyTTL(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithTTL(time.Hour).
+		WithEmptyTTL(time.Minute))
+
+	if _, err := cache.Get(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty for a missing key, got %v", err)
+	}
+	if _, _, err := cache.GetWithVersion(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty from GetWithVersion, got %v", err)
+	}
+
+	if err := cache.Set(nil); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if got, _ := mr.Get("cache:data"); got != string(emptyMarker) {
+		t.Errorf("Expected empty marker to be stored, got %q", got)
+	}
+	if ttl := mr.TTL("cache:data"); ttl != time.Minute {
+		t.Errorf("Expected empty TTL 1m, got %v", ttl)
+	}
+	values, err := cache.Get()
+	if err != nil || values == nil || len(values) != 0 {
+		t.Errorf("Expected cached empty result, got %#v (%v)", values, err)
+	}
+
+	// Non-empty datasets use the regular TTL.
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if ttl := mr.TTL("cache:data"); ttl != time.Hour {
+		t.Errorf("Expected TTL 1h, got %v", ttl)
+	}
+
+	// The empty result expires after EmptyTTL and reads as missing again.
+	if err := cache.Set([]TestUser{}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.FastForward(2 * time.Minute)
+	if _, err := cache.Get(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty after the empty result expired, got %v", err)
+	}
+}
+
+func TestRedisCache_EmptyMarkerCompatibility(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithEmptyTTL(time.Minute).
+		WithVersionedEnvelope(true).
+		WithCodec(GobCodec{}))
+	reader := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithVersionedEnvelope(true).
+		WithCodec(GobCodec{}))
+
+	if ok, err := writer.SetIfVersion(nil, 0); err != nil || !ok {
+		t.Fatalf("SetIfVersion failed: %v (%v)", ok, err)
+	}
+	values, version, err := reader.GetWithVersion()
+	if err != nil || len(values) != 0 || version != 1 {
+		t.Errorf("Expected reader without EmptyTTL to decode the marker, got %+v at %d (%v)", values, version, err)
+	}
+}
+
+func TestHybridCache_LoadCachedEmpty(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	config := DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID })
+	hybrid := NewHybridCache(config, client, DefaultRedisConfig().WithEmptyTTL(time.Minute)).
+		WithEmptyRemotePolicy(EmptyRemoteError)
+
+	if err := hybrid.LoadFromRedis(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty for a missing key, got %v", err)
+	}
+	if err := hybrid.Redis().Set(nil); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := hybrid.LoadFromRedis(); err != nil {
+		t.Errorf("Expected cached empty result to load, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ChangeNotifier reports the current state hash of a cache and notifies subscribers when it
+// changes, so code can react to changes without knowing which tier it was handed.
+// MemoryCache, RedisCache and HybridCache implement it.
+type ChangeNotifier interface {
+	// Hash returns a string identifying the current state.
+	Hash() string
+
+	// Subscribe registers fn to be called with the previous and the new hash after a change.
+	// Calls are made from a separate goroutine, one at a time. The returned function
+	// unsubscribes and is safe to call more than once.
+	Subscribe(fn func(old, new string)) (unsubscribe func())
+}
+
+var (
+	_ ChangeNotifier = (*MemoryCache[any])(nil)
+	_ ChangeNotifier = (*RedisCache[any])(nil)
+	_ ChangeNotifier = (*HybridCache[any])(nil)
+)
+
+// hashSubscriber delivers hash changes to one subscriber from its own goroutine.
+// Changes that arrive faster than fn returns are coalesced: old is the hash the subscriber
+// was last told about, new is the latest one.
+type hashSubscriber struct {
+	fn     func(old, new string)
+	mu     sync.Mutex
+	last   string // hash the subscriber last saw
+	latest string
+	signal chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newHashSubscriber(fn func(old, new string), current string) *hashSubscriber {
+	s := &hashSubscriber{
+		fn:     fn,
+		last:   current,
+		latest: current,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// notify records a new hash and wakes the delivery goroutine without blocking.
+func (s *hashSubscriber) notify(hash string) {
+	s.mu.Lock()
+	s.latest = hash
+	s.mu.Unlock()
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *hashSubscriber) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.signal:
+		}
+		s.mu.Lock()
+		old, latest := s.last, s.latest
+		s.last = latest
+		s.mu.Unlock()
+		if old != latest {
+			s.fn(old, latest)
+		}
+	}
+}
+
+func (s *hashSubscriber) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Hash returns the current content hash; it is the same as GetHash.
+func (c *MemoryCache[V]) Hash() string {
+	return c.GetHash()
+}
+
+// Subscribe implements ChangeNotifier: fn is called whenever a mutation changes the hash.
+func (c *MemoryCache[V]) Subscribe(fn func(old, new string)) func() {
+	c.mu.Lock()
+	s := newHashSubscriber(fn, c.hash)
+	if c.hashSubs == nil {
+		c.hashSubs = make(map[*hashSubscriber]struct{})
+	}
+	c.hashSubs[s] = struct{}{}
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		delete(c.hashSubs, s)
+		c.mu.Unlock()
+		s.stop()
+	}
+}
+
+// notifyHashSubscribers tells subscribers about a new hash. The caller must hold the write lock.
+func (c *MemoryCache[V]) notifyHashSubscribers(hash string) {
+	for s := range c.hashSubs {
+		s.notify(hash)
+	}
+}
+
+// defaultChangePollInterval is used by RedisCache.Subscribe when RedisConfig.ChangePollInterval is not set.
+const defaultChangePollInterval = time.Second
+
+// Hash returns the dataset version as a string, which changes on every Set; "" if the
+// version key is missing or cannot be read.
+func (c *RedisCache[V]) Hash() string {
+	version, err := c.GetVersion()
+	if err != nil || version == 0 {
+		return ""
+	}
+	return strconv.FormatInt(version, 10)
+}
+
+// Subscribe implements ChangeNotifier by polling the version key every
+// RedisConfig.ChangePollInterval (1s if unset). Read errors are skipped.
+func (c *RedisCache[V]) Subscribe(fn func(old, new string)) func() {
+	interval := c.config.ChangePollInterval
+	if interval <= 0 {
+		interval = defaultChangePollInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	last := c.Hash()
+	go func() {
+		_ = RunRefreshLoop(ctx, FixedInterval(interval), func(context.Context) error {
+			version, err := c.GetVersionCtx(ctx)
+			if err != nil {
+				return err
+			}
+			current := ""
+			if version != 0 {
+				current = strconv.FormatInt(version, 10)
+			}
+			if current != last {
+				old := last
+				last = current
+				fn(old, current)
+			}
+			return nil
+		}, nil)
+	}()
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
+
+// Hash returns the hash of the memory tier, which serves all reads.
+func (c *HybridCache[V]) Hash() string {
+	return c.memory.Hash()
+}
+
+// Subscribe implements ChangeNotifier for the memory tier: fn is called when a Set or
+// LoadFromRedis changes the locally served data.
+func (c *HybridCache[V]) Subscribe(fn func(old, new string)) func() {
+	return c.memory.Subscribe(fn)
+}
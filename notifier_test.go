@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+type hashChange struct{ old, new string }
+
+// subscribeChanges subscribes to n and returns a channel receiving every reported change.
+func subscribeChanges(t *testing.T, n ChangeNotifier) <-chan hashChange {
+	t.Helper()
+	changes := make(chan hashChange, 16)
+	unsubscribe := n.Subscribe(func(old, new string) { changes <- hashChange{old, new} })
+	t.Cleanup(unsubscribe)
+	return changes
+}
+
+func receiveChange(t *testing.T, changes <-chan hashChange) hashChange {
+	t.Helper()
+	select {
+	case c := <-changes:
+		return c
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a hash change")
+		return hashChange{}
+	}
+}
+
+func TestMemoryCache_Subscribe(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}})
+	initial := cache.Hash()
+	changes := subscribeChanges(t, cache)
+
+	cache.Set([]TestUser{{ID: "2"}})
+	c := receiveChange(t, changes)
+	if c.old != initial || c.new != cache.Hash() {
+		t.Errorf("Expected %s -> %s, got %+v", initial, cache.Hash(), c)
+	}
+
+	// Same data, same hash: no notification.
+	cache.Set([]TestUser{{ID: "2"}})
+	cache.Clear()
+	c = receiveChange(t, changes)
+	if c.new != "" {
+		t.Errorf("Expected change to empty hash after Clear, got %+v", c)
+	}
+}
+
+func TestMemoryCache_SubscribeUnsubscribe(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	changes := make(chan hashChange, 1)
+	unsubscribe := cache.Subscribe(func(old, new string) { changes <- hashChange{old, new} })
+	unsubscribe()
+	unsubscribe() // idempotent
+
+	cache.Set([]TestUser{{ID: "1"}})
+	select {
+	case c := <-changes:
+		t.Errorf("Expected no notification after unsubscribe, got %+v", c)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestRedisCache_Subscribe(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithChangePollInterval(time.Millisecond))
+	if cache.Hash() != "" {
+		t.Errorf("Expected empty hash before the first Set, got %q", cache.Hash())
+	}
+	changes := subscribeChanges(t, cache)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if c := receiveChange(t, changes); c.old != "" || c.new != "1" {
+		t.Errorf("Expected '' -> '1', got %+v", c)
+	}
+	if cache.Hash() != "1" {
+		t.Errorf("Expected hash '1', got %q", cache.Hash())
+	}
+}
+
+func TestHybridCache_Subscribe(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }), client, nil)
+	changes := subscribeChanges(t, cache)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if c := receiveChange(t, changes); c.new != cache.Hash() || c.new == "" {
+		t.Errorf("Expected change to the memory hash, got %+v", c)
+	}
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// ErrInvalidCursor is returned by Page and PageByIndex for a cursor that was not produced by
+// the same kind of pagination (or is malformed).
+var ErrInvalidCursor = errors.New("cache-kit: invalid pagination cursor")
+
+// ErrIndexNotFound is returned by PageByIndex for an index that is not registered.
+var ErrIndexNotFound = errors.New("cache-kit: index not found")
+
+// errInvalidLimit is returned for a non-positive page limit.
+var errInvalidLimit = errors.New("cache-kit: page limit must be positive")
+
+// Page is one page of a paginated listing.
+type Page[V any] struct {
+	// Items are the values of this page.
+	Items []V
+
+	// NextCursor resumes after the last item; empty when there are no more items.
+	NextCursor string
+
+	// Resynced reports that the dataset changed since the cursor was issued and the entry it
+	// pointed at was removed, so the page resumed at the cursor's former position instead.
+	// Items may then have been skipped or repeated.
+	Resynced bool
+}
+
+// pageCursor is the decoded form of a cursor token.
+type pageCursor struct {
+	Index    string `json:"i,omitempty"` // index name; empty for insertion order
+	Key      string `json:"k"`           // last primary key (insertion order) or index key returned
+	Pos      int    `json:"p,omitempty"` // position after Key (insertion order only)
+	Revision uint64 `json:"r,omitempty"` // cache revision the cursor was issued at
+}
+
+func encodeCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token, index string) (pageCursor, error) {
+	var c pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.Index != index || c.Pos < 0 {
+		return pageCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// pageCache holds lookup structures derived from the dataset for pagination,
+// rebuilt lazily once per revision.
+type pageCache struct {
+	mu        sync.Mutex
+	revision  uint64
+	positions map[string]int      // primary key -> position in insertion order
+	sorted    map[string][]string // index name -> sorted index keys
+}
+
+// reset drops derived structures if they belong to another revision. The caller holds p.mu.
+func (p *pageCache) reset(revision uint64) {
+	if p.revision != revision {
+		p.revision = revision
+		p.positions = nil
+		p.sorted = nil
+	}
+}
+
+// invalidateIndex drops the sorted keys of an index that was added, replaced or removed.
+func (p *pageCache) invalidateIndex(name string) {
+	p.mu.Lock()
+	delete(p.sorted, name)
+	p.mu.Unlock()
+}
+
+// Page returns up to limit values in insertion order, starting after cursor ("" for the first
+// page). Cursors are opaque and survive refreshes: if the dataset changed, the page resumes
+// after the entry the cursor points at, or, when that entry is gone, at the same position
+// (with Page.Resynced set).
+func (c *MemoryCache[V]) Page(cursor string, limit int) (Page[V], error) {
+	if limit <= 0 {
+		return Page[V]{}, errInvalidLimit
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var page Page[V]
+	start := 0
+	if cursor != "" {
+		cur, err := decodeCursor(cursor, "")
+		if err != nil {
+			return Page[V]{}, err
+		}
+		start, page.Resynced = c.resumePosition(cur)
+	}
+
+	end := min(start+limit, len(c.order))
+	page.Items = make([]V, 0, end-start)
+	for _, pk := range c.order[start:end] {
+		if v, exists := c.value(pk); exists {
+			page.Items = append(page.Items, v)
+		}
+	}
+	if end < len(c.order) {
+		page.NextCursor = encodeCursor(pageCursor{Key: c.order[end-1], Pos: end, Revision: c.revision})
+	}
+	return page, nil
+}
+
+// resumePosition returns where a page following cur starts and whether it had to fall back
+// to the cursor's position. The caller must hold the read lock.
+func (c *MemoryCache[V]) resumePosition(cur pageCursor) (int, bool) {
+	if cur.Revision == c.revision && cur.Pos <= len(c.order) {
+		return cur.Pos, false
+	}
+
+	c.pages.mu.Lock()
+	c.pages.reset(c.revision)
+	if c.pages.positions == nil {
+		c.pages.positions = make(map[string]int, len(c.order))
+		for i, pk := range c.order {
+			c.pages.positions[pk] = i
+		}
+	}
+	i, found := c.pages.positions[cur.Key]
+	c.pages.mu.Unlock()
+
+	if found {
+		return i + 1, false
+	}
+	return min(cur.Pos, len(c.order)), true
+}
+
+// PageByIndex returns up to limit values ordered by their key in the named index, starting
+// after cursor ("" for the first page). Pagination is keyset-based, so pages stay consistent
+// across refreshes: the next page starts at the first index key greater than the last one
+// returned. Returns ErrIndexNotFound if the index doesn't exist.
+func (c *MemoryCache[V]) PageByIndex(indexName, cursor string, limit int) (Page[V], error) {
+	if limit <= 0 {
+		return Page[V]{}, errInvalidLimit
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	index, exists := c.indexes[indexName]
+	if !exists {
+		return Page[V]{}, ErrIndexNotFound
+	}
+
+	c.pages.mu.Lock()
+	c.pages.reset(c.revision)
+	keys, cached := c.pages.sorted[indexName]
+	if !cached {
+		keys = make([]string, 0, len(index))
+		for k := range index {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		if c.pages.sorted == nil {
+			c.pages.sorted = make(map[string][]string)
+		}
+		c.pages.sorted[indexName] = keys
+	}
+	c.pages.mu.Unlock()
+
+	start := 0
+	if cursor != "" {
+		cur, err := decodeCursor(cursor, indexName)
+		if err != nil {
+			return Page[V]{}, err
+		}
+		start = sort.SearchStrings(keys, cur.Key)
+		if start < len(keys) && keys[start] == cur.Key {
+			start++
+		}
+	}
+
+	var page Page[V]
+	end := min(start+limit, len(keys))
+	page.Items = make([]V, 0, end-start)
+	for _, key := range keys[start:end] {
+		if v, exists := c.value(index[key]); exists {
+			page.Items = append(page.Items, v)
+		}
+	}
+	if end < len(keys) {
+		page.NextCursor = encodeCursor(pageCursor{Index: indexName, Key: keys[end-1]})
+	}
+	return page, nil
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pagingUsers returns n users whose insertion order is the reverse of their email order.
+func pagingUsers(n int) []TestUser {
+	users := make([]TestUser, n)
+	for i := range users {
+		users[i] = TestUser{ID: fmt.Sprintf("u%d", i), Email: fmt.Sprintf("%02d@example.com", n-i)}
+	}
+	return users
+}
+
+func TestMemoryCache_Page(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithIndex("email", func(u TestUser) string { return u.Email }))
+	cache.Set(pagingUsers(5))
+
+	var ids []string
+	cursor := ""
+	pages := 0
+	for {
+		page, err := cache.Page(cursor, 2)
+		if err != nil {
+			t.Fatalf("Page error: %v", err)
+		}
+		pages++
+		ids = append(ids, idsOf(page.Items))
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+	if strings.Join(ids, ",") != "u0,u1,u2,u3,u4" {
+		t.Errorf("Expected insertion order, got %v", ids)
+	}
+}
+
+func TestMemoryCache_PageAcrossRefresh(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithIndex("email", func(u TestUser) string { return u.Email }))
+	cache.Set(pagingUsers(5))
+	first, _ := cache.Page("", 2) // u0 u1
+
+	// A refresh that inserts an entry before the anchor: the next page still follows u1.
+	cache.Set([]TestUser{{ID: "new"}, {ID: "u0"}, {ID: "u1"}, {ID: "u2"}, {ID: "u3"}, {ID: "u4"}})
+	page, err := cache.Page(first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("Page error: %v", err)
+	}
+	if page.Resynced || idsOf(page.Items) != "u2,u3" {
+		t.Errorf("Expected [u2 u3] after refresh, got %v (resynced %v)", idsOf(page.Items), page.Resynced)
+	}
+
+	// The anchor disappears: fall back to the cursor position.
+	cache.Set([]TestUser{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}})
+	page, err = cache.Page(first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("Page error: %v", err)
+	}
+	if !page.Resynced || idsOf(page.Items) != "c,d" {
+		t.Errorf("Expected resynced [c d], got %v (resynced %v)", idsOf(page.Items), page.Resynced)
+	}
+
+	// A cursor beyond the shrunken dataset yields an empty last page.
+	cache.Set([]TestUser{{ID: "a"}})
+	page, err = cache.Page(first.NextCursor, 2)
+	if err != nil || len(page.Items) != 0 || page.NextCursor != "" {
+		t.Errorf("Expected empty final page, got %+v (err %v)", page, err)
+	}
+}
+
+func TestMemoryCache_PageByIndex(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithIndex("email", func(u TestUser) string { return u.Email }))
+	cache.Set(pagingUsers(5))
+
+	page, err := cache.PageByIndex("email", "", 3)
+	if err != nil {
+		t.Fatalf("PageByIndex error: %v", err)
+	}
+	if idsOf(page.Items) != "u4,u3,u2" {
+		t.Errorf("Expected email order, got %v", idsOf(page.Items))
+	}
+
+	// Remove the anchor entry; keyset pagination continues with the next greater key.
+	cache.Set([]TestUser{{ID: "u0", Email: "05@example.com"}, {ID: "u1", Email: "04@example.com"}})
+	page, err = cache.PageByIndex("email", page.NextCursor, 3)
+	if err != nil {
+		t.Fatalf("PageByIndex error: %v", err)
+	}
+	if idsOf(page.Items) != "u1,u0" || page.NextCursor != "" {
+		t.Errorf("Expected final page [u1 u0], got %v (next %q)", idsOf(page.Items), page.NextCursor)
+	}
+
+	// Re-registering the index refreshes the sorted keys.
+	cache.AddIndex("email", func(u TestUser) string { return u.ID })
+	page, _ = cache.PageByIndex("email", "", 1)
+	if idsOf(page.Items) != "u0" {
+		t.Errorf("Expected replaced index order, got %v", idsOf(page.Items))
+	}
+}
+
+func TestMemoryCache_PageErrors(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig().WithIndex("email", func(u TestUser) string { return u.Email }))
+	cache.Set(pagingUsers(3))
+
+	if _, err := cache.Page("", 0); err == nil {
+		t.Error("Expected error for non-positive limit")
+	}
+	if _, err := cache.Page("not-a-cursor!", 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+	if _, err := cache.PageByIndex("missing", "", 1); !errors.Is(err, ErrIndexNotFound) {
+		t.Errorf("Expected ErrIndexNotFound, got %v", err)
+	}
+
+	ordered, _ := cache.Page("", 1)
+	if _, err := cache.PageByIndex("email", ordered.NextCursor, 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected insertion-order cursor rejected by PageByIndex, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+)
+
+// Partition splits the cached dataset into groups labeled by fn, under a single lock acquisition.
+// Values within a group keep insertion order. Values for which fn returns "" are left out.
+// The caller owns the returned map and slices.
+func (c *MemoryCache[V]) Partition(fn func(value V) string) map[string][]V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.partition(fn)
+}
+
+// partition groups values by fn. The caller must hold the lock.
+func (c *MemoryCache[V]) partition(fn func(value V) string) map[string][]V {
+	groups := make(map[string][]V)
+	for _, pk := range c.order {
+		v, exists := c.value(pk)
+		if !exists {
+			continue
+		}
+		if label := fn(v); label != "" {
+			groups[label] = append(groups[label], v)
+		}
+	}
+	return groups
+}
+
+// PartitionedView is a persistent partitioning of a MemoryCache by a label function.
+// Groups are computed lazily and recomputed only after the cache contents change,
+// so repeated per-group fan-out does not re-scan an unchanged dataset.
+// It is safe for concurrent use.
+type PartitionedView[V any] struct {
+	cache *MemoryCache[V]
+	fn    func(value V) string
+
+	mu       sync.Mutex
+	revision uint64
+	valid    bool
+	groups   map[string][]V
+}
+
+// NewPartitionedView creates a persistent partitioning of the cache by fn.
+// See Partition for how labels are assigned.
+func (c *MemoryCache[V]) NewPartitionedView(fn func(value V) string) *PartitionedView[V] {
+	return &PartitionedView[V]{cache: c, fn: fn}
+}
+
+// current returns the groups for the current cache contents, recomputing them if needed.
+func (p *PartitionedView[V]) current() map[string][]V {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.cache.mu.RLock()
+	defer p.cache.mu.RUnlock()
+
+	if !p.valid || p.revision != p.cache.revision {
+		p.groups = p.cache.partition(p.fn)
+		p.revision = p.cache.revision
+		p.valid = true
+	}
+	return p.groups
+}
+
+// Get returns the values labeled label, in insertion order.
+// The returned slice is shared with the view and must not be modified.
+func (p *PartitionedView[V]) Get(label string) []V {
+	return p.current()[label]
+}
+
+// Labels returns the labels of all non-empty groups, sorted.
+func (p *PartitionedView[V]) Labels() []string {
+	groups := p.current()
+	labels := make([]string, 0, len(groups))
+	for label := range groups {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Groups returns all groups keyed by label.
+// The map is a copy; the slices are shared with the view and must not be modified.
+func (p *PartitionedView[V]) Groups() map[string][]V {
+	groups := p.current()
+	result := make(map[string][]V, len(groups))
+	for label, values := range groups {
+		result[label] = values
+	}
+	return result
+}
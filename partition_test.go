@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func tenantOf(u TestTenantUser) string { return u.Tenant }
+
+func TestMemoryCache_Partition(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{
+		{ID: "1", Tenant: "acme"},
+		{ID: "2", Tenant: "globex"},
+		{ID: "3", Tenant: "acme"},
+		{ID: "4", Tenant: ""},
+	})
+
+	groups := cache.Partition(tenantOf)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %v", groups)
+	}
+	if acme := groups["acme"]; len(acme) != 2 || acme[0].ID != "1" || acme[1].ID != "3" {
+		t.Errorf("Expected acme [1 3] in insertion order, got %+v", acme)
+	}
+	if globex := groups["globex"]; len(globex) != 1 || globex[0].ID != "2" {
+		t.Errorf("Expected globex [2], got %+v", globex)
+	}
+	if _, ok := groups[""]; ok {
+		t.Error("Expected empty labels to be left out")
+	}
+}
+
+func TestPartitionedView(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}, {ID: "2", Tenant: "globex"}})
+
+	calls := 0
+	view := cache.NewPartitionedView(func(u TestTenantUser) string {
+		calls++
+		return u.Tenant
+	})
+
+	if labels := view.Labels(); strings.Join(labels, ",") != "acme,globex" {
+		t.Errorf("Expected labels acme,globex, got %v", labels)
+	}
+	if got := view.Get("acme"); len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("Expected acme [1], got %+v", got)
+	}
+	_ = view.Groups()
+	if calls != 2 {
+		t.Errorf("Expected groups computed once (2 calls), got %d calls", calls)
+	}
+
+	// Recomputed only after the dataset changes
+	cache.Set([]TestTenantUser{{ID: "3", Tenant: "initech"}})
+	groups := view.Groups()
+	if len(groups) != 1 || len(groups["initech"]) != 1 {
+		t.Errorf("Expected view to reflect new dataset, got %+v", groups)
+	}
+	if calls != 3 {
+		t.Errorf("Expected recomputation after Set (3 calls), got %d", calls)
+	}
+
+	cache.Clear()
+	if len(view.Labels()) != 0 {
+		t.Error("Expected no labels after Clear")
+	}
+}
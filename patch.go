@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Patch operations of a PatchRecord.
+const (
+	PatchUpsert = "upsert"
+	PatchDelete = "delete"
+)
+
+// ErrSnapshotNotFound is returned by ExportDiff when a hash matches no retained snapshot.
+var ErrSnapshotNotFound = errors.New("cache-kit: no retained snapshot with that hash")
+
+// PatchRecord is one line of an NDJSON patch stream: an upsert carrying the new value, or a
+// delete of a primary key.
+type PatchRecord[V any] struct {
+	Op    string `json:"op"`
+	Key   string `json:"key"`
+	Value *V     `json:"value,omitempty"`
+}
+
+// ExportDiff writes to w the NDJSON patch that turns the retained snapshot with hash fromHash
+// into the one with hash toHash: one upsert per added or changed entry (values compared with
+// reflect.DeepEqual), in toHash insertion order, followed by one delete per removed key.
+// An empty fromHash stands for the empty dataset, producing a full dump.
+// Requires Config.SnapshotRetention; returns ErrSnapshotNotFound for unknown hashes.
+func (c *MemoryCache[V]) ExportDiff(w io.Writer, fromHash, toHash string) error {
+	var from MapView[V]
+	if fromHash != "" {
+		snap, ok := c.AtHash(fromHash)
+		if !ok {
+			return fmt.Errorf("from %q: %w", fromHash, ErrSnapshotNotFound)
+		}
+		from = snap.MapView
+	}
+	to, ok := c.AtHash(toHash)
+	if !ok {
+		return fmt.Errorf("to %q: %w", toHash, ErrSnapshotNotFound)
+	}
+
+	enc := json.NewEncoder(w)
+	var err error
+	to.Range(func(pk string, v V) bool {
+		if old, exists := from.Get(pk); exists && reflect.DeepEqual(old, v) {
+			return true
+		}
+		err = enc.Encode(PatchRecord[V]{Op: PatchUpsert, Key: pk, Value: &v})
+		return err == nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write patch: %w", err)
+	}
+	for _, pk := range from.order {
+		if !to.Has(pk) {
+			if err := enc.Encode(PatchRecord[V]{Op: PatchDelete, Key: pk}); err != nil {
+				return fmt.Errorf("failed to write patch: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// PatchResult summarizes what ApplyPatch changed.
+type PatchResult struct {
+	Upserted int // entries added or replaced
+	Deleted  int // entries removed
+	Skipped  int // upserts rejected by ValidateFunc, and deletes of absent keys
+}
+
+// patchStep is a checked PatchRecord ready to be applied.
+type patchStep[V any] struct {
+	op    string
+	pk    string
+	value V
+	valid bool
+}
+
+// ApplyPatch applies an NDJSON upsert/delete stream, as written by ExportDiff, in stream order.
+// The whole stream is read and checked first: a malformed record, an unknown op, a missing key
+// or an upsert whose value has a different primary key (after NormalizeFunc) returns an error
+// and leaves the cache unchanged. Upserts rejected by ValidateFunc are skipped, like in Set.
+// The patch is applied as a single modification with indexes and tags maintained, and upserted
+// entries are marked as seen in a new generation, like Upsert.
+// Panics if PrimaryKeyFunc is nil and the patch contains upserts.
+func (c *MemoryCache[V]) ApplyPatch(r io.Reader) (PatchResult, error) {
+	var steps []patchStep[V]
+	dec := json.NewDecoder(r)
+	for n := 1; dec.More(); n++ {
+		var rec PatchRecord[V]
+		if err := dec.Decode(&rec); err != nil {
+			return PatchResult{}, fmt.Errorf("patch record %d: %w", n, err)
+		}
+		if rec.Key == "" {
+			return PatchResult{}, fmt.Errorf("patch record %d: missing key", n)
+		}
+		step := patchStep[V]{op: rec.Op, pk: rec.Key}
+		switch rec.Op {
+		case PatchDelete:
+		case PatchUpsert:
+			if rec.Value == nil {
+				return PatchResult{}, fmt.Errorf("patch record %d: upsert without value", n)
+			}
+			if c.config.PrimaryKeyFunc == nil {
+				panic("cache-kit: MultiIndexCache requires PrimaryKeyFunc when setting non-empty data; set it via config.WithPrimaryKey()")
+			}
+			v := *rec.Value
+			if c.config.NormalizeFunc != nil {
+				v = c.config.NormalizeFunc(v)
+			}
+			if pk := c.config.PrimaryKeyFunc(v); pk != rec.Key {
+				return PatchResult{}, fmt.Errorf("patch record %d: value has primary key %q, want %q", n, pk, rec.Key)
+			}
+			step.value = v
+			step.valid = c.config.ValidateFunc == nil || c.config.ValidateFunc(v) == nil
+		default:
+			return PatchResult{}, fmt.Errorf("patch record %d: unknown op %q", n, rec.Op)
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return PatchResult{}, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.watchedValues()
+	c.cloneStorage()
+	c.cloneIndexes()
+	c.generation++
+
+	var result PatchResult
+	deleted := false
+	for _, step := range steps {
+		switch {
+		case step.op == PatchDelete:
+			if c.deleteLocked(step.pk) {
+				result.Deleted++
+				deleted = true
+			} else {
+				result.Skipped++
+			}
+		case step.valid:
+			c.storeLocked(step.value)
+			result.Upserted++
+		default:
+			result.Skipped++
+		}
+	}
+	if deleted {
+		c.pruneOrder(true)
+	}
+
+	c.setHash(c.calculateHash())
+	c.updatedAt = c.now()
+	c.refreshedAt = c.updatedAt
+	c.revision++
+	c.retainSnapshot()
+	c.notifyWatchers(previous)
+	return result, nil
+}
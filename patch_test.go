@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func decodePatch(t *testing.T, data string) []PatchRecord[TestUser] {
+	t.Helper()
+	var records []PatchRecord[TestUser]
+	dec := json.NewDecoder(strings.NewReader(data))
+	for dec.More() {
+		var r PatchRecord[TestUser]
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("Invalid NDJSON patch: %v", err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestMemoryCache_ExportDiff(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithSnapshotRetention(5))
+
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2"}, {ID: "3"}})
+	from := cache.GetHash()
+	cache.Set([]TestUser{{ID: "1", Email: "changed@example.com"}, {ID: "3"}, {ID: "4"}})
+	to := cache.GetHash()
+
+	var buf bytes.Buffer
+	if err := cache.ExportDiff(&buf, from, to); err != nil {
+		t.Fatalf("ExportDiff error: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("Expected 3 NDJSON lines, got %d: %s", lines, buf.String())
+	}
+	records := decodePatch(t, buf.String())
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %+v", records)
+	}
+	if r := records[0]; r.Op != PatchUpsert || r.Key != "1" || r.Value == nil || r.Value.Email != "changed@example.com" {
+		t.Errorf("Expected upsert of changed entry 1, got %+v", r)
+	}
+	if r := records[1]; r.Op != PatchUpsert || r.Key != "4" {
+		t.Errorf("Expected upsert of added entry 4, got %+v", r)
+	}
+	if r := records[2]; r.Op != PatchDelete || r.Key != "2" || r.Value != nil {
+		t.Errorf("Expected delete of entry 2, got %+v", r)
+	}
+}
+
+func TestMemoryCache_ExportDiffFullDump(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithSnapshotRetention(1))
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+
+	var buf bytes.Buffer
+	if err := cache.ExportDiff(&buf, "", cache.GetHash()); err != nil {
+		t.Fatalf("ExportDiff error: %v", err)
+	}
+	if records := decodePatch(t, buf.String()); len(records) != 2 || records[1].Op != PatchUpsert {
+		t.Errorf("Expected full dump of 2 upserts, got %+v", records)
+	}
+}
+
+func TestMemoryCache_ExportDiffUnknownHash(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithSnapshotRetention(1))
+	cache.Set([]TestUser{{ID: "1"}})
+
+	if err := cache.ExportDiff(&bytes.Buffer{}, "missing", cache.GetHash()); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Expected ErrSnapshotNotFound for from, got %v", err)
+	}
+	if err := cache.ExportDiff(&bytes.Buffer{}, "", "missing"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Expected ErrSnapshotNotFound for to, got %v", err)
+	}
+}
+
+func newPatchTarget() *MemoryCache[TestUser] {
+	return NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email }).
+		WithValidateFunc(func(u TestUser) error {
+			if u.Email == "invalid" {
+				return errInvalidTestUser
+			}
+			return nil
+		}))
+}
+
+func TestMemoryCache_ApplyPatchReplicates(t *testing.T) {
+	source := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithSnapshotRetention(2))
+	source.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+	base := source.GetHash()
+
+	target := newPatchTarget()
+	var full bytes.Buffer
+	_ = source.ExportDiff(&full, "", base)
+	if _, err := target.ApplyPatch(&full); err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+
+	source.Set([]TestUser{{ID: "1", Email: "new@example.com"}, {ID: "3", Email: "c@example.com"}})
+	var delta bytes.Buffer
+	_ = source.ExportDiff(&delta, base, source.GetHash())
+	result, err := target.ApplyPatch(&delta)
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+	if result.Upserted != 2 || result.Deleted != 1 {
+		t.Errorf("Expected 2 upserts and 1 delete, got %+v", result)
+	}
+	if !target.Equal(source) {
+		t.Error("Expected target to match source after applying the delta")
+	}
+	if _, ok := target.GetByIndex("email", "a@example.com"); ok {
+		t.Error("Expected stale index key removed")
+	}
+	if u, ok := target.GetByIndex("email", "new@example.com"); !ok || u.ID != "1" {
+		t.Error("Expected index updated for the changed entry")
+	}
+}
+
+func TestMemoryCache_ApplyPatchOrderAndValidation(t *testing.T) {
+	target := newPatchTarget()
+	target.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+
+	patch := `{"op":"delete","key":"1"}
+{"op":"upsert","key":"1","value":{"ID":"1","Email":"back@example.com"}}
+{"op":"upsert","key":"3","value":{"ID":"3","Email":"invalid"}}
+{"op":"delete","key":"missing"}
+`
+	result, err := target.ApplyPatch(strings.NewReader(patch))
+	if err != nil {
+		t.Fatalf("ApplyPatch error: %v", err)
+	}
+	if result.Upserted != 1 || result.Deleted != 1 || result.Skipped != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if u, ok := target.Get("1"); !ok || u.Email != "back@example.com" {
+		t.Errorf("Expected entry 1 re-added by the later upsert, got %+v", u)
+	}
+	if _, ok := target.Get("3"); ok {
+		t.Error("Expected invalid upsert skipped")
+	}
+	if ids := idsOf(target.GetAll()); ids != "1,2" {
+		t.Errorf("Expected order 1,2 without duplicates, got %s", ids)
+	}
+}
+
+func TestMemoryCache_ApplyPatchRejectsBadStreams(t *testing.T) {
+	tests := map[string]string{
+		"malformed":    `{"op":"upsert"`,
+		"unknown op":   `{"op":"merge","key":"1"}`,
+		"missing key":  `{"op":"delete"}`,
+		"no value":     `{"op":"upsert","key":"1"}`,
+		"key mismatch": `{"op":"upsert","key":"1","value":{"ID":"2"}}`,
+	}
+	for name, patch := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := newPatchTarget()
+			target.Set([]TestUser{{ID: "1"}})
+			hash := target.GetHash()
+
+			stream := `{"op":"delete","key":"1"}` + "\n" + patch
+			if _, err := target.ApplyPatch(strings.NewReader(stream)); err == nil {
+				t.Fatal("Expected error")
+			}
+			if target.GetHash() != hash || target.Len() != 1 {
+				t.Error("Expected cache unchanged after a rejected patch")
+			}
+		})
+	}
+}
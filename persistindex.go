@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// indexKeySuffix is appended to the data key to name the set of persisted index names;
+// each index is stored in the hash indexKeySuffix + ":" + name.
+const indexKeySuffix = ":idx"
+
+// getByIndexScript resolves an index key and reads the matching hash field in one round trip.
+// KEYS: index hash, data hash. ARGV: index key. Returns {pk, value} or nil.
+var getByIndexScript = redis.NewScript(`
+local pk = redis.call('HGET', KEYS[1], ARGV[1])
+if not pk then
+  return nil
+end
+local value = redis.call('HGET', KEYS[2], pk)
+if not value then
+  return nil
+end
+return {pk, value}
+`)
+
+// indexNamesKey returns the key of the set of persisted index names.
+func (c *RedisCache[V]) indexNamesKey() string {
+	return c.key + indexKeySuffix
+}
+
+// indexKey returns the hash key of the persisted index name.
+func (c *RedisCache[V]) indexKey(name string) string {
+	return c.key + indexKeySuffix + ":" + name
+}
+
+// writeIndexes replaces the persisted indexes with indexes (name -> index key -> primary key),
+// dropping indexes persisted earlier that are no longer present, in one transaction.
+func (c *RedisCache[V]) writeIndexes(ctx context.Context, indexes map[string]map[string]string, ttl time.Duration) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpWriteIndexes, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	previous, err := c.client.SMembers(ctx, c.indexNamesKey()).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read persisted indexes: %w", err)
+	}
+
+	pipe := c.client.TxPipeline()
+	for _, name := range previous {
+		pipe.Del(ctx, c.indexKey(name))
+	}
+	pipe.Del(ctx, c.indexNamesKey())
+	for name, index := range indexes {
+		pipe.SAdd(ctx, c.indexNamesKey(), name)
+		fields := make([]any, 0, 2*min(len(index), writeBatchSize))
+		for key, pk := range index {
+			fields = append(fields, key, pk)
+			if len(fields) == 2*writeBatchSize {
+				pipe.HSet(ctx, c.indexKey(name), fields...)
+				fields = make([]any, 0, 2*writeBatchSize)
+			}
+		}
+		if len(fields) > 0 {
+			pipe.HSet(ctx, c.indexKey(name), fields...)
+		}
+		pipe.Expire(ctx, c.indexKey(name), ttl)
+	}
+	if len(indexes) > 0 {
+		pipe.Expire(ctx, c.indexNamesKey(), ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write indexes: %w", err)
+	}
+	return nil
+}
+
+// deleteIndexes removes all persisted indexes.
+func (c *RedisCache[V]) deleteIndexes(ctx context.Context) error {
+	names, err := c.client.SMembers(ctx, c.indexNamesKey()).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{c.indexNamesKey()}
+	for _, name := range names {
+		keys = append(keys, c.indexKey(name))
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// LookupIndex resolves key in an index persisted by HybridCache.WithPersistedIndexes to
+// the primary key of the matching value, without downloading the dataset. Keys are
+// normalized like MemoryCache.GetByIndex does. Returns false if nothing matches.
+func (c *RedisCache[V]) LookupIndex(indexName, key string) (string, bool, error) {
+	return c.LookupIndexCtx(context.Background(), indexName, key)
+}
+
+// LookupIndexCtx is like LookupIndex but uses ctx for the Redis calls.
+func (c *RedisCache[V]) LookupIndexCtx(ctx context.Context, indexName, key string) (_ string, _ bool, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpLookupIndex, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return "", false, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	pk, err := c.client.HGet(ctx, c.indexKey(indexName), normalizeIndexKey(key)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up index: %w", err)
+	}
+	return pk, true, nil
+}
+
+// GetByIndex fetches the value matching key in a persisted index, in one round trip.
+// Requires hash storage (see WithHashStorage); returns ErrNotHashStorage otherwise, in
+// which case use LookupIndex. Returns false if nothing matches.
+func (c *RedisCache[V]) GetByIndex(indexName, key string) (V, bool, error) {
+	return c.GetByIndexCtx(context.Background(), indexName, key)
+}
+
+// GetByIndexCtx is like GetByIndex but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetByIndexCtx(ctx context.Context, indexName, key string) (_ V, _ bool, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpGetByIndex, start, size, err) }()
+
+	var zero V
+	if err := c.admit(); err != nil {
+		return zero, false, err
+	}
+	if c.storage != storageHash {
+		return zero, false, ErrNotHashStorage
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	result, err := getByIndexScript.Run(ctx, c.client, []string{c.indexKey(indexName), c.key},
+		normalizeIndexKey(key)).StringSlice()
+	if err == redis.Nil {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, fmt.Errorf("failed to get by index: %w", err)
+	}
+	size = len(result[1])
+	v, err := c.decodeItem(result[0], result[1])
+	if err != nil {
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// indexMaps returns the current indexes (name -> index key -> primary key).
+// The inner maps are never modified once published and may be read without the lock.
+func (c *MemoryCache[V]) indexMaps() map[string]map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	indexes := make(map[string]map[string]string, len(c.indexes))
+	for name, index := range c.indexes {
+		indexes[name] = index
+	}
+	return indexes
+}
+
+// indexMapsOf returns the indexes Set(values) would build, without touching the cache.
+func (c *MemoryCache[V]) indexMapsOf(values []V) map[string]map[string]string {
+	c.mu.RLock()
+	config := *c.config
+	indexFns := maps.Clone(c.indexFns)
+	c.mu.RUnlock()
+
+	// The scratch cache only needs what decides the index keys.
+	config.Indexes = nil
+	config.TagsFunc = nil
+	config.SetTimingsFunc = nil
+	config.AutoDropColdIndexes = false
+	config.SnapshotRetention = 0
+	config.ExpectedSize = len(values)
+	scratch := NewMultiIndexCache(&config)
+	for name, keyFunc := range indexFns {
+		scratch.AddIndex(name, keyFunc)
+	}
+	scratch.Set(values)
+	return scratch.indexMaps()
+}
+
+// WithPersistedIndexes makes Set and SyncToRedis also write every memory index
+// (index key -> primary key) to a Redis hash, so consumers holding only a RedisCache can
+// resolve lookups server-side with RedisCache.LookupIndex, or RedisCache.GetByIndex under
+// hash storage. Indexes are written after the dataset, in a separate transaction.
+func (c *HybridCache[V]) WithPersistedIndexes() *HybridCache[V] {
+	c.persistIndexes = true
+	return c
+}
+
+// syncIndexes persists the indexes of the dataset values, just written to Redis, if
+// WithPersistedIndexes is set. They are built from values rather than read from memory,
+// which may hold another dataset (see WriteRedisOnly and WithWriteBehind).
+func (c *HybridCache[V]) syncIndexes(values []V) error {
+	if !c.persistIndexes || c.redis == nil {
+		return nil
+	}
+	ttl := c.redis.effectiveTTL(c.redis.setTTL(values))
+	return c.redis.writeIndexes(context.Background(), c.memory.indexMapsOf(values), ttl)
+}
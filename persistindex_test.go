@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newPersistedIndexHybrid(t *testing.T, hashStorage bool) (*HybridCache[TestUser], *RedisCache[TestUser]) {
+	_, client := setupMiniRedis(t)
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email })
+	hybrid := NewHybridCache(config, client, DefaultRedisConfig()).WithPersistedIndexes()
+	consumer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if hashStorage {
+		pk := func(u TestUser) string { return u.ID }
+		hybrid.Redis().WithHashStorage(pk)
+		consumer.WithHashStorage(pk)
+	}
+	return hybrid, consumer
+}
+
+func TestHybridCache_PersistedIndexes(t *testing.T) {
+	hybrid, consumer := newPersistedIndexHybrid(t, false)
+
+	if err := hybrid.Set([]TestUser{{ID: "1", Email: "Alice@Example.com"}, {ID: "2", Email: "bob@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	pk, ok, err := consumer.LookupIndex("email", " alice@example.com")
+	if err != nil || !ok || pk != "1" {
+		t.Errorf("Expected normalized lookup to find 1, got %q ok=%v err=%v", pk, ok, err)
+	}
+	if _, ok, err := consumer.LookupIndex("email", "nobody@example.com"); err != nil || ok {
+		t.Errorf("Expected no match, got ok=%v err=%v", ok, err)
+	}
+	if _, _, err := consumer.GetByIndex("email", "bob@example.com"); !errors.Is(err, ErrNotHashStorage) {
+		t.Errorf("Expected ErrNotHashStorage in blob mode, got %v", err)
+	}
+
+	// A later write replaces the index contents, and dropped indexes disappear.
+	hybrid.Memory().AddIndex("name", func(u TestUser) string { return u.Name })
+	hybrid.Memory().RemoveIndex("email")
+	if err := hybrid.Set([]TestUser{{ID: "3", Name: "Carol"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, ok, _ := consumer.LookupIndex("email", "bob@example.com"); ok {
+		t.Error("Expected removed index to be dropped from Redis")
+	}
+	if pk, ok, _ := consumer.LookupIndex("name", "carol"); !ok || pk != "3" {
+		t.Errorf("Expected new index persisted, got %q ok=%v", pk, ok)
+	}
+
+	if err := consumer.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if _, ok, _ := consumer.LookupIndex("name", "carol"); ok {
+		t.Error("Expected Clear to remove persisted indexes")
+	}
+}
+
+func TestRedisCache_GetByIndex(t *testing.T) {
+	hybrid, consumer := newPersistedIndexHybrid(t, true)
+	if err := hybrid.Set([]TestUser{{ID: "1", Name: "Alice", Email: "alice@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	u, ok, err := consumer.GetByIndex("email", "ALICE@example.com")
+	if err != nil || !ok || u.Name != "Alice" {
+		t.Errorf("Expected Alice, got %+v ok=%v err=%v", u, ok, err)
+	}
+	if _, ok, err := consumer.GetByIndex("email", "nobody@example.com"); err != nil || ok {
+		t.Errorf("Expected no match, got ok=%v err=%v", ok, err)
+	}
+	if _, ok, err := consumer.GetByIndex("unknown", "x"); err != nil || ok {
+		t.Errorf("Expected no match for an unknown index, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestHybridCache_PersistedIndexesSync(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email })
+	hybrid := NewHybridCache(config, client, DefaultRedisConfig().WithTTL(time.Minute)).WithPersistedIndexes()
+
+	hybrid.Memory().Set([]TestUser{{ID: "1", Email: "a@example.com"}})
+	if err := hybrid.SyncToRedis(); err != nil {
+		t.Fatalf("SyncToRedis error: %v", err)
+	}
+	if ttl := mr.TTL("cache:data" + indexKeySuffix + ":email"); ttl != time.Minute {
+		t.Errorf("Expected index TTL to follow the data TTL, got %v", ttl)
+	}
+	if pk, ok, _ := hybrid.Redis().LookupIndex("email", "a@example.com"); !ok || pk != "1" {
+		t.Errorf("Expected synced index, got %q ok=%v", pk, ok)
+	}
+}
+
+func TestHybridCache_WithoutPersistedIndexes(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email })
+	hybrid := NewHybridCache(config, client, DefaultRedisConfig())
+
+	if err := hybrid.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if mr.Exists("cache:data" + indexKeySuffix + ":email") {
+		t.Error("Expected indexes not to be persisted by default")
+	}
+}
+
+func TestHybridCache_PersistedIndexesWriteRedisOnly(t *testing.T) {
+	hybrid, consumer := newPersistedIndexHybrid(t, false)
+	hybrid.Memory().Set([]TestUser{{ID: "1", Email: "old@example.com"}})
+	hybrid.WithWritePolicy(WriteRedisOnly)
+
+	if err := hybrid.Set([]TestUser{{ID: "2", Email: "new@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if pk, ok, _ := consumer.LookupIndex("email", "new@example.com"); !ok || pk != "2" {
+		t.Errorf("Expected the index of the written dataset, got %q ok=%v", pk, ok)
+	}
+	if _, ok, _ := consumer.LookupIndex("email", "old@example.com"); ok {
+		t.Error("Expected no index entry from the memory dataset")
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanBatch is the COUNT hint for SCAN and the number of deletions per pipeline in ClearPrefix.
+const scanBatch = 1000
+
+// globEscaper escapes Redis glob metacharacters.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// ClearPrefix deletes every key under RedisConfig.KeyPrefix (KeyBuilder.Prefix with a
+// KeyBuilder), including keys derived from
+// the data key (version, metadata, stale copy, indexes, locks) and keys of other caches
+// sharing the prefix, e.g. to tear down a tenant or test namespace. With ClusterHashTag,
+// hash-tagged keys ({prefix...}) are matched as well; on a cluster every master is scanned.
+// Keys are found with SCAN, so keys written concurrently may survive. Returns the number of
+// keys deleted. Caches created with NewRedisCacheWithKey have no prefix and fail; use
+// Clear for those.
+func (c *RedisCache[V]) ClearPrefix(ctx context.Context) (deleted int64, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpClearPrefix, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, err
+	}
+	prefix := c.prefix
+	if prefix == "" {
+		return 0, fmt.Errorf("refusing to clear a prefix: cache %q was created with a custom key", c.key)
+	}
+	patterns := []string{globEscaper.Replace(prefix) + "*"}
+	if c.config.ClusterHashTag && !strings.HasPrefix(prefix, "{") {
+		patterns = append(patterns, "{"+globEscaper.Replace(prefix)+"*")
+	}
+
+	var total int64
+	clear := func(ctx context.Context, client redis.UniversalClient) error {
+		for _, pattern := range patterns {
+			n, err := deleteMatching(ctx, client, pattern)
+			total += n
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return clear(ctx, node)
+		})
+	} else {
+		err = clear(ctx, c.client)
+	}
+	if err != nil {
+		return total, fmt.Errorf("failed to clear prefix: %w", err)
+	}
+	return total, nil
+}
+
+// deleteMatching deletes the keys matching pattern on a single node. Keys are deleted one per
+// command, so keys from different cluster slots can share a pipeline.
+func deleteMatching(ctx context.Context, client redis.UniversalClient, pattern string) (int64, error) {
+	var deleted int64
+	iter := client.Scan(ctx, 0, pattern, scanBatch).Iterator()
+	pipe := client.Pipeline()
+	flush := func() error {
+		cmds, err := pipe.Exec(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cmd := range cmds {
+			deleted += cmd.(*redis.IntCmd).Val()
+		}
+		return nil
+	}
+	for iter.Next(ctx) {
+		pipe.Del(ctx, iter.Val())
+		if pipe.Len() >= scanBatch {
+			if err := flush(); err != nil {
+				return deleted, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+	if pipe.Len() > 0 {
+		if err := flush(); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
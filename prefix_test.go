@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisCache_ClearPrefix(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().
+		WithKeyPrefix("tenant[1]:").
+		WithPublishMetadata(true).
+		WithStaleTTL(time.Hour)
+	cache := NewRedisCache[TestUser](client, config)
+	other := NewRedisCacheWithKey[TestUser](client, "tenant[1]:other", DefaultRedisConfig())
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := other.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, err := cache.AcquireRebuildLock(context.Background(), time.Minute); err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+	// Keys that merely match the unescaped glob must survive.
+	_ = mr.Set("tenant1:data", "x")
+	_ = mr.Set("unrelated", "x")
+
+	deleted, err := cache.ClearPrefix(context.Background())
+	if err != nil {
+		t.Fatalf("ClearPrefix error: %v", err)
+	}
+	// data, version, meta, stale, lock + other's data and version
+	if deleted != 7 {
+		t.Errorf("Expected 7 keys deleted, got %d (remaining %v)", deleted, mr.Keys())
+	}
+	if keys := mr.Keys(); len(keys) != 2 || keys[0] != "tenant1:data" || keys[1] != "unrelated" {
+		t.Errorf("Expected only keys outside the prefix to remain, got %v", keys)
+	}
+
+	if deleted, err := cache.ClearPrefix(context.Background()); err != nil || deleted != 0 {
+		t.Errorf("Expected nothing left to delete, got %d (%v)", deleted, err)
+	}
+}
+
+func TestRedisCache_ClearPrefixCustomKey(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	custom := NewRedisCacheWithKey[TestUser](client, "myapp:users", DefaultRedisConfig())
+	other := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := custom.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := other.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	if deleted, err := custom.ClearPrefix(context.Background()); err == nil || deleted != 0 {
+		t.Errorf("Expected ClearPrefix to refuse a custom-key cache, got %d (%v)", deleted, err)
+	}
+	for _, key := range []string{"myapp:users", "myapp:users:version", "cache:data", "cache:data:version"} {
+		if !mr.Exists(key) {
+			t.Errorf("Expected %s to survive, got %v", key, mr.Keys())
+		}
+	}
+}
+
+func TestRedisCache_ClearPrefixClusterHashTag(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithKeyPrefix("ns:").
+		WithClusterHashTag(true))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	_ = mr.Set("ns:plain", "x")
+
+	deleted, err := cache.ClearPrefix(context.Background())
+	if err != nil {
+		t.Fatalf("ClearPrefix error: %v", err)
+	}
+	if deleted != 3 || len(mr.Keys()) != 0 {
+		t.Errorf("Expected tagged and plain keys deleted, got %d (remaining %v)", deleted, mr.Keys())
+	}
+}
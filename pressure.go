@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"time"
+)
+
+// PressureRelief reports what RelievePressure freed.
+type PressureRelief struct {
+	// DroppedIndexes lists the cold indexes that were removed (see Config.IndexColdAfter).
+	DroppedIndexes []string
+
+	// Evicted is the number of stale entries removed (see Config.PressureEvictStaleAfter).
+	Evicted int
+
+	// Compacted reports whether storage was rebuilt; it is false when there was no pressure.
+	Compacted bool
+}
+
+// MemoryLimitPressure returns a pressure function for Config.WithMemoryPressure that reports
+// true once the memory the Go runtime counts against its soft limit (debug.SetMemoryLimit,
+// or GOMEMLIMIT) reaches fraction of that limit. Without a limit it never reports pressure.
+func MemoryLimitPressure(fraction float64) func() bool {
+	return func() bool {
+		limit := debug.SetMemoryLimit(-1)
+		if limit <= 0 || limit == math.MaxInt64 {
+			return false
+		}
+		samples := []metrics.Sample{
+			{Name: "/memory/classes/total:bytes"},
+			{Name: "/memory/classes/heap/released:bytes"},
+		}
+		metrics.Read(samples)
+		used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+		return overLimit(used, limit, fraction)
+	}
+}
+
+// overLimit reports whether used bytes reach fraction of limit.
+func overLimit(used uint64, limit int64, fraction float64) bool {
+	return float64(used) >= fraction*float64(limit)
+}
+
+// RelievePressure checks Config.MemoryPressureFunc and, if it reports pressure, frees what
+// the cache can give up without losing current data: cold indexes are dropped, entries stale
+// for PressureEvictStaleAfter generations are evicted when that is set, and storage is
+// compacted (arena bytes of replaced values and spare map capacity are released).
+// It does nothing when no pressure function is configured.
+func (c *MemoryCache[V]) RelievePressure() PressureRelief {
+	if c.config.MemoryPressureFunc == nil || !c.config.MemoryPressureFunc() {
+		return PressureRelief{}
+	}
+
+	var relief PressureRelief
+	defer func() { c.logEvicted(relief.Evicted) }() // after unlocking
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	relief.DroppedIndexes = c.dropColdIndexes()
+	if after := c.config.PressureEvictStaleAfter; after > 0 {
+		stale := make(map[string]struct{})
+		for _, pk := range c.order {
+			if c.isStale(pk, after) {
+				stale[pk] = struct{}{}
+			}
+		}
+		if len(stale) > 0 {
+			relief.Evicted = c.removeEntries(stale)
+		}
+	}
+	c.compactStorage()
+	relief.Compacted = true
+	return relief
+}
+
+// WatchMemoryPressure calls RelievePressure every interval until ctx is done, passing each
+// relief that freed something to onRelief (if non-nil). It returns ctx.Err().
+func (c *MemoryCache[V]) WatchMemoryPressure(ctx context.Context, interval time.Duration, onRelief func(PressureRelief)) error {
+	return RunRefreshLoop(ctx, FixedInterval(interval), func(context.Context) error {
+		if relief := c.RelievePressure(); relief.Compacted && onRelief != nil {
+			onRelief(relief)
+		}
+		return nil
+	}, nil)
+}
+
+// compactStorage rebuilds storage and the order slice at their exact size. The contents are
+// unchanged, so the hash and revision are kept. The caller must hold the write lock.
+func (c *MemoryCache[V]) compactStorage() {
+	if c.arena != nil {
+		c.arena = c.arena.compacted(c.order)
+	} else {
+		data := make(map[string]V, len(c.order))
+		for _, pk := range c.order {
+			data[pk] = c.data[pk]
+		}
+		c.data = data
+	}
+	order := make([]string, len(c.order))
+	copy(order, c.order)
+	c.order = order
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestRelievePressure_NoPressure(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}})
+	if relief := cache.RelievePressure(); relief.Compacted {
+		t.Error("Expected no relief without a pressure function")
+	}
+
+	cache = NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithMemoryPressure(func() bool { return false }, 1))
+	cache.Set([]TestUser{{ID: "1"}})
+	if relief := cache.RelievePressure(); relief.Compacted || relief.Evicted != 0 {
+		t.Errorf("Expected no relief when not under pressure, got %+v", relief)
+	}
+}
+
+func TestRelievePressure_EvictsStaleAndDropsColdIndexes(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1000, 0)}
+	pressure := false
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithColdIndexPolicy(time.Minute, false).
+		WithMemoryPressure(func() bool { return pressure }, 2))
+	cache.now = clock.Now
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.AddIndex("name", func(u TestUser) string { return u.Name })
+
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+	cache.Upsert([]TestUser{{ID: "2", Email: "b@example.com"}})
+	cache.Upsert([]TestUser{{ID: "2", Email: "b@example.com"}})
+	hash := cache.GetHash()
+
+	clock.Advance(2 * time.Minute)
+	cache.GetByIndex("email", "b@example.com") // keep "email" warm
+
+	if relief := cache.RelievePressure(); relief.Compacted {
+		t.Fatal("Expected nothing freed before pressure")
+	}
+
+	pressure = true
+	relief := cache.RelievePressure()
+	if !relief.Compacted {
+		t.Error("Expected storage compacted under pressure")
+	}
+	if relief.Evicted != 1 {
+		t.Errorf("Expected 1 stale entry evicted, got %d", relief.Evicted)
+	}
+	if len(relief.DroppedIndexes) != 1 || relief.DroppedIndexes[0] != "name" {
+		t.Errorf("Expected cold index 'name' dropped, got %v", relief.DroppedIndexes)
+	}
+	if _, ok := cache.Get("1"); ok {
+		t.Error("Expected stale entry 1 evicted")
+	}
+	if _, ok := cache.GetByIndex("email", "b@example.com"); !ok {
+		t.Error("Expected fresh entry to survive with its index")
+	}
+	if cache.GetHash() == hash {
+		t.Error("Expected hash to change after eviction")
+	}
+}
+
+func TestRelievePressure_CompactsArena(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithArenaStorage(nil).
+		WithMemoryPressure(func() bool { return true }, 0))
+	cache.Set([]TestUser{{ID: "1", Name: "first"}, {ID: "2", Name: "second"}})
+	for range 10 {
+		cache.Upsert([]TestUser{{ID: "1", Name: "replaced"}})
+	}
+	hash := cache.GetHash()
+	before := cache.ArenaBytes()
+	view := cache.AsMapRef()
+
+	relief := cache.RelievePressure()
+	if relief.Evicted != 0 {
+		t.Errorf("Expected no eviction when disabled, got %d", relief.Evicted)
+	}
+	if after := cache.ArenaBytes(); after >= before {
+		t.Errorf("Expected arena to shrink, before %d after %d", before, after)
+	}
+	if cache.GetHash() != hash {
+		t.Error("Expected compaction to keep the hash")
+	}
+	if v, ok := cache.Get("1"); !ok || v.Name != "replaced" {
+		t.Errorf("Expected latest value after compaction, got %+v", v)
+	}
+	if v, ok := view.Get("2"); !ok || v.Name != "second" {
+		t.Error("Expected existing views to stay readable after compaction")
+	}
+}
+
+func TestWatchMemoryPressure(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithMemoryPressure(func() bool { return true }, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reliefs := 0
+	err := cache.WatchMemoryPressure(ctx, time.Millisecond, func(PressureRelief) {
+		reliefs++
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || reliefs != 1 {
+		t.Errorf("Expected one relief then context.Canceled, got %d, %v", reliefs, err)
+	}
+}
+
+func TestMemoryLimitPressure(t *testing.T) {
+	if overLimit(50, 100, 0.9) || !overLimit(95, 100, 0.9) {
+		t.Error("overLimit threshold incorrect")
+	}
+
+	old := debug.SetMemoryLimit(-1)
+	defer debug.SetMemoryLimit(old)
+
+	debug.SetMemoryLimit(1 << 62)
+	if MemoryLimitPressure(0.9)() {
+		t.Error("Expected no pressure far below the limit")
+	}
+}
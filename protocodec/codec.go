@@ -0,0 +1,152 @@
+// Package protocodec provides cache-kit codecs for protobuf models, so services with existing
+// protobuf types can store them in RedisCache (RedisConfig.WithCodec) or arena storage
+// (Config.WithArenaStorage) without maintaining JSON tags as well.
+//
+// Slices are encoded as a sequence of size-delimited messages (see protodelim), single values
+// with proto.Marshal.
+package protocodec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+var messageType = reflect.TypeFor[proto.Message]()
+
+// Codec encodes values whose type implements proto.Message (e.g. *pb.User) and slices of them.
+// The zero value is ready to use.
+type Codec struct {
+	MarshalOptions   proto.MarshalOptions
+	UnmarshalOptions proto.UnmarshalOptions
+}
+
+// Marshal encodes a proto.Message or a slice of proto.Message.
+func (c Codec) Marshal(v any) ([]byte, error) {
+	if m, ok := v.(proto.Message); ok {
+		return c.MarshalOptions.Marshal(m)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || !rv.Type().Elem().Implements(messageType) {
+		return nil, fmt.Errorf("protocodec: cannot marshal %T: not a proto.Message or a slice of them", v)
+	}
+	var buf bytes.Buffer
+	opts := protodelim.MarshalOptions{MarshalOptions: c.MarshalOptions}
+	for i := range rv.Len() {
+		elem := rv.Index(i)
+		m, _ := elem.Interface().(proto.Message)
+		if m == nil || (elem.Kind() == reflect.Pointer && elem.IsNil()) {
+			return nil, fmt.Errorf("protocodec: nil message at index %d", i)
+		}
+		if _, err := opts.MarshalTo(&buf, m); err != nil {
+			return nil, fmt.Errorf("protocodec: marshal message %d: %w", i, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes into a proto.Message or a pointer to a slice of message pointers
+// (e.g. *[]*pb.User), replacing the slice contents.
+func (c Codec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return c.UnmarshalOptions.Unmarshal(data, m)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("protocodec: cannot unmarshal into %T: not a proto.Message or a pointer to a slice", v)
+	}
+	elem := rv.Elem().Type().Elem()
+	if elem.Kind() != reflect.Pointer || !elem.Implements(messageType) {
+		return fmt.Errorf("protocodec: cannot unmarshal into %T: slice elements must be message pointers", v)
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), 0, 0)
+	err := c.unmarshalDelimited(data,
+		func() proto.Message { return reflect.New(elem.Elem()).Interface().(proto.Message) },
+		func(m proto.Message) { slice = reflect.Append(slice, reflect.ValueOf(m)) })
+	if err != nil {
+		return err
+	}
+	rv.Elem().Set(slice)
+	return nil
+}
+
+// unmarshalDelimited decodes each size-delimited message in data into a message from
+// newMessage and passes it to add. The payload size is bounded by the caller (e.g.
+// RedisConfig.MaxValueBytes), so individual messages are not limited.
+func (c Codec) unmarshalDelimited(data []byte, newMessage func() proto.Message, add func(proto.Message)) error {
+	r := bytes.NewReader(data)
+	opts := protodelim.UnmarshalOptions{UnmarshalOptions: c.UnmarshalOptions, MaxSize: -1}
+	for i := 0; ; i++ {
+		m := newMessage()
+		err := opts.UnmarshalFrom(r, m)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("protocodec: unmarshal message %d: %w", i, err)
+		}
+		add(m)
+	}
+}
+
+// ConverterCodec stores a domain type V through its protobuf representation M, for models
+// that are not protobuf types themselves. Build it with Converter.
+type ConverterCodec[V any, M proto.Message] struct {
+	codec     Codec
+	toProto   func(V) M
+	fromProto func(M) V
+	newProto  func() M
+}
+
+// Converter returns a codec for V that converts each value with toProto before encoding and
+// with fromProto after decoding; newProto returns an empty message to decode into.
+func Converter[V any, M proto.Message](toProto func(V) M, fromProto func(M) V, newProto func() M) *ConverterCodec[V, M] {
+	return &ConverterCodec[V, M]{toProto: toProto, fromProto: fromProto, newProto: newProto}
+}
+
+// Marshal encodes a V or a []V.
+func (c *ConverterCodec[V, M]) Marshal(v any) ([]byte, error) {
+	switch x := v.(type) {
+	case V:
+		return c.codec.Marshal(c.toProto(x))
+	case []V:
+		msgs := make([]M, len(x))
+		for i, value := range x {
+			msgs[i] = c.toProto(value)
+		}
+		return c.codec.Marshal(msgs)
+	default:
+		return nil, fmt.Errorf("protocodec: cannot marshal %T with a converter for %T", v, *new(V))
+	}
+}
+
+// Unmarshal decodes into a *V or a *[]V.
+func (c *ConverterCodec[V, M]) Unmarshal(data []byte, v any) error {
+	switch x := v.(type) {
+	case *V:
+		m := c.newProto()
+		if err := c.codec.Unmarshal(data, m); err != nil {
+			return err
+		}
+		*x = c.fromProto(m)
+		return nil
+	case *[]V:
+		values := []V{}
+		err := c.codec.unmarshalDelimited(data,
+			func() proto.Message { return c.newProto() },
+			func(m proto.Message) { values = append(values, c.fromProto(m.(M))) })
+		if err != nil {
+			return err
+		}
+		*x = values
+		return nil
+	default:
+		return fmt.Errorf("protocodec: cannot unmarshal into %T with a converter for %T", v, *new(V))
+	}
+}
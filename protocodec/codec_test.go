@@ -0,0 +1,137 @@
+package protocodec
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	cache "github.com/soulteary/cache-kit"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// account is a domain type without protobuf or JSON tags.
+type account struct {
+	ID   string
+	Plan string
+}
+
+func accountCodec() *ConverterCodec[account, *wrapperspb.StringValue] {
+	return Converter(
+		func(a account) *wrapperspb.StringValue { return wrapperspb.String(a.ID + "/" + a.Plan) },
+		func(m *wrapperspb.StringValue) account {
+			for i := range len(m.Value) {
+				if m.Value[i] == '/' {
+					return account{ID: m.Value[:i], Plan: m.Value[i+1:]}
+				}
+			}
+			return account{ID: m.Value}
+		},
+		func() *wrapperspb.StringValue { return &wrapperspb.StringValue{} },
+	)
+}
+
+func newRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("Failed to start miniredis: %v", err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+		mr.Close()
+	})
+	return client
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	var codec Codec
+	values := []*wrapperspb.StringValue{wrapperspb.String("a"), wrapperspb.String(""), wrapperspb.String("c")}
+
+	data, err := codec.Marshal(values)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var decoded []*wrapperspb.StringValue
+	if err := codec.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(decoded) != 3 || decoded[0].Value != "a" || decoded[2].Value != "c" {
+		t.Errorf("Unexpected round trip result: %v", decoded)
+	}
+
+	single, err := codec.Marshal(wrapperspb.String("x"))
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var m wrapperspb.StringValue
+	if err := codec.Unmarshal(single, &m); err != nil || m.Value != "x" {
+		t.Errorf("Expected single message round trip, got %q (err %v)", m.Value, err)
+	}
+
+	var empty []*wrapperspb.StringValue
+	if err := codec.Unmarshal(nil, &empty); err != nil || empty == nil || len(empty) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %v (err %v)", empty, err)
+	}
+}
+
+func TestCodec_Errors(t *testing.T) {
+	var codec Codec
+	if _, err := codec.Marshal([]string{"not proto"}); err == nil {
+		t.Error("Expected error marshaling non-proto slice")
+	}
+	if _, err := codec.Marshal([]*wrapperspb.StringValue{nil}); err == nil {
+		t.Error("Expected error marshaling nil message")
+	}
+	var out []string
+	if err := codec.Unmarshal(nil, &out); err == nil {
+		t.Error("Expected error unmarshaling into non-proto slice")
+	}
+	var msgs []*wrapperspb.StringValue
+	if err := codec.Unmarshal([]byte{0x05, 0x01}, &msgs); err == nil {
+		t.Error("Expected error for truncated payload")
+	}
+}
+
+func TestCodec_RedisCache(t *testing.T) {
+	rc := cache.NewRedisCache[*wrapperspb.StringValue](newRedis(t), cache.DefaultRedisConfig().WithCodec(Codec{}))
+	if err := rc.Set([]*wrapperspb.StringValue{wrapperspb.String("hello")}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, err := rc.Get()
+	if err != nil || len(values) != 1 || values[0].GetValue() != "hello" {
+		t.Errorf("Expected protobuf round trip through Redis, got %v (err %v)", values, err)
+	}
+}
+
+func TestConverter_HybridCache(t *testing.T) {
+	codec := accountCodec()
+	memConfig := cache.DefaultConfig[account]().
+		WithPrimaryKey(func(a account) string { return a.ID }).
+		WithIndex("plan", func(a account) string { return a.Plan }).
+		WithArenaStorage(codec)
+	client := newRedis(t)
+
+	writer := cache.NewHybridCache[account](memConfig, client, cache.DefaultRedisConfig().WithCodec(codec))
+	if err := writer.Set([]account{{ID: "1", Plan: "pro"}, {ID: "2", Plan: "free"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if a, ok := writer.GetByIndex("plan", "pro"); !ok || a.ID != "1" {
+		t.Errorf("Expected arena lookup through converter, got %+v", a)
+	}
+
+	reader := cache.NewHybridCache[account](memConfig, client, cache.DefaultRedisConfig().WithCodec(codec))
+	if err := reader.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if a, ok := reader.GetByIndex("plan", "free"); !ok || a.ID != "2" {
+		t.Errorf("Expected converted values loaded from Redis, got %+v", a)
+	}
+
+	if _, err := codec.Marshal("wrong"); err == nil {
+		t.Error("Expected error marshaling an unrelated type")
+	}
+	if err := codec.Unmarshal(nil, new(string)); err == nil {
+		t.Error("Expected error unmarshaling into an unrelated type")
+	}
+}
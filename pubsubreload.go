@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+// pubSubRetryInterval is how long WithPubSubReload waits before subscribing again after the
+// subscription failed.
+const pubSubRetryInterval = time.Second
+
+// WithPubSubReload keeps the memory tiers of a fleet consistent within milliseconds: every
+// write this instance makes to Redis (Set, including write-behind, SyncToRedis and
+// RebuildRedis) is followed by a PUBLISH on RedisConfig.InvalidationChannel, and a
+// background subscriber calls LoadFromRedis when another instance publishes there. Bursts of
+// messages coalesce into one reload; an instance ignores its own messages. Messages
+// published while the subscription is down are lost, so pair it with StartAutoRefresh at a
+// long interval where missed updates matter. Close stops the subscriber.
+// Panics if InvalidationChannel is empty or the store is not a RedisCache.
+func (c *HybridCache[V]) WithPubSubReload() *HybridCache[V] {
+	if c.redis == nil {
+		panic("cache-kit: WithPubSubReload requires a RedisCache store")
+	}
+	if c.redis.config.InvalidationChannel == "" {
+		panic("cache-kit: WithPubSubReload requires RedisConfig.InvalidationChannel")
+	}
+	c.instanceID = rand.Text()
+	loop := startLoop(c.runPubSubReload)
+
+	c.loopsMu.Lock()
+	previous := c.reload
+	c.reload = loop
+	c.loopsMu.Unlock()
+	previous.stop()
+	return c
+}
+
+// runPubSubReload subscribes to the invalidation channel, resubscribing after failures,
+// and reloads memory for every message from another instance.
+func (c *HybridCache[V]) runPubSubReload(ctx context.Context) {
+	due := make(chan struct{}, 1)
+	subscribed := make(chan struct{})
+	go func() {
+		defer close(subscribed)
+		sub := NewChannelSubscriber(c.redis.client, c.redis.config.InvalidationChannel)
+		for {
+			err := sub.Run(ctx, func(e InvalidationEvent) {
+				if e.Event == c.instanceID {
+					return
+				}
+				select {
+				case due <- struct{}{}:
+				default:
+				}
+			})
+			if ctx.Err() == nil {
+				c.reportError(TaskPubSubReload, err)
+			}
+			if sleepCtx(ctx, pubSubRetryInterval) != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			<-subscribed
+			return
+		case <-due:
+			c.reportError(TaskPubSubReload, c.LoadFromRedis())
+		}
+	}
+}
+
+// publishInvalidation tells the other instances that this one wrote Redis, under
+// WithPubSubReload.
+func (c *HybridCache[V]) publishInvalidation() error {
+	if c.instanceID == "" {
+		return nil
+	}
+	ctx, cancel := c.redis.getContext(context.Background())
+	defer cancel()
+	if err := c.redis.client.Publish(ctx, c.redis.config.InvalidationChannel, c.instanceID).Err(); err != nil {
+		return fmt.Errorf("failed to publish invalidation: %w", err)
+	}
+	return nil
+}
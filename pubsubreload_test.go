@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_PubSubReload(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().WithInvalidationChannel("users:changed")
+	a := NewHybridCache[TestUser](userConfig(), client, config).WithPubSubReload()
+	defer a.Close(context.Background())
+	b := NewHybridCache[TestUser](userConfig(), client, config).WithPubSubReload()
+	defer b.Close(context.Background())
+	waitFor(t, func() bool { return mr.PubSubNumSub("users:changed")["users:changed"] == 2 })
+
+	if err := a.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool { return idsOf(b.GetAll()) == "1,2" })
+
+	// a ignores its own message: memory diverging locally is not overwritten by a reload.
+	a.Memory().Set([]TestUser{{ID: "9"}})
+	time.Sleep(20 * time.Millisecond)
+	if got := idsOf(a.GetAll()); got != "9" {
+		t.Errorf("Expected an instance not to reload on its own message, got %s", got)
+	}
+
+	if err := b.SyncToRedis(); err != nil {
+		t.Fatalf("SyncToRedis error: %v", err)
+	}
+	waitFor(t, func() bool { return idsOf(a.GetAll()) == "1,2" })
+}
+
+func TestHybridCache_PubSubReloadRequiresChannel(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic without InvalidationChannel")
+		}
+	}()
+	NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithPubSubReload()
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by RedisCache operations rejected by RedisConfig.RateLimiter.
+// Nothing was sent to Redis; callers can back off or serve from memory.
+var ErrRateLimited = errors.New("cache-kit: redis operation rate limit exceeded")
+
+// RateLimiter decides whether a RedisCache operation may go ahead, see
+// RedisConfig.RateLimiter. Allow must not block and must be safe for concurrent use.
+type RateLimiter interface {
+	Allow() bool
+}
+
+// TokenBucket is a RateLimiter allowing rate operations per second on average, with bursts
+// of up to burst operations. Share one TokenBucket between the caches of a Redis instance to
+// limit them together.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewTokenBucket creates a TokenBucket that starts full. rate must be positive; burst below
+// 1 is treated as 1.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if rate <= 0 {
+		panic("cache-kit: token bucket rate must be positive")
+	}
+	b := &TokenBucket{rate: rate, burst: float64(max(burst, 1)), now: time.Now}
+	b.tokens = b.burst
+	b.last = b.now()
+	return b
+}
+
+// Allow takes a token if one is available and reports whether it did.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// admit checks that the cache can send an operation to Redis: it has a client and, with
+// RedisConfig.RateLimiter, the limiter allows it.
+func (c *RedisCache[V]) admit() error {
+	if c.client == nil {
+		return ErrNilClient
+	}
+	if limiter := c.config.RateLimiter; limiter != nil && !limiter.Allow() {
+		return ErrRateLimited
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	bucket := NewTokenBucket(2, 3)
+	bucket.now = func() time.Time { return now }
+	bucket.last = now
+
+	for i := 0; i < 3; i++ {
+		if !bucket.Allow() {
+			t.Fatalf("Expected the burst to allow operation %d", i+1)
+		}
+	}
+	if bucket.Allow() {
+		t.Error("Expected an empty bucket to reject")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !bucket.Allow() || bucket.Allow() {
+		t.Error("Expected one token after half a second at 2/s")
+	}
+	now = now.Add(time.Hour)
+	allowed := 0
+	for bucket.Allow() {
+		allowed++
+	}
+	if allowed != 3 {
+		t.Errorf("Expected refills to be capped at the burst of 3, got %d", allowed)
+	}
+}
+
+func TestRedisCache_RateLimiter(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithRateLimiter(NewTokenBucket(0.001, 2)).WithMetricsHook(hook))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, err := cache.Get(); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if _, err := cache.Get(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited once the burst is spent, got %v", err)
+	}
+	if last := hook.last(); last.op != OpGet || !errors.Is(last.err, ErrRateLimited) {
+		t.Errorf("Expected the rejection to be observed, got %+v", last)
+	}
+	if err := cache.Ping(context.Background()); err != nil {
+		t.Errorf("Expected Ping not to be limited, got %v", err)
+	}
+}
@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -13,53 +16,113 @@ import (
 // Prevents key space abuse and ensures predictable behavior.
 const maxRedisKeyLen = 512
 
-// validateRedisKeys panics if dataKey or versionKey are invalid: empty, identical, or too long.
+// validateRedisKeys reports whether dataKey or versionKey are invalid: empty, identical, or too long.
 // Use a unique KeyPrefix (or key for NewRedisCacheWithKey) per cache to avoid key collision.
-func validateRedisKeys(dataKey, versionKey string) {
+func validateRedisKeys(dataKey, versionKey string) error {
 	if dataKey == "" {
-		panic("cache-kit: Redis data key must not be empty; use a non-empty KeyPrefix or key")
+		return errors.New("cache-kit: Redis data key must not be empty; use a non-empty KeyPrefix or key")
 	}
 	if versionKey == "" || versionKey == dataKey {
-		panic("cache-kit: Redis version key must not be empty and must differ from data key; set VersionKeySuffix")
+		return errors.New("cache-kit: Redis version key must not be empty and must differ from data key; set VersionKeySuffix")
 	}
-	if len(dataKey) > maxRedisKeyLen || len(versionKey) > maxRedisKeyLen {
-		panic("cache-kit: Redis key length must not exceed 512 bytes")
+	if keysTooLong(dataKey, versionKey) {
+		return errors.New("cache-kit: Redis key length must not exceed 512 bytes; see RedisConfig.HashLongKeys")
 	}
+	return nil
 }
 
 // RedisCache provides a Redis-based cache implementation.
 // It supports versioning for cache invalidation detection.
 type RedisCache[V any] struct {
-	client *redis.Client
-	config *RedisConfig
-	key    string // main data key
+	client  redis.UniversalClient
+	config  *RedisConfig
+	key     string // main data key
+	verKey  string // version key
+	metaKey string // metadata hash key
+	prefix  string // namespace of all keys, for ClearPrefix
+
+	storage storageMode // how the dataset is laid out in Redis
+	itemKey KeyFunc[V]  // primary key of a value in hash storage mode (see WithHashStorage)
+
+	instrumented *instrumentSlot // see Instrument; shared by copies of the cache
 }
 
+// storageMode selects the Redis data structure holding a RedisCache dataset.
+type storageMode int
+
+const (
+	storageBlob      storageMode = iota // a single encoded payload (the default)
+	storageHash                         // one hash field per primary key, see WithHashStorage
+	storageList                         // one list entry per value, see WithListStorage
+	storageJSON                         // a RedisJSON document, see WithJSONStorage
+	storageBlueGreen                    // generations behind a pointer key, see WithBlueGreen
+)
+
 // NewRedisCache creates a new Redis cache with the given client and configuration.
+// The client may be a *redis.Client, *redis.ClusterClient or any other redis.UniversalClient;
+// against a cluster, enable RedisConfig.ClusterHashTag.
 // KeyPrefix and VersionKeySuffix must be non-empty; use a unique prefix per cache to avoid key collision.
-func NewRedisCache[V any](client *redis.Client, config *RedisConfig) *RedisCache[V] {
+// With RedisConfig.KeyBuilder, the key names come from it instead.
+func NewRedisCache[V any](client redis.UniversalClient, config *RedisConfig) *RedisCache[V] {
 	if config == nil {
 		config = DefaultRedisConfig()
 	}
+	keys, err := redisCacheKeys(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return &RedisCache[V]{
+		client:  nilIfTypedNil(client),
+		config:  config,
+		key:     keys.data,
+		verKey:  keys.version,
+		metaKey: keys.metadata,
+		prefix:  keys.prefix,
+
+		instrumented: &instrumentSlot{},
+	}
+}
+
+// cacheKeys are the key names of a RedisCache.
+type cacheKeys struct {
+	data, version, metadata, prefix string
+}
+
+// redisCacheKeys derives the key names of NewRedisCache from config, or reports why
+// config cannot name a cache.
+func redisCacheKeys(config *RedisConfig) (cacheKeys, error) {
+	if builder := config.KeyBuilder; builder != nil {
+		if err := validateKeyBuilder(builder, config.ClusterHashTag); err != nil {
+			return cacheKeys{}, err
+		}
+		dataKey, versionKey, metadataKey, prefix := builder.DataKey(), builder.VersionKey(), builder.MetadataKey(), builder.Prefix()
+		if config.HashLongKeys && keysTooLong(dataKey, versionKey) {
+			dataKey, versionKey, metadataKey, prefix = shortenBuilderKeys(builder)
+		}
+		return cacheKeys{dataKey, versionKey, metadataKey, prefix}, validateRedisKeys(dataKey, versionKey)
+	}
 	if config.KeyPrefix == "" {
-		panic("cache-kit: Redis KeyPrefix must not be empty; use a unique prefix per cache")
+		return cacheKeys{}, errors.New("cache-kit: Redis KeyPrefix must not be empty; use a unique prefix per cache")
 	}
 	if config.VersionKeySuffix == "" {
-		panic("cache-kit: Redis VersionKeySuffix must not be empty")
+		return cacheKeys{}, errors.New("cache-kit: Redis VersionKeySuffix must not be empty")
 	}
-	dataKey := config.KeyPrefix + "data"
+	prefix := config.KeyPrefix
+	dataKey := redisDataKey(prefix+"data", config)
 	versionKey := dataKey + config.VersionKeySuffix
-	validateRedisKeys(dataKey, versionKey)
-	return &RedisCache[V]{
-		client: client,
-		config: config,
-		key:    dataKey,
+	if config.HashLongKeys && keysTooLong(dataKey, versionKey) {
+		prefix = shortenKey(prefix) + ":"
+		dataKey = redisDataKey(prefix+"data", config)
+		versionKey = dataKey + config.VersionKeySuffix
 	}
+	return cacheKeys{dataKey, versionKey, dataKey + metadataKeySuffix, prefix}, validateRedisKeys(dataKey, versionKey)
 }
 
 // NewRedisCacheWithKey creates a new Redis cache with a custom key name.
 // The key must be non-empty; VersionKeySuffix must be non-empty. Use a unique key per cache to avoid key collision.
-func NewRedisCacheWithKey[V any](client *redis.Client, key string, config *RedisConfig) *RedisCache[V] {
+// RedisConfig.KeyBuilder is not used, and the cache has no key prefix: ClearPrefix fails
+// rather than clearing RedisConfig.KeyPrefix, which does not name its keys.
+func NewRedisCacheWithKey[V any](client redis.UniversalClient, key string, config *RedisConfig) *RedisCache[V] {
 	if config == nil {
 		config = DefaultRedisConfig()
 	}
@@ -69,23 +132,101 @@ func NewRedisCacheWithKey[V any](client *redis.Client, key string, config *Redis
 	if config.VersionKeySuffix == "" {
 		panic("cache-kit: Redis VersionKeySuffix must not be empty")
 	}
-	versionKey := key + config.VersionKeySuffix
-	validateRedisKeys(key, versionKey)
+	dataKey := redisDataKey(key, config)
+	versionKey := dataKey + config.VersionKeySuffix
+	if config.HashLongKeys && keysTooLong(dataKey, versionKey) {
+		dataKey = redisDataKey(shortenKey(key), config)
+		versionKey = dataKey + config.VersionKeySuffix
+	}
+	key = dataKey
+	if err := validateRedisKeys(key, versionKey); err != nil {
+		panic(err.Error())
+	}
 	return &RedisCache[V]{
-		client: client,
-		config: config,
-		key:    key,
+		client:  nilIfTypedNil(client),
+		config:  config,
+		key:     key,
+		verKey:  versionKey,
+		metaKey: key + metadataKeySuffix,
+
+		instrumented: &instrumentSlot{},
 	}
 }
 
-// getContext creates a context with timeout.
-func (c *RedisCache[V]) getContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), c.config.OperationTimeout)
+// redisDataKey returns the data key for base, hash-tagged under RedisConfig.ClusterHashTag.
+func redisDataKey(base string, config *RedisConfig) string {
+	if config.ClusterHashTag {
+		return hashTagged(base)
+	}
+	return base
+}
+
+// hashTagged wraps key in a Redis Cluster hash tag, so keys derived from it by appending
+// suffixes hash to the same slot. A key that already contains a non-empty {tag} is kept.
+func hashTagged(key string) string {
+	if hashTag(key) != "" {
+		return key
+	}
+	return "{" + key + "}"
+}
+
+// nilIfTypedNil turns a nil *redis.Client or *redis.ClusterClient wrapped in the interface
+// into a nil interface, so the ErrNilClient checks keep working.
+func nilIfTypedNil(client redis.UniversalClient) redis.UniversalClient {
+	switch c := client.(type) {
+	case *redis.Client:
+		if c == nil {
+			return nil
+		}
+	case *redis.ClusterClient:
+		if c == nil {
+			return nil
+		}
+	}
+	return client
+}
+
+// getContext derives the context for one operation from ctx. OperationTimeout applies only
+// when ctx has no deadline of its own, so a caller's tighter or looser deadline wins; a
+// timeout from WithCallTimeout replaces it.
+func (c *RedisCache[V]) getContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, operationTimeout(ctx, c.config.OperationTimeout))
+}
+
+// callTimeoutKey is the context key of WithCallTimeout.
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a context making the RedisCache operations it is passed to (the
+// *Ctx methods) run with timeout instead of RedisConfig.OperationTimeout, e.g. a longer one
+// for a bulk Set of a large payload than for an interactive Exists check. Unlike a deadline
+// set on ctx, the timeout starts with each operation, so one context can be reused across
+// calls. A deadline on ctx still takes precedence.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// operationTimeout returns the timeout set on ctx with WithCallTimeout, or fallback.
+func operationTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok && timeout > 0 {
+		return timeout
+	}
+	return fallback
+}
+
+// codec returns the configured payload codec, JSONCodec if unset.
+func (c *RedisCache[V]) codec() Codec {
+	if c.config.Codec != nil {
+		return c.config.Codec
+	}
+	return JSONCodec{}
 }
 
 // versionKey returns the version key for this cache.
 func (c *RedisCache[V]) versionKey() string {
-	return c.key + c.config.VersionKeySuffix
+	return c.verKey
 }
 
 // effectiveTTL returns the TTL to use; if the given ttl is <= 0, uses config TTL, or 1 hour as fallback.
@@ -100,60 +241,272 @@ func (c *RedisCache[V]) effectiveTTL(ttl time.Duration) time.Duration {
 }
 
 // Set stores values in Redis and increments the version.
-func (c *RedisCache[V]) Set(values []V) error {
-	if c.client == nil {
-		return fmt.Errorf("redis client is nil")
+// Options adjust a single write, see SetOption.
+func (c *RedisCache[V]) Set(values []V, opts ...SetOption) error {
+	return c.SetCtx(context.Background(), values, opts...)
+}
+
+// SetCtx is like Set but uses ctx for the Redis calls.
+func (c *RedisCache[V]) SetCtx(ctx context.Context, values []V, opts ...SetOption) error {
+	if len(opts) == 0 {
+		return c.write(ctx, values, c.setTTL(values), true)
 	}
+	return c.setWithOptions(ctx, values, opts)
+}
 
-	data, err := json.Marshal(values)
-	if err != nil {
-		return fmt.Errorf("failed to marshal values: %w", err)
+// write encodes values and stores them with the given TTL (see effectiveTTL), bumping the
+// version unless bump is false.
+func (c *RedisCache[V]) write(ctx context.Context, values []V, ttl time.Duration, bump bool) (err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpSet, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
-	ttl := c.effectiveTTL(c.config.TTL)
+	ttl = c.effectiveTTL(ttl)
+	switch c.storage {
+	case storageHash:
+		size, err = c.writeItems(ctx, values, ttl, bump)
+	case storageList:
+		size, err = c.writeList(ctx, values, ttl, bump)
+	case storageJSON:
+		size, err = c.writeJSON(ctx, values, ttl, bump)
+	case storageBlueGreen:
+		_, size, err = c.writeGeneration(ctx, values, ttl, bump, false, 0)
+	default:
+		size, err = c.writeBlob(ctx, values, ttl, bump)
+	}
+	if err != nil {
+		return err
+	}
+	return c.writeDerived(ctx, values, ttl)
+}
+
+// writeDerived updates the keys kept alongside a full dataset write, as configured:
+// the stale copy, the soft expiry marker, the content hash and the metadata, in one round trip.
+func (c *RedisCache[V]) writeDerived(ctx context.Context, values []V, ttl time.Duration) error {
 	pipe := c.client.Pipeline()
-	pipe.Set(ctx, c.key, data, ttl)
-	pipe.Incr(ctx, c.versionKey())
-	pipe.Expire(ctx, c.versionKey(), ttl)
+	if err := c.queueDerived(ctx, pipe, values, ttl); err != nil {
+		return err
+	}
+	if pipe.Len() == 0 {
+		return nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set derived keys: %w", err)
+	}
+	return nil
+}
 
-	_, err = pipe.Exec(ctx)
+// queueDerived queues the commands of writeDerived on pipe.
+func (c *RedisCache[V]) queueDerived(ctx context.Context, pipe redis.Pipeliner, values []V, ttl time.Duration) error {
+	if c.config.StaleTTL > 0 {
+		if err := c.queueStale(ctx, pipe, values); err != nil {
+			return fmt.Errorf("failed to marshal stale copy: %w", err)
+		}
+	}
+	if c.config.SoftTTL > 0 {
+		pipe.Set(ctx, c.softKey(), "1", c.config.SoftTTL)
+	}
+	if c.config.ContentHash {
+		hash, err := c.contentHash(values)
+		if err != nil {
+			return fmt.Errorf("failed to hash values: %w", err)
+		}
+		pipe.Set(ctx, c.contentHashKey(), hash, ttl)
+	}
+	if c.config.PublishMetadata {
+		c.queueMetadata(ctx, pipe, len(values), ttl)
+	}
+	return nil
+}
+
+// writeBlob stores values as a single encoded payload and bumps the version if bump is set.
+// Returns the payload size.
+func (c *RedisCache[V]) writeBlob(ctx context.Context, values []V, ttl time.Duration, bump bool) (int, error) {
+	data, err := c.encode(values)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal values: %w", err)
+	}
+
+	if c.config.VersionedEnvelope {
+		err = writeEnvelopeScript.Run(ctx, c.client, []string{c.key, c.versionKey()},
+			data, ttl.Milliseconds(), bumpFlag(bump)).Err()
+	} else {
+		var pipe redis.Pipeliner
+		if c.config.AtomicWrites {
+			pipe = c.client.TxPipeline()
+		} else {
+			pipe = c.client.Pipeline()
+		}
+		c.queueBlob(ctx, pipe, data, ttl, bump)
+		_, err = pipe.Exec(ctx)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to set cache: %w", err)
+		return len(data), fmt.Errorf("failed to set cache: %w", err)
 	}
+	return len(data), nil
+}
 
-	return nil
+// queueBlob queues storing an encoded payload and bumping the version (if bump is set) on
+// pipe. With VersionedEnvelope the script is sent in full, since a pipeline cannot fall back
+// from EVALSHA on NOSCRIPT.
+func (c *RedisCache[V]) queueBlob(ctx context.Context, pipe redis.Pipeliner, data []byte, ttl time.Duration, bump bool) {
+	if c.config.VersionedEnvelope {
+		writeEnvelopeScript.Eval(ctx, pipe, []string{c.key, c.versionKey()}, data, ttl.Milliseconds(), bumpFlag(bump))
+		return
+	}
+	pipe.Set(ctx, c.key, data, ttl)
+	if bump {
+		pipe.Incr(ctx, c.versionKey())
+	}
+	pipe.Expire(ctx, c.versionKey(), ttl)
+}
+
+// bumpFlag returns the script argument for bump.
+func bumpFlag(bump bool) string {
+	if bump {
+		return "1"
+	}
+	return "0"
 }
 
 // Get retrieves values from Redis.
 // Returns an empty slice if the key doesn't exist.
 // If the stored value exceeds MaxValueBytes (when set in config), returns an error to prevent OOM.
+// With VersionedEnvelope enabled, returns a *VersionMismatchError if the data and version keys disagree.
 func (c *RedisCache[V]) Get() ([]V, error) {
-	if c.client == nil {
-		return nil, fmt.Errorf("redis client is nil")
+	return c.GetCtx(context.Background())
+}
+
+// GetCtx is like Get but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetCtx(ctx context.Context) ([]V, error) {
+	values, found, err := c.load(ctx)
+	if err == nil && !found && c.config.EmptyTTL > 0 {
+		return nil, ErrRemoteEmpty
+	}
+	return values, err
+}
+
+// GetWithVersion retrieves values together with the version they belong to,
+// read in a single MULTI/EXEC transaction so both come from the same point in time.
+// Returns an empty slice and version 0 if the keys don't exist.
+func (c *RedisCache[V]) GetWithVersion() ([]V, int64, error) {
+	return c.GetWithVersionCtx(context.Background())
+}
+
+// GetWithVersionCtx is like GetWithVersion but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetWithVersionCtx(ctx context.Context) ([]V, int64, error) {
+	values, version, found, err := c.fetch(ctx, true)
+	if err == nil && !found && c.config.EmptyTTL > 0 {
+		return nil, version, ErrRemoteEmpty
 	}
+	return values, version, err
+}
+
+// load retrieves values from Redis and reports whether the key exists.
+// A missing key returns an empty slice and found == false.
+func (c *RedisCache[V]) load(ctx context.Context) (values []V, found bool, err error) {
+	values, _, found, err = c.fetch(ctx, false)
+	return values, found, err
+}
+
+// fetch reads the stored values and reports them to the MetricsHook as OpGet.
+// The returned version is 0 when it was not read.
+func (c *RedisCache[V]) fetch(ctx context.Context, withVersion bool) (values []V, version int64, found bool, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpGet, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, 0, false, err
+	}
+	switch c.storage {
+	case storageHash:
+		values, version, found, size, err = c.fetchItems(ctx)
+	case storageList:
+		values, version, found, size, err = c.fetchList(ctx)
+	case storageJSON:
+		values, version, found, size, err = c.fetchJSON(ctx)
+	case storageBlueGreen:
+		values, version, found, size, err = c.fetchGeneration(ctx)
+	default:
+		values, version, found, size, err = c.fetchBlob(ctx, withVersion)
+	}
+	if err == nil && found && c.config.SlidingTTL {
+		c.slide(ctx, values)
+	}
+	return values, version, found, err
+}
 
-	ctx, cancel := c.getContext()
+// fetchBlob reads the data key and, when withVersion is set or envelopes are enabled, the
+// version key. size is the length of the stored payload.
+func (c *RedisCache[V]) fetchBlob(ctx context.Context, withVersion bool) (values []V, version int64, found bool, size int, err error) {
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
-	data, err := c.client.Get(ctx, c.key).Bytes()
+	var data []byte
+	if withVersion || c.config.VersionedEnvelope {
+		pipe := c.client.TxPipeline()
+		dataCmd := pipe.Get(ctx, c.key)
+		versionCmd := pipe.Get(ctx, c.versionKey())
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, 0, false, 0, fmt.Errorf("failed to get cache: %w", err)
+		}
+		version, err = versionCmd.Int64()
+		if err != nil && err != redis.Nil {
+			return nil, 0, false, 0, fmt.Errorf("failed to get version: %w", err)
+		}
+		data, err = dataCmd.Bytes()
+		size = len(data)
+		if err == nil && c.config.VersionedEnvelope {
+			data, err = c.openEnvelope(data, version)
+			if err != nil {
+				return nil, version, true, size, err
+			}
+		}
+		if err == redis.Nil && c.config.VersionedEnvelope && version != 0 {
+			return nil, version, false, 0, &VersionMismatchError{DataVersion: 0, KeyVersion: version}
+		}
+	} else {
+		data, err = c.client.Get(ctx, c.key).Bytes()
+		size = len(data)
+	}
 	if err == redis.Nil {
-		return []V{}, nil
+		return []V{}, version, false, 0, nil
+	}
+	if err != nil {
+		return nil, 0, false, 0, fmt.Errorf("failed to get cache: %w", err)
 	}
+
+	values, err = c.decode(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cache: %w", err)
+		return nil, version, true, size, err
+	}
+	return values, version, true, size, nil
+}
+
+// decode checks the payload size limit and unmarshals the stored values.
+// The empty marker (see RedisConfig.EmptyTTL) decodes to an empty slice.
+func (c *RedisCache[V]) decode(data []byte) ([]V, error) {
+	if bytes.Equal(data, emptyMarker) {
+		return []V{}, nil
 	}
 
 	maxBytes := c.config.MaxValueBytes
 	if maxBytes > 0 && len(data) > maxBytes {
-		return nil, fmt.Errorf("cache value size %d exceeds max allowed %d", len(data), maxBytes)
+		return nil, &ValueTooLargeError{Size: len(data), Max: maxBytes}
 	}
 
 	var values []V
-	if err := json.Unmarshal(data, &values); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal values: %w", err)
+	if err := c.codec().Unmarshal(data, &values); err != nil {
+		return nil, decodeError(fmt.Errorf("failed to unmarshal values: %w", err))
+	}
+	if values == nil {
+		values = []V{} // codecs such as gob decode an empty slice as nil
 	}
 
 	return values, nil
@@ -161,11 +514,19 @@ func (c *RedisCache[V]) Get() ([]V, error) {
 
 // Exists checks if the cache key exists.
 func (c *RedisCache[V]) Exists() (bool, error) {
-	if c.client == nil {
-		return false, fmt.Errorf("redis client is nil")
+	return c.ExistsCtx(context.Background())
+}
+
+// ExistsCtx is like Exists but uses ctx for the Redis calls.
+func (c *RedisCache[V]) ExistsCtx(ctx context.Context) (exists bool, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpExists, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return false, err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
 	count, err := c.client.Exists(ctx, c.key).Result()
@@ -179,14 +540,22 @@ func (c *RedisCache[V]) Exists() (bool, error) {
 // GetVersion returns the current cache version.
 // Returns 0 if the version key doesn't exist.
 func (c *RedisCache[V]) GetVersion() (int64, error) {
-	if c.client == nil {
-		return 0, fmt.Errorf("redis client is nil")
+	return c.GetVersionCtx(context.Background())
+}
+
+// GetVersionCtx is like GetVersion but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetVersionCtx(ctx context.Context) (version int64, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpGetVersion, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
-	version, err := c.client.Get(ctx, c.versionKey()).Int64()
+	version, err = c.client.Get(ctx, c.versionKey()).Int64()
 	if err == redis.Nil {
 		return 0, nil
 	}
@@ -197,61 +566,71 @@ func (c *RedisCache[V]) GetVersion() (int64, error) {
 	return version, nil
 }
 
-// Clear deletes the cache key and the version key.
+// Clear deletes the cache key, the version key and all derived keys (metadata, stale copy,
+// soft expiry marker, persisted indexes).
 // After Clear(), GetVersion() returns 0 (version key is removed).
 func (c *RedisCache[V]) Clear() error {
-	if c.client == nil {
-		return fmt.Errorf("redis client is nil")
+	return c.ClearCtx(context.Background())
+}
+
+// ClearCtx is like Clear but uses ctx for the Redis calls.
+func (c *RedisCache[V]) ClearCtx(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpClear, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
+	if c.storage == storageBlueGreen {
+		if err = c.clearGenerations(ctx); err != nil {
+			return err
+		}
+	}
 	pipe := c.client.Pipeline()
 	pipe.Del(ctx, c.key)
 	pipe.Del(ctx, c.versionKey())
-	_, err := pipe.Exec(ctx)
-	return err
+	pipe.Del(ctx, c.metadataKey())
+	pipe.Del(ctx, c.staleKey())
+	pipe.Del(ctx, c.softKey())
+	pipe.Del(ctx, c.contentHashKey())
+	if _, err = pipe.Exec(ctx); err != nil {
+		return err
+	}
+	return c.deleteIndexes(ctx)
 }
 
 // SetWithTTL stores values with a custom TTL.
 func (c *RedisCache[V]) SetWithTTL(values []V, ttl time.Duration) error {
-	if c.client == nil {
-		return fmt.Errorf("redis client is nil")
-	}
-
-	data, err := json.Marshal(values)
-	if err != nil {
-		return fmt.Errorf("failed to marshal values: %w", err)
-	}
-
-	ctx, cancel := c.getContext()
-	defer cancel()
-
-	effectiveTTL := c.effectiveTTL(ttl)
-	pipe := c.client.Pipeline()
-	pipe.Set(ctx, c.key, data, effectiveTTL)
-	pipe.Incr(ctx, c.versionKey())
-	pipe.Expire(ctx, c.versionKey(), effectiveTTL)
-
-	_, err = pipe.Exec(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to set cache: %w", err)
-	}
+	return c.SetWithTTLCtx(context.Background(), values, ttl)
+}
 
-	return nil
+// SetWithTTLCtx is like SetWithTTL but uses ctx for the Redis calls.
+func (c *RedisCache[V]) SetWithTTLCtx(ctx context.Context, values []V, ttl time.Duration) error {
+	return c.write(ctx, values, ttl, true)
 }
 
 // TTL returns the remaining TTL for the cache key.
 func (c *RedisCache[V]) TTL() (time.Duration, error) {
-	if c.client == nil {
-		return 0, fmt.Errorf("redis client is nil")
+	return c.TTLCtx(context.Background())
+}
+
+// TTLCtx is like TTL but uses ctx for the Redis calls.
+func (c *RedisCache[V]) TTLCtx(ctx context.Context) (ttl time.Duration, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpTTL, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
-	ttl, err := c.client.TTL(ctx, c.key).Result()
+	ttl, err = c.client.TTL(ctx, c.key).Result()
 	if err != nil {
 		return 0, fmt.Errorf("failed to get TTL: %w", err)
 	}
@@ -261,35 +640,129 @@ func (c *RedisCache[V]) TTL() (time.Duration, error) {
 
 // Refresh extends the TTL of the cache without changing the data.
 func (c *RedisCache[V]) Refresh() error {
-	if c.client == nil {
-		return fmt.Errorf("redis client is nil")
+	return c.RefreshCtx(context.Background())
+}
+
+// RefreshCtx is like Refresh but uses ctx for the Redis calls.
+func (c *RedisCache[V]) RefreshCtx(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpRefresh, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
 	}
 
-	ctx, cancel := c.getContext()
+	ctx, cancel := c.getContext(ctx)
 	defer cancel()
 
 	ttl := c.effectiveTTL(c.config.TTL)
 	pipe := c.client.Pipeline()
+	c.queueExpire(ctx, pipe, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// queueExpire queues resetting the TTL of the data key and the keys sharing its TTL
+// (version, metadata, content hash, generations) on pipe.
+func (c *RedisCache[V]) queueExpire(ctx context.Context, pipe redis.Pipeliner, ttl time.Duration) {
+	if c.storage == storageBlueGreen {
+		c.queueExpireGenerations(ctx, pipe, ttl)
+	}
 	pipe.Expire(ctx, c.key, ttl)
 	pipe.Expire(ctx, c.versionKey(), ttl)
+	if c.config.PublishMetadata {
+		pipe.Expire(ctx, c.metadataKey(), ttl)
+	}
+	if c.config.ContentHash {
+		pipe.Expire(ctx, c.contentHashKey(), ttl)
+	}
+}
 
+// slide resets the TTL after a read that found data, under RedisConfig.SlidingTTL.
+// Empty datasets under negative caching keep EmptyTTL. The outcome is reported to the
+// MetricsHook as OpRefresh rather than failing the read.
+func (c *RedisCache[V]) slide(ctx context.Context, values []V) {
+	start := time.Now()
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	c.queueExpire(ctx, pipe, c.effectiveTTL(c.setTTL(values)))
 	_, err := pipe.Exec(ctx)
-	return err
+	c.observe(OpRefresh, start, 0, err)
 }
 
+// EmptyRemotePolicy controls what HybridCache.LoadFromRedis does when the Redis key
+// is missing or expired. A stored empty dataset is not affected: it always replaces memory.
+type EmptyRemotePolicy int
+
+const (
+	// EmptyRemoteClear replaces memory with the empty dataset (the default).
+	EmptyRemoteClear EmptyRemotePolicy = iota
+
+	// EmptyRemoteKeep leaves the memory cache untouched.
+	EmptyRemoteKeep
+
+	// EmptyRemoteError leaves the memory cache untouched and returns ErrRemoteEmpty.
+	EmptyRemoteError
+)
+
+// ErrRemoteEmpty is returned by HybridCache.LoadFromRedis under EmptyRemoteError
+// when the Redis key is missing or expired, by RedisCache.GetStale without a stale copy,
+// and by RedisCache.Get for a missing key when RedisConfig.EmptyTTL is set.
+var ErrRemoteEmpty = errors.New("cache-kit: remote cache key is missing or expired")
+
 // HybridCache combines memory cache with Redis for distributed scenarios.
 // It uses memory cache for fast local access and Redis for persistence/sharing.
+// With* options must be applied before the cache is used concurrently.
 type HybridCache[V any] struct {
 	memory *MemoryCache[V]
-	redis  *RedisCache[V]
+	remote RemoteStore[V]
+	redis  *RedisCache[V] // remote as a RedisCache, nil for other stores
+
+	emptyRemotePolicy EmptyRemotePolicy
+	merge             MergeFunc[V]    // see WithMergeOnLoad
+	loader            LoaderFunc[V]   // see WithLoader
+	persistIndexes    bool            // see WithPersistedIndexes
+	syncOnClose       bool            // see WithSyncOnClose
+	errorHandler      ErrorHandler    // see WithErrorHandler
+	diffSync          bool            // see WithDiffSync
+	atomicSet         bool            // see WithAtomicSet
+	writePolicy       WritePolicy     // see WithWritePolicy
+	writeBehind       *writeBehind[V] // see WithWriteBehind
+	retry             *writeBehind[V] // see WithSyncRetry
+	coldStore         ColdStore       // see WithColdStore
+	cold              *writeBehind[V] // copies written datasets to coldStore
+
+	syncMu   sync.Mutex
+	synced   *syncedItems // diff base, see WithDiffSync
+	atomicMu sync.Mutex   // serializes Sets under WithAtomicSet
+
+	loopsMu sync.Mutex
+	refresh *backgroundLoop // see StartAutoRefresh and StartLeaderRefresh
+	reload  *backgroundLoop // see WithPubSubReload
+
+	instanceID   string                // identifies this instance in invalidation messages
+	swr          *staleWhileRevalidate // see WithStaleWhileRevalidate
+	ahead        *refreshAhead         // see WithRefreshAhead
+	lazy         *lazyLoad             // see WithLazyLoad
+	memTTL       *memoryTTL            // see WithMemoryTTL
+	version      atomic.Int64          // Redis version memory holds, see LoadedVersion
+	leading      atomic.Bool           // see IsLeader
+	stats        hybridCounters        // see Stats
+	instrumented instrumentSlot        // see Instrument
 }
 
 // NewHybridCache creates a new hybrid cache.
-func NewHybridCache[V any](memoryConfig *Config[V], redisClient *redis.Client, redisConfig *RedisConfig) *HybridCache[V] {
-	return &HybridCache[V]{
-		memory: NewMultiIndexCache(memoryConfig),
-		redis:  NewRedisCache[V](redisClient, redisConfig),
-	}
+func NewHybridCache[V any](memoryConfig *Config[V], redisClient redis.UniversalClient, redisConfig *RedisConfig) *HybridCache[V] {
+	return NewHybridCacheWithStore(memoryConfig, NewRedisCache[V](redisClient, redisConfig))
+}
+
+// WithEmptyRemotePolicy sets how LoadFromRedis handles a missing or expired Redis key.
+// The default, EmptyRemoteClear, wipes the memory cache.
+func (c *HybridCache[V]) WithEmptyRemotePolicy(policy EmptyRemotePolicy) *HybridCache[V] {
+	c.emptyRemotePolicy = policy
+	return c
 }
 
 // AddIndex registers a new index on the memory cache.
@@ -301,35 +774,202 @@ func (c *HybridCache[V]) AddIndex(name string, keyFunc KeyFunc[V]) {
 // Memory is updated first, then Redis. If Redis.Set fails, memory already holds the new data
 // while Redis may still have the old data; the error is returned and the caller should
 // retry or call LoadFromRedis to reconcile (e.g. clear memory or reload from Redis).
+// With WithWriteBehind, the Redis write is queued and Set returns nil right away.
+// WithWritePolicy can restrict Set to one tier, and WithAtomicSet writes Redis first and
+// commits memory only on success.
 func (c *HybridCache[V]) Set(values []V) error {
+	switch c.writePolicy {
+	case WriteMemoryOnly:
+		c.memory.Set(values)
+		return nil
+	case WriteRedisOnly:
+		return c.writeRedisRetrying(values)
+	case WriteThrough:
+		if c.atomicSet {
+			return c.setAtomic(values)
+		}
+	}
 	c.memory.Set(values)
-	return c.redis.Set(values)
+	if c.writeBehind != nil && c.writeBehind.enqueue(values) {
+		return nil
+	}
+	return c.writeRedisRetrying(values)
+}
+
+// writeRedis stores values in Redis, with the persisted indexes if enabled, and tells the
+// other instances under WithPubSubReload.
+func (c *HybridCache[V]) writeRedis(values []V) error {
+	changed, err := c.writeDataset(values)
+	if err != nil || !changed {
+		return err
+	}
+	return c.afterRedisWrite(values)
+}
+
+// writeDataset stores values in Redis, as a diff under WithDiffSync, and reports whether
+// Redis changed.
+func (c *HybridCache[V]) writeDataset(values []V) (changed bool, err error) {
+	if c.diffSyncing() {
+		return c.writeItemsDiff(values)
+	}
+	return true, c.remote.SetCtx(context.Background(), values)
+}
+
+// afterRedisWrite follows a write of the dataset values to Redis: it persists the indexes,
+// records the version and publishes the invalidation, as enabled.
+func (c *HybridCache[V]) afterRedisWrite(values []V) error {
+	c.stats.lastSync.Store(time.Now().UnixNano())
+	if c.cold != nil {
+		c.cold.enqueue(values)
+	}
+	if err := c.syncIndexes(values); err != nil {
+		return err
+	}
+	c.recordWrittenVersion()
+	return c.publishInvalidation()
 }
 
 // GetByIndex retrieves a value from memory cache by index.
 func (c *HybridCache[V]) GetByIndex(indexName string, key string) (V, bool) {
-	return c.memory.GetByIndex(indexName, key)
+	return c.lookup(func() (V, bool) { return c.memory.GetByIndex(indexName, key) })
+}
+
+// Get retrieves a value from memory cache by its primary key.
+func (c *HybridCache[V]) Get(pk string) (V, bool) {
+	return c.lookup(func() (V, bool) { return c.memory.Get(pk) })
+}
+
+// lookup runs get against memory, loading Redis on a miss under WithLazyLoad, and counts
+// the hit or miss.
+func (c *HybridCache[V]) lookup(get func() (V, bool)) (V, bool) {
+	c.validateMemory()
+	c.revalidate()
+	c.refreshAheadIfDue()
+	v, ok := get()
+	if !ok && c.loadOnMiss() {
+		v, ok = get()
+	}
+	if ok {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	return v, ok
 }
 
 // GetAll returns all values from memory cache.
 func (c *HybridCache[V]) GetAll() []V {
+	c.validateMemory()
+	c.revalidate()
+	c.refreshAheadIfDue()
 	return c.memory.GetAll()
 }
 
 // LoadFromRedis loads data from Redis into memory cache.
+// When the Redis key is missing or expired, the configured EmptyRemotePolicy applies.
+// With WithMergeOnLoad, the Redis dataset is merged into memory instead of replacing it.
+// With WithColdStore or WithLoader, a missing key is filled from the cold store or the
+// source instead (see RefreshFromSource).
 func (c *HybridCache[V]) LoadFromRedis() error {
-	values, err := c.redis.Get()
+	_, err := c.loadFromRedis(context.Background(), true)
+	return err
+}
+
+// loadFromRedis implements LoadFromRedis and reports whether Redis held a dataset. Without
+// fill, a missing dataset leaves memory untouched: neither the cold store, the loader nor
+// the EmptyRemotePolicy applies.
+func (c *HybridCache[V]) loadFromRedis(ctx context.Context, fill bool) (found bool, err error) {
+	start := time.Now()
+	defer func() {
+		c.stats.recordLoad(start, err)
+		c.observeLoad(start, err)
+	}()
+
+	values, version, found, err := c.fetchRemote(ctx)
 	if err != nil {
-		return err
+		return false, err
+	}
+	if !found && !fill {
+		return false, nil
+	}
+	if !found {
+		cold, ok, err := c.loadCold(ctx)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			c.memory.Set(cold)
+			return false, c.writeRedis(cold)
+		}
+	}
+	if !found && c.loader != nil {
+		return false, c.RefreshFromSource(ctx)
+	}
+	c.recordSynced(values, version)
+	if !found {
+		switch c.emptyRemotePolicy {
+		case EmptyRemoteKeep:
+			return false, nil
+		case EmptyRemoteError:
+			return false, ErrRemoteEmpty
+		}
+	}
+	if c.merge != nil {
+		values = c.mergeLocal(values)
 	}
 	c.memory.Set(values)
-	return nil
+	c.recordVersion(version)
+	return found, nil
 }
 
 // SyncToRedis saves memory cache data to Redis.
 func (c *HybridCache[V]) SyncToRedis() error {
+	return c.writeRedisRetrying(c.memory.GetAll())
+}
+
+// ErrMemoryEmpty is returned by HybridCache.RebuildRedis when the memory cache holds no data,
+// so an unhealthy or cold node cannot overwrite Redis with an empty dataset.
+var ErrMemoryEmpty = errors.New("cache-kit: memory cache is empty; refusing to rebuild Redis")
+
+// RebuildRedis rewrites Redis from this node's memory cache and returns the new version.
+// It is meant for operators recovering from Redis data loss without waiting for the next
+// upstream refresh; other nodes pick up the bumped version on their next LoadFromRedis.
+// The whole dataset is written and the version bumped even under WithDiffSync, and a
+// failed write is returned rather than queued by WithSyncRetry. Returns ErrMemoryEmpty
+// when memory is empty, and an error if the Redis version did not increase.
+func (c *HybridCache[V]) RebuildRedis() (int64, error) {
+	ctx := context.Background()
 	values := c.memory.GetAll()
-	return c.redis.Set(values)
+	if len(values) == 0 {
+		return 0, ErrMemoryEmpty
+	}
+	before, err := c.remote.GetVersionCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	write := func() error {
+		if err := c.remote.SetCtx(ctx, values); err != nil {
+			return err
+		}
+		return c.afterRedisWrite(values)
+	}
+	if c.retry != nil {
+		// Holding the retry lock drops a pending retry of an older dataset.
+		err = c.retry.discard(write)
+	} else {
+		err = write()
+	}
+	if err != nil {
+		return 0, err
+	}
+	version, err := c.remote.GetVersionCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version <= before {
+		return 0, fmt.Errorf("cache-kit: rebuild did not bump the Redis version (%d before, %d after)", before, version)
+	}
+	return version, nil
 }
 
 // Memory returns the underlying memory cache for direct access.
@@ -337,7 +977,8 @@ func (c *HybridCache[V]) Memory() *MemoryCache[V] {
 	return c.memory
 }
 
-// Redis returns the underlying Redis cache for direct access.
+// Redis returns the underlying Redis cache for direct access, or nil for a cache created
+// with another store by NewHybridCacheWithStore.
 func (c *HybridCache[V]) Redis() *RedisCache[V] {
 	return c.redis
 }
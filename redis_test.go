@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -289,6 +291,14 @@ func TestHybridCache_BasicOperations(t *testing.T) {
 		t.Errorf("Expected ID 1, got %s", user.ID)
 	}
 
+	// Test Get by primary key
+	if user, ok := cache.Get("2"); !ok || user.Email != "user2@example.com" {
+		t.Errorf("Expected Get to find user 2, got %+v, %v", user, ok)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Expected Get to miss an unknown primary key")
+	}
+
 	// Test GetAll
 	all := cache.GetAll()
 	if len(all) != 2 {
@@ -670,3 +680,342 @@ func TestRedisCache_EmptyVersionKeySuffixPanics(t *testing.T) {
 	}()
 	NewRedisCache[TestUser](client, config)
 }
+
+func TestHybridCache_EmptyRemotePolicy(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	memConfig := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	users := []TestUser{{ID: "1"}, {ID: "2"}}
+
+	tests := []struct {
+		name    string
+		policy  EmptyRemotePolicy
+		wantLen int
+		wantErr error
+	}{
+		{name: "clear", policy: EmptyRemoteClear, wantLen: 0},
+		{name: "keep", policy: EmptyRemoteKeep, wantLen: 2},
+		{name: "error", policy: EmptyRemoteError, wantLen: 2, wantErr: ErrRemoteEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redisConfig := DefaultRedisConfig().WithKeyPrefix("empty-policy:" + tt.name + ":")
+			cache := NewHybridCache[TestUser](memConfig, client, redisConfig).
+				WithEmptyRemotePolicy(tt.policy)
+			cache.Memory().Set(users)
+
+			err := cache.LoadFromRedis()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if cache.Memory().Len() != tt.wantLen {
+				t.Errorf("Expected %d items in memory, got %d", tt.wantLen, cache.Memory().Len())
+			}
+		})
+	}
+}
+
+func TestHybridCache_EmptyRemotePolicyStoredEmptyDataset(t *testing.T) {
+	_, client := setupMiniRedis(t)
+
+	memConfig := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewHybridCache[TestUser](memConfig, client, DefaultRedisConfig()).
+		WithEmptyRemotePolicy(EmptyRemoteKeep)
+	cache.Memory().Set([]TestUser{{ID: "1"}})
+
+	// A legitimately empty dataset in Redis still replaces memory
+	if err := cache.Redis().Set([]TestUser{}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if cache.Memory().Len() != 0 {
+		t.Errorf("Expected stored empty dataset to clear memory, got %d items", cache.Memory().Len())
+	}
+}
+
+func TestHybridCache_RebuildRedis(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+
+	memConfig := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID })
+	cache := NewHybridCache[TestUser](memConfig, client, DefaultRedisConfig())
+
+	if _, err := cache.RebuildRedis(); !errors.Is(err, ErrMemoryEmpty) {
+		t.Fatalf("Expected ErrMemoryEmpty, got %v", err)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.FlushAll() // simulate Redis data loss
+
+	version, err := cache.RebuildRedis()
+	if err != nil {
+		t.Fatalf("RebuildRedis error: %v", err)
+	}
+	if version != 1 {
+		t.Errorf("Expected version 1 after rebuild, got %d", version)
+	}
+	values, err := cache.Redis().Get()
+	if err != nil || len(values) != 1 || values[0].ID != "1" {
+		t.Errorf("Expected Redis rebuilt from memory, got %v (err %v)", values, err)
+	}
+}
+
+func TestHybridCache_RebuildRedisForcesWrite(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithDiffSync().
+		WithSyncRetry(SyncRetryConfig{Backoff: time.Millisecond})
+	cache.Redis().WithHashStorage(func(u TestUser) string { return u.ID })
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	// An unchanged dataset is still written in full and bumps the version.
+	if version, err := cache.RebuildRedis(); err != nil || version != 2 {
+		t.Errorf("Expected version 2 after rebuild, got %d, %v", version, err)
+	}
+
+	// A failed rebuild is reported, not retried in the background.
+	mr.SetError("boom")
+	if _, err := cache.RebuildRedis(); err == nil {
+		t.Error("Expected the rebuild error")
+	}
+	mr.SetError("")
+	time.Sleep(20 * time.Millisecond)
+	if version, _ := cache.Redis().GetVersion(); version != 2 {
+		t.Errorf("Expected no retried rebuild, got version %d", version)
+	}
+}
+
+func TestRedisCache_CtxVariants(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	ctx := context.Background()
+
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if err := cache.SetWithTTLCtx(ctx, []TestUser{{ID: "1"}, {ID: "2"}}, time.Minute); err != nil {
+		t.Fatalf("SetWithTTLCtx error: %v", err)
+	}
+	values, err := cache.GetCtx(ctx)
+	if err != nil || len(values) != 2 {
+		t.Errorf("GetCtx: expected 2 values, got %v (err %v)", values, err)
+	}
+	if _, version, err := cache.GetWithVersionCtx(ctx); err != nil || version != 2 {
+		t.Errorf("GetWithVersionCtx: expected version 2, got %d (err %v)", version, err)
+	}
+	if exists, err := cache.ExistsCtx(ctx); err != nil || !exists {
+		t.Errorf("ExistsCtx: expected true, got %v (err %v)", exists, err)
+	}
+	if version, err := cache.GetVersionCtx(ctx); err != nil || version != 2 {
+		t.Errorf("GetVersionCtx: expected 2, got %d (err %v)", version, err)
+	}
+	if err := cache.RefreshCtx(ctx); err != nil {
+		t.Errorf("RefreshCtx error: %v", err)
+	}
+	if ttl, err := cache.TTLCtx(ctx); err != nil || ttl != time.Hour {
+		t.Errorf("TTLCtx: expected 1h after refresh, got %v (err %v)", ttl, err)
+	}
+	if err := cache.ClearCtx(ctx); err != nil {
+		t.Errorf("ClearCtx error: %v", err)
+	}
+	if exists, _ := cache.ExistsCtx(ctx); exists {
+		t.Error("Expected key removed by ClearCtx")
+	}
+}
+
+func TestRedisCache_CtxCanceled(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "1"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from SetCtx, got %v", err)
+	}
+	if _, err := cache.GetCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from GetCtx, got %v", err)
+	}
+}
+
+func TestRedisCache_CallerDeadlineOverridesOperationTimeout(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithOperationTimeout(time.Nanosecond))
+
+	// The configured timeout would expire immediately; the caller's deadline takes precedence.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "1"}}); err != nil {
+		t.Errorf("Expected caller deadline to be used, got %v", err)
+	}
+}
+
+func TestRedisCache_CallTimeout(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithOperationTimeout(time.Nanosecond))
+
+	// WithCallTimeout replaces the configured timeout for each operation it is passed to.
+	ctx := WithCallTimeout(context.Background(), 5*time.Second)
+	if err := cache.SetCtx(ctx, []TestUser{{ID: "1"}}); err != nil {
+		t.Errorf("Expected the call timeout to be used, got %v", err)
+	}
+	if _, err := cache.ExistsCtx(ctx); err != nil {
+		t.Errorf("Expected the call timeout to be reused, got %v", err)
+	}
+	if _, err := cache.ExistsCtx(context.Background()); err == nil {
+		t.Error("Expected the configured timeout to apply without a call timeout")
+	}
+
+	interactive := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if _, err := interactive.ExistsCtx(WithCallTimeout(context.Background(), time.Nanosecond)); err == nil {
+		t.Error("Expected a short call timeout to expire")
+	}
+}
+
+func TestHashTagged(t *testing.T) {
+	tests := map[string]string{
+		"cache:data":      "{cache:data}",
+		"{users}:data":    "{users}:data",
+		"app:{users}":     "app:{users}",
+		"odd{}key":        "{odd{}key}",
+		"unterminated{ab": "{unterminated{ab}",
+	}
+	for in, want := range tests {
+		if got := hashTagged(in); got != want {
+			t.Errorf("hashTagged(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRedisCache_ClusterHashTag(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().WithClusterHashTag(true).WithPublishMetadata(true).WithVersionedEnvelope(true)
+	cache := NewRedisCache[TestUser](client, config)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	for _, key := range []string{"{cache:data}", "{cache:data}:version", "{cache:data}:meta"} {
+		if !mr.Exists(key) {
+			t.Errorf("Expected co-located key %q", key)
+		}
+	}
+	if values, version, err := cache.GetWithVersion(); err != nil || len(values) != 1 || version != 1 {
+		t.Errorf("GetWithVersion: got %v, %d, %v", values, version, err)
+	}
+
+	custom := NewRedisCacheWithKey[TestUser](client, "whitelist", DefaultRedisConfig().WithClusterHashTag(true))
+	if err := custom.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if !mr.Exists("{whitelist}") || !mr.Exists("{whitelist}:version") {
+		t.Error("Expected hash-tagged custom key")
+	}
+}
+
+func TestRedisCache_ClusterClient(t *testing.T) {
+	mr, _ := setupMiniRedis(t)
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithClusterHashTag(true))
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, version, err := cache.GetWithVersion(); err != nil || len(values) != 1 || version != 1 {
+		t.Errorf("GetWithVersion: got %v, %d, %v", values, version, err)
+	}
+}
+
+func TestRedisCache_TypedNilClient(t *testing.T) {
+	var client *redis.Client
+	cache := NewRedisCache[TestUser](client, nil)
+	if err := cache.Set([]TestUser{{ID: "1"}}); err == nil || !strings.Contains(err.Error(), "nil") {
+		t.Errorf("Expected nil client error, got %v", err)
+	}
+}
+
+// countingCodec wraps JSONCodec and counts calls, to verify RedisConfig.Codec is used.
+type countingCodec struct {
+	JSONCodec
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return c.JSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return c.JSONCodec.Unmarshal(data, v)
+}
+
+func TestRedisCache_WithCodec(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	codec := &countingCodec{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithCodec(codec))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, err := cache.Get()
+	if err != nil || len(values) != 1 {
+		t.Fatalf("Get: got %v (err %v)", values, err)
+	}
+	if codec.marshals != 1 || codec.unmarshals != 1 {
+		t.Errorf("Expected configured codec used once each way, got %d/%d", codec.marshals, codec.unmarshals)
+	}
+}
+
+// commandRecorder is a go-redis hook recording the command names of each pipeline.
+type commandRecorder struct{ pipelines [][]string }
+
+func (h *commandRecorder) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *commandRecorder) ProcessHook(next redis.ProcessHook) redis.ProcessHook { return next }
+
+func (h *commandRecorder) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = cmd.Name()
+		}
+		h.pipelines = append(h.pipelines, names)
+		return next(ctx, cmds)
+	}
+}
+
+func TestRedisCache_AtomicWrites(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	client.Ping(context.Background()) // connection setup is pipelined too
+	recorder := &commandRecorder{}
+	client.AddHook(recorder)
+
+	plain := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("plain:"))
+	atomic := NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("atomic:").WithAtomicWrites(true))
+
+	if err := plain.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if got := strings.Join(recorder.pipelines[len(recorder.pipelines)-1], ","); got != "set,incr,expire" {
+		t.Errorf("Expected a plain pipeline, got %s", got)
+	}
+	if err := atomic.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if got := strings.Join(recorder.pipelines[len(recorder.pipelines)-1], ","); got != "multi,set,incr,expire,exec" {
+		t.Errorf("Expected a MULTI/EXEC transaction, got %s", got)
+	}
+	values, version, err := atomic.GetWithVersion()
+	if err != nil || idsOf(values) != "1" || version != 1 {
+		t.Errorf("Expected 1 at version 1, got %s at %d (%v)", idsOf(values), version, err)
+	}
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshPolicy decides when a cache should refresh next.
+// Wait blocks until a refresh is due and returns nil, or returns ctx.Err() once ctx is done.
+// Policies may keep state between calls and are not safe for concurrent use by several loops.
+type RefreshPolicy interface {
+	Wait(ctx context.Context) error
+}
+
+// RefreshPolicyFunc adapts a function to RefreshPolicy.
+type RefreshPolicyFunc func(ctx context.Context) error
+
+// Wait calls f(ctx).
+func (f RefreshPolicyFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// FixedInterval returns a policy that refreshes every interval.
+func FixedInterval(interval time.Duration) RefreshPolicy {
+	return RefreshPolicyFunc(func(ctx context.Context) error {
+		return sleepCtx(ctx, interval)
+	})
+}
+
+// TTLProportional returns a policy that refreshes once fraction (0 < fraction <= 1) of the
+// remaining TTL reported by ttl has elapsed, e.g. 0.8 with RedisCache.TTL refreshes at 80%
+// of the key's lifetime. Waits are clamped to minWait, which is also used when ttl fails
+// or reports no expiry.
+func TTLProportional(ttl func() (time.Duration, error), fraction float64, minWait time.Duration) RefreshPolicy {
+	if fraction <= 0 || fraction > 1 {
+		fraction = 1
+	}
+	return RefreshPolicyFunc(func(ctx context.Context) error {
+		remaining, err := ttl()
+		if err != nil || remaining <= 0 {
+			return sleepCtx(ctx, minWait)
+		}
+		return sleepCtx(ctx, max(time.Duration(float64(remaining)*fraction), minWait))
+	})
+}
+
+// VersionPollPolicy refreshes when the version reported by its version function changes,
+// checking every interval. The first Wait returns immediately so the cache is loaded once.
+type VersionPollPolicy struct {
+	interval time.Duration
+	version  func() (int64, error)
+	last     int64
+	started  bool
+}
+
+// VersionPoll returns a policy that polls version (e.g. RedisCache.GetVersion) every interval.
+// Errors from version are treated as "unchanged" and polling continues.
+func VersionPoll(interval time.Duration, version func() (int64, error)) *VersionPollPolicy {
+	return &VersionPollPolicy{interval: interval, version: version}
+}
+
+// Wait implements RefreshPolicy.
+func (p *VersionPollPolicy) Wait(ctx context.Context) error {
+	if !p.started {
+		p.started = true
+		if v, err := p.version(); err == nil {
+			p.last = v
+		}
+		return ctx.Err()
+	}
+	for {
+		if err := sleepCtx(ctx, p.interval); err != nil {
+			return err
+		}
+		v, err := p.version()
+		if err == nil && v != p.last {
+			p.last = v
+			return nil
+		}
+	}
+}
+
+// EventDriven returns a policy that refreshes whenever a value arrives on events
+// (e.g. a pub/sub invalidation). A closed channel stops the loop with context.Canceled
+// unless ctx ended first.
+func EventDriven[T any](events <-chan T) RefreshPolicy {
+	return RefreshPolicyFunc(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-events:
+			if !ok {
+				return context.Canceled
+			}
+			return nil
+		}
+	})
+}
+
+// RunRefreshLoop calls refresh each time policy says a refresh is due, until ctx is done or
+// the policy gives up, and returns the policy's error. Refresh errors are passed to onError
+// (if non-nil) and do not stop the loop.
+func RunRefreshLoop(ctx context.Context, policy RefreshPolicy, refresh func(ctx context.Context) error, onError func(error)) error {
+	for {
+		if err := policy.Wait(ctx); err != nil {
+			return err
+		}
+		if err := refresh(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
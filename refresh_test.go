@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFixedInterval(t *testing.T) {
+	policy := FixedInterval(5 * time.Millisecond)
+	start := time.Now()
+	if err := policy.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if time.Since(start) < 5*time.Millisecond {
+		t.Error("Expected Wait to block for the interval")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := FixedInterval(time.Hour).Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTTLProportional(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// 10% of 20ms = 2ms, well within the deadline.
+	policy := TTLProportional(func() (time.Duration, error) { return 20 * time.Millisecond, nil }, 0.1, time.Millisecond)
+	if err := policy.Wait(ctx); err != nil {
+		t.Errorf("Expected refresh before deadline, got %v", err)
+	}
+
+	// Failing TTL lookups fall back to minWait.
+	policy = TTLProportional(func() (time.Duration, error) { return 0, errors.New("down") }, 0.5, time.Hour)
+	if err := policy.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected minWait fallback to outlast the deadline, got %v", err)
+	}
+}
+
+func TestVersionPoll(t *testing.T) {
+	version := int64(1)
+	policy := VersionPoll(time.Millisecond, func() (int64, error) { return version, nil })
+	ctx := context.Background()
+
+	if err := policy.Wait(ctx); err != nil {
+		t.Fatalf("Expected immediate first refresh, got %v", err)
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := policy.Wait(shortCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected no refresh while version is unchanged, got %v", err)
+	}
+
+	version = 2
+	if err := policy.Wait(ctx); err != nil {
+		t.Errorf("Expected refresh after version change, got %v", err)
+	}
+}
+
+func TestEventDriven(t *testing.T) {
+	events := make(chan struct{}, 1)
+	policy := EventDriven(events)
+
+	events <- struct{}{}
+	if err := policy.Wait(context.Background()); err != nil {
+		t.Errorf("Expected refresh on event, got %v", err)
+	}
+
+	close(events)
+	if err := policy.Wait(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled on closed channel, got %v", err)
+	}
+}
+
+func TestRunRefreshLoop(t *testing.T) {
+	events := make(chan int, 3)
+	events <- 1
+	events <- 2
+	events <- 3
+	close(events)
+
+	refreshes := 0
+	var errs []error
+	err := RunRefreshLoop(context.Background(), EventDriven(events), func(ctx context.Context) error {
+		refreshes++
+		if refreshes == 2 {
+			return errors.New("upstream down")
+		}
+		return nil
+	}, func(err error) { errs = append(errs, err) })
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected loop to stop with context.Canceled, got %v", err)
+	}
+	if refreshes != 3 {
+		t.Errorf("Expected 3 refreshes, got %d", refreshes)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 refresh error reported, got %d", len(errs))
+	}
+}
+
+func TestRunRefreshLoop_HybridCache(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	config := DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID })
+
+	writer := NewHybridCache[TestUser](config, client, DefaultRedisConfig())
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	reader := NewHybridCache[TestUser](DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }), client, DefaultRedisConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	policy := VersionPoll(time.Millisecond, reader.Redis().GetVersion)
+	_ = RunRefreshLoop(ctx, policy, func(context.Context) error {
+		defer cancel()
+		return reader.LoadFromRedis()
+	}, nil)
+
+	if reader.Memory().Len() != 1 {
+		t.Errorf("Expected reader loaded by refresh loop, got %d items", reader.Memory().Len())
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// refreshAhead is the state of HybridCache.WithRefreshAhead.
+type refreshAhead struct {
+	threshold  time.Duration
+	lastCheck  atomic.Int64 // unix nanoseconds of the last TTL check
+	refreshing atomic.Bool  // a background check or refresh is running
+}
+
+// WithRefreshAhead keeps the Redis dataset from expiring under steady traffic: reads
+// (GetAll, GetByIndex) check the Redis TTL in the background, at most once per threshold/4,
+// and once it drops below threshold the instance taking the rebuild lock (see
+// RedisCache.AcquireRebuildLock) re-runs the loader set by WithLoader, or without one
+// extends the TTL with RedisCache.Refresh. With a loader, a missing key is filled the same
+// way. Reads never wait for the refresh. threshold must be shorter than RedisConfig.TTL.
+func (c *HybridCache[V]) WithRefreshAhead(threshold time.Duration) *HybridCache[V] {
+	c.ahead = &refreshAhead{threshold: threshold}
+	return c
+}
+
+// refreshAheadIfDue starts a background TTL check if one is due.
+func (c *HybridCache[V]) refreshAheadIfDue() {
+	a := c.ahead
+	if a == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	if now-a.lastCheck.Load() < int64(a.threshold/4) || !a.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	a.lastCheck.Store(now)
+	go func() {
+		defer a.refreshing.Store(false)
+		c.reportError(TaskRefreshAhead, c.refreshAhead(context.Background()))
+	}()
+}
+
+// refreshAhead refreshes the Redis dataset if its TTL dropped below the threshold.
+func (c *HybridCache[V]) refreshAhead(ctx context.Context) error {
+	if c.redis == nil {
+		return ErrRedisRequired
+	}
+	ttl, err := c.redis.TTLCtx(ctx)
+	if err != nil {
+		return err
+	}
+	// TTL reports -2 for a missing key and -1 for a key without expiry.
+	switch {
+	case ttl == -2:
+		if c.loader == nil {
+			return nil
+		}
+	case ttl < 0 || ttl >= c.ahead.threshold:
+		return nil
+	}
+
+	lock, err := c.redis.AcquireRebuildLock(ctx, c.ahead.threshold)
+	if errors.Is(err, ErrLockHeld) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release(context.Background()) }()
+
+	if c.loader != nil {
+		return c.RefreshFromSource(ctx)
+	}
+	return c.redis.RefreshCtx(ctx)
+}
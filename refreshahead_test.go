@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_RefreshAheadLoader(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	var loads atomic.Int32
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithTTL(time.Hour)).
+		WithLoader(func(context.Context) ([]TestUser, error) {
+			loads.Add(1)
+			return []TestUser{{ID: "1"}}, nil
+		}).
+		WithRefreshAhead(10 * time.Minute)
+
+	if err := cache.RefreshFromSource(context.Background()); err != nil {
+		t.Fatalf("RefreshFromSource error: %v", err)
+	}
+	// Well before the threshold: no refresh.
+	if err := cache.refreshAhead(context.Background()); err != nil {
+		t.Fatalf("refreshAhead error: %v", err)
+	}
+	if loads.Load() != 1 {
+		t.Errorf("Expected no refresh above the threshold, got %d loads", loads.Load())
+	}
+
+	mr.FastForward(55 * time.Minute)
+	cache.GetAll()
+	waitFor(t, func() bool { return loads.Load() == 2 })
+	waitFor(t, func() bool { return mr.TTL(cache.Redis().key) > 55*time.Minute })
+}
+
+func TestHybridCache_RefreshAheadExtendsTTL(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithTTL(time.Hour)).
+		WithRefreshAhead(10 * time.Minute)
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	mr.FastForward(55 * time.Minute)
+	if err := cache.refreshAhead(context.Background()); err != nil {
+		t.Fatalf("refreshAhead error: %v", err)
+	}
+	if ttl := mr.TTL(cache.Redis().key); ttl != time.Hour {
+		t.Errorf("Expected the TTL to be extended, got %v", ttl)
+	}
+}
+
+func TestHybridCache_RefreshAheadSkipsWhileLocked(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	ctx := context.Background()
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithTTL(time.Hour)).
+		WithRefreshAhead(10 * time.Minute)
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if _, err := cache.Redis().AcquireRebuildLock(ctx, time.Hour); err != nil {
+		t.Fatalf("AcquireRebuildLock error: %v", err)
+	}
+
+	mr.FastForward(55 * time.Minute)
+	if err := cache.refreshAhead(ctx); err != nil {
+		t.Fatalf("refreshAhead error: %v", err)
+	}
+	if ttl := mr.TTL(cache.Redis().key); ttl != 5*time.Minute {
+		t.Errorf("Expected another instance's lock to skip the refresh, got TTL %v", ttl)
+	}
+}
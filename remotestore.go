@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrRedisRequired is returned by HybridCache features built on Redis commands, such as
+// locks, when the cache was created with another RemoteStore.
+var ErrRedisRequired = errors.New("cache-kit: this HybridCache feature requires a RedisCache store")
+
+var _ RemoteStore[struct{}] = (*RedisCache[struct{}])(nil)
+
+// NewHybridCacheWithStore creates a hybrid cache whose shared tier is store instead of a
+// RedisCache, e.g. a database table or another key-value service. Set, the reads,
+// LoadFromRedis, SyncToRedis, Delete, Clear, the version checks (NeedsRefresh,
+// StartAutoRefresh, WithStaleWhileRevalidate, WithMemoryTTL, Watch) and the loader work
+// with any store; features built on Redis commands need a RedisCache: WithPubSubReload
+// panics, StartLeaderRefresh and WithRefreshAhead report ErrRedisRequired, and
+// WithPersistedIndexes, WithDiffSync and the version check of Delete are skipped.
+// Passing a *RedisCache is the same as NewHybridCache.
+func NewHybridCacheWithStore[V any](memoryConfig *Config[V], store RemoteStore[V]) *HybridCache[V] {
+	c := &HybridCache[V]{memory: NewMultiIndexCache(memoryConfig), remote: store}
+	c.redis, _ = store.(*RedisCache[V])
+	return c
+}
+
+// Store returns the shared tier: the RedisCache returned by Redis, or the store passed to
+// NewHybridCacheWithStore.
+func (c *HybridCache[V]) Store() RemoteStore[V] {
+	return c.remote
+}
+
+// fetchRemote reads the dataset with its version and reports whether one is stored. A
+// RedisCache reads both at once; other stores read the version first, so a concurrent
+// write can only make the next version check reload again.
+func (c *HybridCache[V]) fetchRemote(ctx context.Context) (values []V, version int64, found bool, err error) {
+	if c.redis != nil {
+		return c.redis.fetch(ctx, true)
+	}
+	if version, err = c.remote.GetVersionCtx(ctx); err != nil {
+		return nil, 0, false, err
+	}
+	if values, err = c.remote.GetCtx(ctx); err != nil {
+		return nil, 0, false, err
+	}
+	found = len(values) > 0
+	if !found {
+		if found, err = c.remote.ExistsCtx(ctx); err != nil {
+			return nil, 0, false, err
+		}
+	}
+	return values, version, found, nil
+}
+
+// codec returns the codec HybridCache encodes datasets with outside the store (cold
+// copies, consistency checks): the RedisCache one, or JSONCodec for other stores.
+func (c *HybridCache[V]) codec() Codec {
+	if c.redis != nil {
+		return c.redis.codec()
+	}
+	return JSONCodec{}
+}
+
+// decode decodes a dataset encoded with codec.
+func (c *HybridCache[V]) decode(data []byte) ([]V, error) {
+	if c.redis != nil {
+		return c.redis.decode(data)
+	}
+	var values []V
+	if err := c.codec().Unmarshal(data, &values); err != nil {
+		return nil, decodeError(fmt.Errorf("failed to unmarshal values: %w", err))
+	}
+	if values == nil {
+		values = []V{}
+	}
+	return values, nil
+}
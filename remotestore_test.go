@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is a RemoteStore kept in process.
+type mapStore struct {
+	mu      sync.Mutex
+	values  []TestUser
+	version int64
+	stored  bool
+}
+
+func (s *mapStore) GetCtx(context.Context) ([]TestUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]TestUser{}, s.values...), nil
+}
+
+func (s *mapStore) SetCtx(_ context.Context, values []TestUser, _ ...SetOption) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values, s.stored = slices.Clone(values), true
+	s.version++
+	return nil
+}
+
+func (s *mapStore) ExistsCtx(context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stored, nil
+}
+
+func (s *mapStore) GetVersionCtx(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.version, nil
+}
+
+func (s *mapStore) ClearCtx(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values, s.version, s.stored = nil, 0, false
+	return nil
+}
+
+func (s *mapStore) TTLCtx(context.Context) (time.Duration, error) {
+	if ok, _ := s.ExistsCtx(context.Background()); !ok {
+		return -2, nil
+	}
+	return -1, nil
+}
+
+func TestHybridCache_WithStore(t *testing.T) {
+	store := &mapStore{}
+	writer := NewHybridCacheWithStore[TestUser](userConfig(), store)
+	reader := NewHybridCacheWithStore[TestUser](userConfig(), store).WithEmptyRemotePolicy(EmptyRemoteError)
+	ctx := context.Background()
+
+	if writer.Redis() != nil || writer.Store() != store {
+		t.Error("Expected a custom store to have no RedisCache")
+	}
+	if err := reader.LoadFromRedis(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected the empty remote policy for a missing dataset, got %v", err)
+	}
+
+	if err := writer.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if writer.LoadedVersion() != 1 {
+		t.Errorf("Expected the written version to be recorded, got %d", writer.LoadedVersion())
+	}
+	if stale, err := reader.NeedsRefresh(ctx); err != nil || !stale {
+		t.Errorf("Expected the reader to need a refresh, got %v, %v", stale, err)
+	}
+	if err := reader.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if got := idsOf(reader.GetAll()); got != "1,2" {
+		t.Errorf("Expected the stored dataset, got %s", got)
+	}
+
+	if err := writer.Delete("1"); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+	if values, _ := store.GetCtx(ctx); idsOf(values) != "2" {
+		t.Errorf("Expected Delete to rewrite the store, got %s", idsOf(values))
+	}
+	if report, err := writer.ConsistencyCheck(ctx); err != nil || !report.Consistent {
+		t.Errorf("Expected memory and store to agree, got %+v, %v", report, err)
+	}
+
+	if err := writer.Clear(ctx); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if ok, _ := store.ExistsCtx(ctx); ok || writer.memory.Len() != 0 {
+		t.Error("Expected Clear to empty both tiers")
+	}
+}
+
+func TestHybridCache_WithStoreRedisFeatures(t *testing.T) {
+	errs := &taskErrors{}
+	cache := NewHybridCacheWithStore[TestUser](userConfig(), &mapStore{}).WithErrorHandler(errs.handle)
+
+	cache.StartLeaderRefresh(5 * time.Millisecond)
+	defer cache.Stop()
+	waitFor(t, func() bool { return errs.count(TaskLeader) > 0 })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected WithPubSubReload to panic without a RedisCache")
+		}
+	}()
+	cache.WithPubSubReload()
+}
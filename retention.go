@@ -0,0 +1,64 @@
+package cache
+
+import "time"
+
+// SnapshotInfo describes a retained snapshot (see MemoryCache.History).
+type SnapshotInfo struct {
+	Hash      string
+	UpdatedAt time.Time
+	Len       int
+}
+
+// retainSnapshot records the current state when Config.SnapshotRetention is set, dropping
+// the oldest retained snapshot beyond the limit. The caller must hold the write lock.
+func (c *MemoryCache[V]) retainSnapshot() {
+	limit := c.config.SnapshotRetention
+	if limit <= 0 {
+		return
+	}
+	if len(c.history) >= limit {
+		// Copy instead of reslicing so dropped snapshots can be garbage collected.
+		c.history = append([]*Snapshot[V](nil), c.history[len(c.history)-limit+1:]...)
+	}
+	c.history = append(c.history, c.snapshot())
+}
+
+// AtTime returns the retained snapshot of the dataset as it was at t: the latest state
+// modified at or before t. Returns false if retention is disabled or t predates every
+// retained snapshot.
+func (c *MemoryCache[V]) AtTime(t time.Time) (*Snapshot[V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if !c.history[i].updatedAt.After(t) {
+			return c.history[i], true
+		}
+	}
+	return nil, false
+}
+
+// AtHash returns the most recent retained snapshot whose hash is h.
+func (c *MemoryCache[V]) AtHash(h string) (*Snapshot[V], bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for i := len(c.history) - 1; i >= 0; i-- {
+		if c.history[i].hash == h {
+			return c.history[i], true
+		}
+	}
+	return nil, false
+}
+
+// History describes the retained snapshots, oldest first. The last entry is the current state.
+func (c *MemoryCache[V]) History() []SnapshotInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	infos := make([]SnapshotInfo, len(c.history))
+	for i, s := range c.history {
+		infos[i] = SnapshotInfo{Hash: s.hash, UpdatedAt: s.updatedAt, Len: s.Len()}
+	}
+	return infos
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_TimeTravel(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)}
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithIndex("email", func(u TestUser) string { return u.Email }).
+		WithSnapshotRetention(3))
+	cache.now = clock.Now
+
+	cache.Set([]TestUser{{ID: "1", Email: "v1@example.com"}}) // 14:00
+	hashV1 := cache.GetHash()
+	clock.Advance(2 * time.Minute)
+	cache.Set([]TestUser{{ID: "1", Email: "v2@example.com"}}) // 14:02
+	clock.Advance(3 * time.Minute)
+	cache.Upsert([]TestUser{{ID: "2", Email: "new@example.com"}}) // 14:05
+
+	at, ok := cache.AtTime(time.Date(2024, 5, 1, 14, 3, 0, 0, time.UTC))
+	if !ok {
+		t.Fatal("Expected a snapshot at 14:03")
+	}
+	if _, found := at.GetByIndex("email", "v2@example.com"); !found || at.Len() != 1 {
+		t.Errorf("Expected the 14:02 dataset, got %v", at.GetAll())
+	}
+
+	if _, ok := cache.AtTime(time.Date(2024, 5, 1, 13, 59, 0, 0, time.UTC)); ok {
+		t.Error("Expected no snapshot before the first Set")
+	}
+
+	byHash, ok := cache.AtHash(hashV1)
+	if !ok {
+		t.Fatal("Expected snapshot for the first hash")
+	}
+	if v, _ := byHash.Get("1"); v.Email != "v1@example.com" {
+		t.Errorf("Expected first dataset, got %+v", v)
+	}
+
+	// Retention is bounded: a fourth state drops the oldest.
+	cache.Clear()
+	if _, ok := cache.AtHash(hashV1); ok {
+		t.Error("Expected the oldest snapshot to be dropped")
+	}
+	history := cache.History()
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 retained snapshots, got %d", len(history))
+	}
+	if last := history[2]; last.Len != 0 || last.Hash != cache.GetHash() {
+		t.Errorf("Expected current (cleared) state last, got %+v", last)
+	}
+	if history[1].Len != 2 {
+		t.Errorf("Expected upserted state with 2 entries, got %+v", history[1])
+	}
+}
+
+func TestMemoryCache_TimeTravelDisabled(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}})
+
+	if _, ok := cache.AtTime(time.Now()); ok {
+		t.Error("Expected no snapshots without retention")
+	}
+	if _, ok := cache.AtHash(cache.GetHash()); ok {
+		t.Error("Expected no snapshots without retention")
+	}
+	if len(cache.History()) != 0 {
+		t.Error("Expected empty history without retention")
+	}
+}
@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// RefreshJob is how a RefreshScheduler refreshes one cache.
+type RefreshJob struct {
+	// Refresh refreshes the cache, e.g. a HybridCache's RefreshFromSource method, or
+	// LoadFromRedis wrapped in a func(context.Context) error.
+	Refresh func(ctx context.Context) error
+	// Interval is the wait between the end of a refresh and the start of the next.
+	Interval time.Duration
+	// Jitter, if positive, adds a random wait in [0, Jitter) to every interval, so caches
+	// with the same interval do not refresh in lockstep across instances.
+	Jitter time.Duration
+	// After names the jobs this one depends on: its first refresh waits for their first
+	// refresh to finish, and every successful refresh of one of them refreshes this job
+	// right after, e.g. users embedding plans refresh whenever plans changed.
+	After []string
+}
+
+// RefreshHealth is the refresh history of one job of a RefreshScheduler.
+type RefreshHealth struct {
+	Runs     uint64 // refreshes run so far
+	Failures uint64 // of those, the ones that failed
+
+	// ConsecutiveFailures is the number of failed refreshes since the last success.
+	ConsecutiveFailures int
+
+	LastRun      time.Time     // when the latest refresh started; zero if none did
+	LastDuration time.Duration // how long the latest refresh took
+	LastSuccess  time.Time     // when the latest successful refresh finished; zero if none did
+	LastError    error         // error of the latest refresh, nil if it succeeded
+}
+
+// Healthy reports whether the job refreshed at least once and the latest refresh succeeded.
+func (h RefreshHealth) Healthy() bool {
+	return !h.LastSuccess.IsZero() && h.LastError == nil
+}
+
+// RefreshScheduler owns the refresh cadence of many caches, instead of one ticker per
+// cache, whatever their value types:
+//
+//	scheduler := cache.NewRefreshScheduler(4).
+//		Add("plans", cache.RefreshJob{Refresh: plans.RefreshFromSource, Interval: time.Minute}).
+//		Add("users", cache.RefreshJob{Refresh: users.RefreshFromSource, Interval: 5 * time.Minute,
+//			Jitter: 30 * time.Second, After: []string{"plans"}})
+//	go scheduler.Run(ctx)
+//
+// Every job refreshes once right away (after the jobs it depends on), then every
+// Interval plus Jitter; at most concurrency refreshes run at a time. Health reports how
+// each job fares. Add every job before Run.
+type RefreshScheduler struct {
+	concurrency int
+	names       []string
+	jobs        map[string]RefreshJob
+
+	mu      sync.Mutex
+	running bool
+	health  map[string]RefreshHealth
+}
+
+// NewRefreshScheduler creates a scheduler running at most concurrency refreshes at a time;
+// values below 1 do not limit them.
+func NewRefreshScheduler(concurrency int) *RefreshScheduler {
+	return &RefreshScheduler{
+		concurrency: concurrency,
+		jobs:        make(map[string]RefreshJob),
+		health:      make(map[string]RefreshHealth),
+	}
+}
+
+// Add registers job under name, which identifies the cache in Health and in After.
+// Adding a name again replaces its job.
+func (s *RefreshScheduler) Add(name string, job RefreshJob) *RefreshScheduler {
+	if _, ok := s.jobs[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.jobs[name] = job
+	return s
+}
+
+// validate reports jobs without a Refresh or a positive Interval, unknown dependencies and
+// dependency cycles, joined.
+func (s *RefreshScheduler) validate() error {
+	var errs []error
+	for _, name := range s.names {
+		job := s.jobs[name]
+		if job.Refresh == nil {
+			errs = append(errs, fmt.Errorf("cache-kit: refresh job %q has no Refresh", name))
+		}
+		if job.Interval <= 0 {
+			errs = append(errs, fmt.Errorf("cache-kit: refresh job %q needs a positive Interval", name))
+		}
+		for _, dep := range job.After {
+			if _, ok := s.jobs[dep]; !ok {
+				errs = append(errs, fmt.Errorf("cache-kit: refresh job %q is after unknown job %q", name, dep))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// Depth-first search for cycles: 1 while visiting, 2 once done.
+	state := make(map[string]int, len(s.names))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("cache-kit: refresh job %q depends on itself", name)
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, dep := range s.jobs[name].After {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		return nil
+	}
+	for _, name := range s.names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run refreshes the jobs until ctx is done, then waits for running refreshes to return and
+// returns ctx.Err(). It returns an error right away if a job is invalid (no Refresh, no
+// positive Interval, an unknown or cyclic dependency) or the scheduler is already running.
+func (s *RefreshScheduler) Run(ctx context.Context) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return errors.New("cache-kit: refresh scheduler is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	var sem chan struct{}
+	if s.concurrency > 0 {
+		sem = make(chan struct{}, s.concurrency)
+	}
+	first := make(map[string]chan struct{}, len(s.names)) // closed after the first refresh
+	kick := make(map[string]chan struct{}, len(s.names))  // a dependency refreshed
+	dependents := make(map[string][]string)
+	for _, name := range s.names {
+		first[name] = make(chan struct{})
+		kick[name] = make(chan struct{}, 1)
+		for _, dep := range s.jobs[name].After {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range s.names {
+		job := s.jobs[name]
+		wg.Go(func() {
+			for _, dep := range job.After {
+				select {
+				case <-first[dep]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			for initial := true; ; initial = false {
+				select {
+				case <-kick[name]: // this refresh covers it
+				default:
+				}
+				ran, err := s.refresh(ctx, name, job, sem)
+				if initial {
+					close(first[name])
+				}
+				if !ran {
+					return
+				}
+				if err == nil {
+					for _, d := range dependents[name] {
+						select {
+						case kick[d] <- struct{}{}:
+						default:
+						}
+					}
+				}
+				wait := job.Interval
+				if job.Jitter > 0 {
+					wait += rand.N(job.Jitter)
+				}
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				case <-kick[name]:
+					timer.Stop()
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// refresh runs one refresh of job once a concurrency slot is free, and records it; ran is
+// false if ctx was done first.
+func (s *RefreshScheduler) refresh(ctx context.Context, name string, job RefreshJob, sem chan struct{}) (ran bool, err error) {
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			return false, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	start := time.Now()
+	err = job.Refresh(ctx)
+	end := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.health[name]
+	h.Runs++
+	h.LastRun, h.LastDuration, h.LastError = start, end.Sub(start), err
+	if err != nil {
+		h.Failures++
+		h.ConsecutiveFailures++
+	} else {
+		h.ConsecutiveFailures = 0
+		h.LastSuccess = end
+	}
+	s.health[name] = h
+	return true, err
+}
+
+// Health returns the refresh history of every added job, by name; jobs that did not run
+// yet have a zero RefreshHealth.
+func (s *RefreshScheduler) Health() map[string]RefreshHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	health := make(map[string]RefreshHealth, len(s.names))
+	for _, name := range s.names {
+		health[name] = s.health[name]
+	}
+	return health
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshScheduler_Run(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return err
+		}
+	}
+	scheduler := NewRefreshScheduler(1).
+		Add("users", RefreshJob{Refresh: record("users", nil), Interval: time.Hour, After: []string{"plans"}}).
+		Add("plans", RefreshJob{Refresh: record("plans", nil), Interval: 10 * time.Millisecond, Jitter: time.Millisecond}).
+		Add("flags", RefreshJob{Refresh: record("flags", errors.New("boom")), Interval: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	// plans refreshes every 10ms and each success refreshes users right after.
+	waitFor(t, func() bool { return scheduler.Health()["users"].Runs >= 3 })
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	mu.Lock()
+	firstUsers, firstPlans := -1, -1
+	for i, name := range order {
+		if name == "users" && firstUsers < 0 {
+			firstUsers = i
+		}
+		if name == "plans" && firstPlans < 0 {
+			firstPlans = i
+		}
+	}
+	mu.Unlock()
+	if firstPlans < 0 || firstUsers < firstPlans {
+		t.Errorf("Expected plans refreshed before users, got %v", order)
+	}
+
+	health := scheduler.Health()
+	if h := health["users"]; !h.Healthy() || h.ConsecutiveFailures != 0 {
+		t.Errorf("Expected users healthy, got %+v", h)
+	}
+	if h := health["flags"]; h.Healthy() || h.Runs != 1 || h.Failures != 1 || h.ConsecutiveFailures != 1 || h.LastError == nil {
+		t.Errorf("Expected one failed flags refresh, got %+v", h)
+	}
+}
+
+func TestRefreshScheduler_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	refresh := func(context.Context) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return nil
+	}
+	scheduler := NewRefreshScheduler(2)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		scheduler.Add(name, RefreshJob{Refresh: refresh, Interval: time.Hour})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx) }()
+	waitFor(t, func() bool {
+		for _, h := range scheduler.Health() {
+			if h.Runs == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	cancel()
+	<-done
+	if got := peak.Load(); got > 2 {
+		t.Errorf("Expected at most 2 concurrent refreshes, got %d", got)
+	}
+}
+
+func TestRefreshScheduler_Invalid(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+	tests := map[string]struct {
+		scheduler *RefreshScheduler
+		want      string
+	}{
+		"no refresh":  {NewRefreshScheduler(0).Add("a", RefreshJob{Interval: time.Second}), "has no Refresh"},
+		"no interval": {NewRefreshScheduler(0).Add("a", RefreshJob{Refresh: noop}), "positive Interval"},
+		"unknown dependency": {NewRefreshScheduler(0).
+			Add("a", RefreshJob{Refresh: noop, Interval: time.Second, After: []string{"b"}}), `unknown job "b"`},
+		"cycle": {NewRefreshScheduler(0).
+			Add("a", RefreshJob{Refresh: noop, Interval: time.Second, After: []string{"b"}}).
+			Add("b", RefreshJob{Refresh: noop, Interval: time.Second, After: []string{"a"}}), "depends on itself"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.scheduler.Run(context.Background())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
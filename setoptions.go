@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrVersionConflict is returned by Set with WithIfVersion when the stored version no longer
+// equals the expected one; Redis is left unchanged.
+var ErrVersionConflict = errors.New("cache-kit: stored version differs from the expected version")
+
+// SetOption adjusts a single RedisCache.Set. Options replace the method permutations:
+// Set(values, WithTTL(ttl)) is SetWithTTL and Set(values, WithIfVersion(v)) is SetIfVersion,
+// reporting a lost race as ErrVersionConflict.
+type SetOption func(*setOptions)
+
+// setOptions holds the effect of the SetOptions of one write.
+type setOptions struct {
+	ttl          time.Duration
+	noBump       bool
+	codec        Codec
+	checkVersion bool
+	expected     int64
+}
+
+// ErrUnsupportedSetOption is returned by a RemoteStore given a SetOption it cannot apply;
+// the store is left unchanged.
+var ErrUnsupportedSetOption = errors.New("cache-kit: set option not supported by this store")
+
+// SetOptions is the combined effect of a list of SetOption, for RemoteStore
+// implementations to read with ApplySetOptions.
+type SetOptions struct {
+	TTL             time.Duration // WithTTL; 0 if not given
+	NoVersionBump   bool          // WithNoVersionBump
+	Codec           Codec         // WithCodec; nil if not given
+	IfVersion       bool          // WithIfVersion, expecting ExpectedVersion
+	ExpectedVersion int64
+}
+
+// ApplySetOptions returns the combined effect of opts.
+func ApplySetOptions(opts ...SetOption) SetOptions {
+	var o setOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return SetOptions{
+		TTL:             o.ttl,
+		NoVersionBump:   o.noBump,
+		Codec:           o.codec,
+		IfVersion:       o.checkVersion,
+		ExpectedVersion: o.expected,
+	}
+}
+
+// IsZero reports whether no option had an effect.
+func (o SetOptions) IsZero() bool {
+	return o.TTL == 0 && !o.NoVersionBump && o.Codec == nil && !o.IfVersion
+}
+
+// WithTTL stores the dataset with ttl instead of RedisConfig.TTL (or EmptyTTL).
+func WithTTL(ttl time.Duration) SetOption {
+	return func(o *setOptions) { o.ttl = ttl }
+}
+
+// WithNoVersionBump keeps the stored version, e.g. to repair data in place without
+// signalling a change to readers polling the version. The version key's TTL is still reset.
+// Cannot be combined with WithIfVersion.
+func WithNoVersionBump() SetOption {
+	return func(o *setOptions) { o.noBump = true }
+}
+
+// WithCodec encodes this write with codec instead of RedisConfig.Codec. Readers must decode
+// the key with the same codec.
+func WithCodec(codec Codec) SetOption {
+	return func(o *setOptions) { o.codec = codec }
+}
+
+// WithIfVersion writes only if the stored version still equals expectedVersion, like
+// SetIfVersion; otherwise Set returns ErrVersionConflict.
+func WithIfVersion(expectedVersion int64) SetOption {
+	return func(o *setOptions) {
+		o.checkVersion = true
+		o.expected = expectedVersion
+	}
+}
+
+// setWithOptions implements SetCtx with options.
+func (c *RedisCache[V]) setWithOptions(ctx context.Context, values []V, opts []SetOption) error {
+	o := setOptions{ttl: c.setTTL(values)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	target := c
+	if o.codec != nil {
+		target = c.withCodec(o.codec)
+	}
+	if !o.checkVersion {
+		return target.write(ctx, values, o.ttl, !o.noBump)
+	}
+	if o.noBump {
+		return errors.New("cache-kit: WithIfVersion cannot be combined with WithNoVersionBump")
+	}
+	ok, err := target.setIfVersion(ctx, values, o.expected, o.ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// withCodec returns a copy of c sharing its client and keys but encoding with codec.
+func (c *RedisCache[V]) withCodec(codec Codec) *RedisCache[V] {
+	config := *c.config
+	config.Codec = codec
+	copied := *c
+	copied.config = &config
+	return &copied
+}
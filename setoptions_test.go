@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_SetOptions(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := cache.Set([]TestUser{{ID: "1"}}, WithTTL(time.Minute)); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if ttl := mr.TTL(cache.key); ttl != time.Minute {
+		t.Errorf("Expected a TTL of 1m, got %v", ttl)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "2"}}, WithNoVersionBump()); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, version, _ := cache.GetWithVersion()
+	if idsOf(values) != "2" || version != 1 {
+		t.Errorf("Expected 2 at version 1, got %s at %d", idsOf(values), version)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "3"}}, WithIfVersion(0)); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "3"}}, WithIfVersion(1), WithTTL(time.Minute)); err != nil {
+		t.Errorf("Expected a conditional write at version 1 to succeed, got %v", err)
+	}
+	if version, _ := cache.GetVersion(); version != 2 {
+		t.Errorf("Expected version 2, got %d", version)
+	}
+	if err := cache.Set(nil, WithIfVersion(2), WithNoVersionBump()); err == nil {
+		t.Error("Expected an error combining WithIfVersion and WithNoVersionBump")
+	}
+
+	if err := cache.Set([]TestUser{{ID: "4"}}, WithCodec(GobCodec{})); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	gob := NewRedisCache[TestUser](client, DefaultRedisConfig().WithCodec(GobCodec{}))
+	if values, err := gob.Get(); err != nil || idsOf(values) != "4" {
+		t.Errorf("Expected 4 decoded with gob, got %s (%v)", idsOf(values), err)
+	}
+	if cache.config.Codec != nil {
+		t.Error("Expected WithCodec to leave the cache configuration unchanged")
+	}
+}
+
+func TestApplySetOptions(t *testing.T) {
+	if o := ApplySetOptions(); !o.IsZero() {
+		t.Errorf("Expected no effect without options, got %+v", o)
+	}
+	o := ApplySetOptions(WithTTL(time.Minute), WithIfVersion(3), WithCodec(JSONCodec{}))
+	if o.TTL != time.Minute || !o.IfVersion || o.ExpectedVersion != 3 || o.Codec == nil || o.NoVersionBump || o.IsZero() {
+		t.Errorf("Unexpected options %+v", o)
+	}
+}
+
+func TestRedisCache_SetNoVersionBumpEnvelope(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithVersionedEnvelope(true))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.Set([]TestUser{{ID: "2"}}, WithNoVersionBump()); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	values, version, err := cache.GetWithVersion()
+	if err != nil || idsOf(values) != "2" || version != 1 {
+		t.Errorf("Expected 2 at version 1 with a consistent envelope, got %s at %d (%v)", idsOf(values), version, err)
+	}
+}
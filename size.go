@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ValueSize returns the size in bytes of the stored dataset, 0 if nothing is cached, so
+// dashboards can alert when a dataset grows abnormally. In the default and blue/green
+// storage modes it is the length of the payload (STRLEN, including any envelope); in the
+// hash, list and JSON storage modes it is the memory Redis uses for the data key (MEMORY
+// USAGE), which includes per-entry overhead. The size is also reported to the MetricsHook
+// as the bytes of OpValueSize; OpSet and OpGet report the bytes written and read.
+func (c *RedisCache[V]) ValueSize(ctx context.Context) (size int64, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpValueSize, start, int(size), err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	switch c.storage {
+	case storageBlob:
+		size, err = c.client.StrLen(ctx, c.key).Result()
+	case storageBlueGreen:
+		size, err = generationSizeScript.Run(ctx, c.client, []string{c.key}).Int64()
+	default:
+		size, err = c.client.MemoryUsage(ctx, c.key).Result()
+		if err == redis.Nil {
+			return 0, nil
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get value size: %w", err)
+	}
+	return size, nil
+}
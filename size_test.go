@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRedisCache_ValueSize(t *testing.T) {
+	ctx := context.Background()
+	mr, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithMetricsHook(hook))
+
+	if size, err := cache.ValueSize(ctx); err != nil || size != 0 {
+		t.Errorf("Expected 0 for an empty cache, got %d (%v)", size, err)
+	}
+	if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	written := hook.last().bytes
+	stored, _ := mr.Get(cache.key)
+	if written != len(stored) {
+		t.Errorf("Expected Set to report %d bytes, got %d", len(stored), written)
+	}
+	size, err := cache.ValueSize(ctx)
+	if err != nil || size != int64(len(stored)) {
+		t.Errorf("Expected %d bytes, got %d (%v)", len(stored), size, err)
+	}
+	if last := hook.last(); last.op != OpValueSize || last.bytes != len(stored) {
+		t.Errorf("Expected OpValueSize with %d bytes, got %+v", len(stored), last)
+	}
+}
+
+func TestRedisCache_ValueSizeStorageModes(t *testing.T) {
+	ctx := context.Background()
+	_, client := setupMiniRedis(t)
+	caches := map[string]*RedisCache[TestUser]{
+		"hash":      NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("hash:")).WithHashStorage(func(u TestUser) string { return u.ID }),
+		"list":      NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("list:")).WithListStorage(),
+		"bluegreen": NewRedisCache[TestUser](client, DefaultRedisConfig().WithKeyPrefix("bg:")).WithBlueGreen(),
+	}
+	for name, cache := range caches {
+		if size, err := cache.ValueSize(ctx); err != nil || size != 0 {
+			t.Errorf("%s: expected 0 for an empty cache, got %d (%v)", name, size, err)
+		}
+		if err := cache.Set([]TestUser{{ID: "1"}, {ID: "2"}}); err != nil {
+			t.Fatalf("%s: Set error: %v", name, err)
+		}
+		if size, err := cache.ValueSize(ctx); err != nil || size <= 0 {
+			t.Errorf("%s: expected a positive size, got %d (%v)", name, size, err)
+		}
+	}
+}
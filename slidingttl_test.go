@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedisCache_SlidingTTL(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithTTL(time.Minute).WithSlidingTTL(true).WithPublishMetadata(true).WithMetricsHook(hook))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.FastForward(40 * time.Second)
+	if values, err := cache.Get(); err != nil || idsOf(values) != "1" {
+		t.Fatalf("Expected 1, got %s (%v)", idsOf(values), err)
+	}
+	for _, key := range []string{cache.key, cache.versionKey(), cache.metadataKey()} {
+		if ttl := mr.TTL(key); ttl != time.Minute {
+			t.Errorf("Expected the TTL of %q to be reset to 1m, got %v", key, ttl)
+		}
+	}
+	// The reset is reported just before the read itself.
+	if op := hook.ops[len(hook.ops)-2]; op.op != OpRefresh || op.err != nil {
+		t.Errorf("Expected a successful OpRefresh, got %+v", op)
+	}
+
+	// Without reads the dataset expires as usual.
+	mr.FastForward(61 * time.Second)
+	if mr.Exists(cache.key) {
+		t.Error("Expected an idle dataset to expire")
+	}
+}
+
+func TestRedisCache_NoSlidingTTL(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithTTL(time.Minute))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.FastForward(40 * time.Second)
+	if _, err := cache.Get(); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if ttl := mr.TTL(cache.key); ttl != 20*time.Second {
+		t.Errorf("Expected reads to leave the TTL alone, got %v", ttl)
+	}
+}
@@ -0,0 +1,79 @@
+package cache
+
+import "time"
+
+// Snapshot is an immutable, point-in-time copy of a MemoryCache dataset including its indexes.
+// Taking a snapshot is O(number of indexes): the cache never mutates storage that has been
+// published, it replaces it (copy-on-write), so a snapshot shares data with the cache safely.
+// A Snapshot is safe for concurrent use without locking.
+type Snapshot[V any] struct {
+	MapView[V]
+
+	indexes   map[string]map[string]string // index name -> index key -> primary key
+	hash      string
+	updatedAt time.Time
+}
+
+// Snapshot returns an immutable view of the current dataset.
+// Reads from the snapshot observe exactly the dataset present at the time of the call,
+// regardless of concurrent Set, Clear or invalidation calls.
+func (c *MemoryCache[V]) Snapshot() *Snapshot[V] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.snapshot()
+}
+
+// snapshot builds a Snapshot of the current state. The caller must hold the lock.
+func (c *MemoryCache[V]) snapshot() *Snapshot[V] {
+	indexes := make(map[string]map[string]string, len(c.indexes))
+	for name, index := range c.indexes {
+		indexes[name] = index
+	}
+	return &Snapshot[V]{
+		MapView:   MapView[V]{data: c.data, arena: c.arena, order: c.order},
+		indexes:   indexes,
+		hash:      c.hash,
+		updatedAt: c.updatedAt,
+	}
+}
+
+// GetByIndex retrieves a value by a named index as of the snapshot.
+func (s *Snapshot[V]) GetByIndex(indexName string, key string) (V, bool) {
+	var zero V
+	index, exists := s.indexes[indexName]
+	if !exists {
+		return zero, false
+	}
+	pk, exists := index[normalizeIndexKey(key)]
+	if !exists {
+		return zero, false
+	}
+	return s.Get(pk)
+}
+
+// GetAll returns all values of the snapshot in insertion order.
+func (s *Snapshot[V]) GetAll() []V {
+	result := make([]V, 0, len(s.order))
+	s.Iterate(func(v V) bool {
+		result = append(result, v)
+		return true
+	})
+	return result
+}
+
+// Iterate applies fn to each value of the snapshot in insertion order.
+// If fn returns false, iteration stops.
+func (s *Snapshot[V]) Iterate(fn func(value V) bool) {
+	s.Range(func(_ string, v V) bool { return fn(v) })
+}
+
+// Hash returns the cache hash at the time of the snapshot.
+func (s *Snapshot[V]) Hash() string {
+	return s.hash
+}
+
+// UpdatedAt returns when the snapshotted dataset was last modified; zero if never set.
+func (s *Snapshot[V]) UpdatedAt() time.Time {
+	return s.updatedAt
+}
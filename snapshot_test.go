@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_Snapshot(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+
+	snap := cache.Snapshot()
+	hash := cache.GetHash()
+
+	cache.Set([]TestUser{{ID: "3", Email: "c@example.com"}})
+	cache.AddIndex("name", func(u TestUser) string { return u.Name })
+
+	if snap.Len() != 2 {
+		t.Errorf("Expected snapshot length 2, got %d", snap.Len())
+	}
+	if u, ok := snap.GetByIndex("email", " A@example.com "); !ok || u.ID != "1" {
+		t.Errorf("Expected snapshot index lookup to find 1, got %+v (ok=%v)", u, ok)
+	}
+	if _, ok := snap.GetByIndex("email", "c@example.com"); ok {
+		t.Error("Expected snapshot not to see later data")
+	}
+	if _, ok := snap.GetByIndex("name", ""); ok {
+		t.Error("Expected snapshot not to see later indexes")
+	}
+	if snap.Hash() != hash {
+		t.Error("Expected snapshot hash to match the hash at snapshot time")
+	}
+	if all := snap.GetAll(); len(all) != 2 || all[0].ID != "1" || all[1].ID != "2" {
+		t.Errorf("Unexpected snapshot contents %+v", all)
+	}
+	if snap.UpdatedAt().IsZero() {
+		t.Error("Expected snapshot UpdatedAt to be set")
+	}
+}
+
+func TestMemoryCache_IterateObservesPreSetDataset(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+
+	started := make(chan struct{})
+	resume := make(chan struct{})
+	done := make(chan []string)
+	go func() {
+		var seen []string
+		cache.Iterate(func(u TestUser) bool {
+			seen = append(seen, u.ID)
+			if len(seen) == 1 {
+				close(started)
+				<-resume
+			}
+			return true
+		})
+		done <- seen
+	}()
+
+	<-started
+	setDone := make(chan struct{})
+	go func() {
+		cache.Set([]TestUser{{ID: "4"}})
+		close(setDone)
+	}()
+	select {
+	case <-setDone:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Set not to be blocked by a running iteration")
+	}
+	close(resume)
+
+	seen := <-done
+	if len(seen) != 3 || seen[0] != "1" || seen[1] != "2" || seen[2] != "3" {
+		t.Errorf("Expected iteration to observe the full pre-Set dataset, got %v", seen)
+	}
+}
+
+func TestMemoryCache_IterateCallbackMayUseCache(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}})
+
+	cache.Iterate(func(u TestUser) bool {
+		cache.Set([]TestUser{{ID: "2"}}) // would deadlock if Iterate held the lock
+		return true
+	})
+	if _, ok := cache.Get("2"); !ok {
+		t.Error("Expected Set from within Iterate to succeed")
+	}
+}
+
+func TestMemoryCache_SnapshotUnchangedByInvalidate(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{u.Tenant} })
+	cache := NewMultiIndexCache(config)
+	cache.AddIndex("email", func(u TestTenantUser) string { return u.Email })
+	cache.Set([]TestTenantUser{{ID: "1", Email: "a@example.com", Tenant: "acme"}})
+
+	snap := cache.Snapshot()
+	cache.InvalidateByTag("acme")
+
+	if _, ok := snap.GetByIndex("email", "a@example.com"); !ok {
+		t.Error("Expected snapshot index to be unaffected by invalidation")
+	}
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// softKeySuffix is appended to the data key to name the soft expiry marker, a key that
+// expires RedisConfig.SoftTTL after each full write.
+const softKeySuffix = ":soft"
+
+// softKey returns the soft expiry marker key for this cache.
+func (c *RedisCache[V]) softKey() string {
+	return c.key + softKeySuffix
+}
+
+// IsSoftExpired reports whether RedisConfig.SoftTTL has passed since the last full write,
+// i.e. the data is stale and should be refreshed, while reads keep succeeding until the hard
+// TTL drops it. Also true if nothing was written (or it was cleared), so refresh logic can
+// call it unconditionally. Without SoftTTL it reports whether the data key is missing.
+// Refresh extends the hard TTL only and does not make stale data fresh.
+func (c *RedisCache[V]) IsSoftExpired() (bool, error) {
+	return c.IsSoftExpiredCtx(context.Background())
+}
+
+// IsSoftExpiredCtx is like IsSoftExpired but uses ctx for the Redis calls.
+func (c *RedisCache[V]) IsSoftExpiredCtx(ctx context.Context) (_ bool, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpSoftExpired, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	key := c.key
+	if c.config.SoftTTL > 0 {
+		key = c.softKey()
+	}
+	n, err := c.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check soft expiry: %w", err)
+	}
+	return n == 0, nil
+}
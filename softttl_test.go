@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedisCache_IsSoftExpired(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithTTL(time.Hour).
+		WithSoftTTL(10*time.Minute))
+
+	if expired, err := cache.IsSoftExpired(); err != nil || !expired {
+		t.Errorf("Expected soft-expired before the first write, got %v (%v)", expired, err)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if expired, err := cache.IsSoftExpired(); err != nil || expired {
+		t.Errorf("Expected fresh data right after Set, got %v (%v)", expired, err)
+	}
+
+	// Past the soft TTL: stale, but still readable until the hard TTL.
+	mr.FastForward(15 * time.Minute)
+	if expired, _ := cache.IsSoftExpired(); !expired {
+		t.Error("Expected soft expiry after 15m")
+	}
+	if values, err := cache.Get(); err != nil || len(values) != 1 {
+		t.Errorf("Expected reads to keep succeeding, got %+v (%v)", values, err)
+	}
+	if err := cache.Refresh(); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	if expired, _ := cache.IsSoftExpired(); !expired {
+		t.Error("Expected Refresh not to reset the soft expiry")
+	}
+
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "2"}}, 1); err != nil || !ok {
+		t.Fatalf("SetIfVersion failed: %v (%v)", ok, err)
+	}
+	if expired, _ := cache.IsSoftExpired(); expired {
+		t.Error("Expected SetIfVersion to reset the soft expiry")
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if expired, _ := cache.IsSoftExpired(); !expired {
+		t.Error("Expected soft-expired after Clear")
+	}
+}
+
+func TestRedisCache_IsSoftExpiredWithoutSoftTTL(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithTTL(time.Minute))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if mr.Exists("cache:data" + softKeySuffix) {
+		t.Error("Expected no soft expiry marker without SoftTTL")
+	}
+	if expired, _ := cache.IsSoftExpired(); expired {
+		t.Error("Expected existing data to count as fresh")
+	}
+	mr.FastForward(2 * time.Minute)
+	if expired, _ := cache.IsSoftExpired(); !expired {
+		t.Error("Expected expired data key to count as soft-expired")
+	}
+}
@@ -0,0 +1,247 @@
+// Package sqlstore provides a cache.RemoteStore kept in a database/sql table, so small
+// deployments get HybridCache semantics from the database they already operate:
+//
+//	store := sqlstore.New[User](db, "users", sqlstore.Postgres)
+//	if err := store.CreateTable(ctx); err != nil { ... }
+//	users := cache.NewHybridCacheWithStore(memConfig, store)
+//
+// Each dataset is one row of the table (default "cachekit_datasets") holding the encoded
+// payload, its version, the SHA-256 of the payload and the update time. Several datasets
+// may share the table under different names. Times are stored as Unix nanoseconds, so the
+// schema is the same for every dialect.
+package sqlstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+)
+
+// DefaultTable is the table used unless WithTable sets another one.
+const DefaultTable = "cachekit_datasets"
+
+// Dialect selects the SQL flavour of the queries.
+type Dialect int
+
+const (
+	// SQLite uses ? placeholders and BLOB payloads.
+	SQLite Dialect = iota
+
+	// Postgres uses $n placeholders and BYTEA payloads.
+	Postgres
+)
+
+// Metadata describes the stored dataset.
+type Metadata struct {
+	Version   int64
+	Hash      string // hex SHA-256 of the encoded payload
+	Size      int    // payload bytes
+	UpdatedAt time.Time
+}
+
+// Store is a cache.RemoteStore backed by one row of a SQL table. It is safe for
+// concurrent use, by several processes as well: the version is bumped by the database.
+type Store[V any] struct {
+	db      *sql.DB
+	name    string
+	dialect Dialect
+	table   string
+	codec   cache.Codec
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+var _ cache.RemoteStore[struct{}] = (*Store[struct{}])(nil)
+
+// New creates a Store for the dataset name in db.
+func New[V any](db *sql.DB, name string, dialect Dialect) *Store[V] {
+	return &Store[V]{db: db, name: name, dialect: dialect, table: DefaultTable, codec: cache.JSONCodec{}, now: time.Now}
+}
+
+// WithTable sets the table name; it is inserted in the queries as is.
+func (s *Store[V]) WithTable(table string) *Store[V] {
+	s.table = table
+	return s
+}
+
+// WithCodec sets how the dataset is encoded (cache.JSONCodec by default).
+func (s *Store[V]) WithCodec(codec cache.Codec) *Store[V] {
+	s.codec = codec
+	return s
+}
+
+// WithTTL makes a stored dataset expire ttl after it was written; expired rows read as
+// missing. 0 (the default) keeps datasets until ClearCtx.
+func (s *Store[V]) WithTTL(ttl time.Duration) *Store[V] {
+	s.ttl = ttl
+	return s
+}
+
+// CreateTable creates the table if it does not exist.
+func (s *Store[V]) CreateTable(ctx context.Context) error {
+	payload := "BLOB"
+	if s.dialect == Postgres {
+		payload = "BYTEA"
+	}
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+  name       VARCHAR(255) PRIMARY KEY,
+  payload    `+payload+` NOT NULL,
+  version    BIGINT NOT NULL,
+  hash       VARCHAR(64) NOT NULL,
+  updated_at BIGINT NOT NULL,
+  expires_at BIGINT NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+	return nil
+}
+
+// GetCtx implements cache.RemoteStore.
+func (s *Store[V]) GetCtx(ctx context.Context) ([]V, error) {
+	var data []byte
+	err := s.queryRow(ctx, "payload", &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []V{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var values []V
+	if err := s.codec.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal values: %w", cache.ErrDecode, err)
+	}
+	if values == nil {
+		values = []V{}
+	}
+	return values, nil
+}
+
+// SetCtx implements cache.RemoteStore. Of the SetOptions, cache.WithTTL overrides the
+// store TTL for this write; the others fail with cache.ErrUnsupportedSetOption.
+func (s *Store[V]) SetCtx(ctx context.Context, values []V, opts ...cache.SetOption) error {
+	o := cache.ApplySetOptions(opts...)
+	if o.NoVersionBump || o.Codec != nil || o.IfVersion {
+		return fmt.Errorf("%w: sqlstore supports WithTTL only", cache.ErrUnsupportedSetOption)
+	}
+	ttl := s.ttl
+	if o.TTL > 0 {
+		ttl = o.TTL
+	}
+	if values == nil {
+		values = []V{}
+	}
+	data, err := s.codec.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	now := s.now()
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = now.Add(ttl).UnixNano()
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`INSERT INTO `+s.table+` (name, payload, version, hash, updated_at, expires_at)
+VALUES (?, ?, 1, ?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET payload = excluded.payload, version = `+s.table+`.version + 1,
+  hash = excluded.hash, updated_at = excluded.updated_at, expires_at = excluded.expires_at`),
+		s.name, data, hex.EncodeToString(sum[:]), now.UnixNano(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to set dataset: %w", err)
+	}
+	return nil
+}
+
+// ExistsCtx implements cache.RemoteStore.
+func (s *Store[V]) ExistsCtx(ctx context.Context) (bool, error) {
+	var version int64
+	err := s.queryRow(ctx, "version", &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetVersionCtx implements cache.RemoteStore.
+func (s *Store[V]) GetVersionCtx(ctx context.Context) (int64, error) {
+	var version int64
+	err := s.queryRow(ctx, "version", &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// ClearCtx implements cache.RemoteStore.
+func (s *Store[V]) ClearCtx(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, s.rebind(`DELETE FROM `+s.table+` WHERE name = ?`), s.name); err != nil {
+		return fmt.Errorf("failed to clear dataset: %w", err)
+	}
+	return nil
+}
+
+// TTLCtx implements cache.RemoteStore.
+func (s *Store[V]) TTLCtx(ctx context.Context) (time.Duration, error) {
+	var expiresAt int64
+	err := s.queryRow(ctx, "expires_at", &expiresAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return -2, nil
+	case err != nil:
+		return 0, err
+	case expiresAt == 0:
+		return -1, nil
+	}
+	return time.Duration(expiresAt - s.now().UnixNano()), nil
+}
+
+// Metadata returns the version, hash, size and update time of the stored dataset, and
+// false if none is stored.
+func (s *Store[V]) Metadata(ctx context.Context) (Metadata, bool, error) {
+	var m Metadata
+	var updatedAt int64
+	err := s.queryRow(ctx, "version, hash, LENGTH(payload), updated_at", &m.Version, &m.Hash, &m.Size, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Metadata{}, false, nil
+	}
+	if err != nil {
+		return Metadata{}, false, err
+	}
+	m.UpdatedAt = time.Unix(0, updatedAt)
+	return m, true, nil
+}
+
+// queryRow scans columns of the dataset row into dest; an expired row is sql.ErrNoRows.
+func (s *Store[V]) queryRow(ctx context.Context, columns string, dest ...any) error {
+	query := s.rebind(`SELECT ` + columns + ` FROM ` + s.table + ` WHERE name = ? AND (expires_at = 0 OR expires_at > ?)`)
+	err := s.db.QueryRowContext(ctx, query, s.name, s.now().UnixNano()).Scan(dest...)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return err
+}
+
+// rebind rewrites the ? placeholders of query for the dialect.
+func (s *Store[V]) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cache "github.com/soulteary/cache-kit"
+	_ "modernc.org/sqlite"
+)
+
+type user struct {
+	ID   string
+	Name string
+}
+
+func openStore(t *testing.T) (*sql.DB, *Store[user]) {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := New[user](db, "users", SQLite)
+	if err := store.CreateTable(context.Background()); err != nil {
+		t.Fatalf("CreateTable error: %v", err)
+	}
+	return db, store
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	_, store := openStore(t)
+	ctx := context.Background()
+
+	if ok, err := store.ExistsCtx(ctx); err != nil || ok {
+		t.Errorf("Expected no dataset, got %v, %v", ok, err)
+	}
+	if values, err := store.GetCtx(ctx); err != nil || len(values) != 0 {
+		t.Errorf("Expected an empty dataset, got %v, %v", values, err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != -2 {
+		t.Errorf("Expected TTL -2 for a missing dataset, got %v", ttl)
+	}
+
+	for i, name := range []string{"a", "b"} {
+		if err := store.SetCtx(ctx, []user{{ID: "1", Name: name}}); err != nil {
+			t.Fatalf("SetCtx error: %v", err)
+		}
+		if version, _ := store.GetVersionCtx(ctx); version != int64(i+1) {
+			t.Errorf("Expected version %d, got %d", i+1, version)
+		}
+	}
+	values, err := store.GetCtx(ctx)
+	if err != nil || len(values) != 1 || values[0].Name != "b" {
+		t.Errorf("Expected the last dataset, got %v, %v", values, err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != -1 {
+		t.Errorf("Expected TTL -1 without expiry, got %v", ttl)
+	}
+	m, ok, err := store.Metadata(ctx)
+	if err != nil || !ok || m.Version != 2 || len(m.Hash) != 64 || m.Size == 0 || m.UpdatedAt.IsZero() {
+		t.Errorf("Unexpected metadata %+v, %v, %v", m, ok, err)
+	}
+
+	if err := store.ClearCtx(ctx); err != nil {
+		t.Fatalf("ClearCtx error: %v", err)
+	}
+	if version, _ := store.GetVersionCtx(ctx); version != 0 {
+		t.Errorf("Expected version 0 after ClearCtx, got %d", version)
+	}
+}
+
+func TestStore_SharedTable(t *testing.T) {
+	db, users := openStore(t)
+	ctx := context.Background()
+	other := New[user](db, "admins", SQLite)
+
+	if err := users.SetCtx(ctx, []user{{ID: "1"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if ok, _ := other.ExistsCtx(ctx); ok {
+		t.Error("Expected datasets sharing the table to be separate")
+	}
+}
+
+func TestStore_TTL(t *testing.T) {
+	_, store := openStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	store.WithTTL(time.Minute).now = func() time.Time { return now }
+
+	if err := store.SetCtx(ctx, []user{{ID: "1"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != time.Minute {
+		t.Errorf("Expected TTL 1m, got %v", ttl)
+	}
+	now = now.Add(2 * time.Minute)
+	if ok, _ := store.ExistsCtx(ctx); ok {
+		t.Error("Expected an expired dataset to read as missing")
+	}
+	// Writing again replaces the expired row.
+	if err := store.SetCtx(ctx, []user{{ID: "2"}}); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if values, _ := store.GetCtx(ctx); len(values) != 1 || values[0].ID != "2" {
+		t.Errorf("Expected the new dataset, got %v", values)
+	}
+}
+
+func TestStore_SetOptions(t *testing.T) {
+	_, store := openStore(t)
+	ctx := context.Background()
+	now := time.Now()
+	store.WithTTL(time.Minute).now = func() time.Time { return now }
+
+	if err := store.SetCtx(ctx, []user{{ID: "1"}}, cache.WithTTL(time.Hour)); err != nil {
+		t.Fatalf("SetCtx error: %v", err)
+	}
+	if ttl, _ := store.TTLCtx(ctx); ttl != time.Hour {
+		t.Errorf("Expected WithTTL to override the store TTL, got %v", ttl)
+	}
+	for _, opt := range []cache.SetOption{cache.WithIfVersion(1), cache.WithNoVersionBump(), cache.WithCodec(cache.JSONCodec{})} {
+		if err := store.SetCtx(ctx, []user{{ID: "2"}}, opt); !errors.Is(err, cache.ErrUnsupportedSetOption) {
+			t.Errorf("Expected ErrUnsupportedSetOption, got %v", err)
+		}
+	}
+	if values, _ := store.GetCtx(ctx); len(values) != 1 || values[0].ID != "1" {
+		t.Errorf("Expected rejected writes to leave the dataset, got %v", values)
+	}
+}
+
+func TestStore_HybridCache(t *testing.T) {
+	_, store := openStore(t)
+	config := cache.DefaultConfig[user]().WithPrimaryKey(func(u user) string { return u.ID })
+	writer := cache.NewHybridCacheWithStore(config, store)
+	reader := cache.NewHybridCacheWithStore(config, store)
+
+	if err := writer.Set([]user{{ID: "1", Name: "a"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := reader.LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if u, ok := reader.Get("1"); !ok || u.Name != "a" || reader.LoadedVersion() != 1 {
+		t.Errorf("Expected the reader to load version 1, got %+v, %v, %d", u, ok, reader.LoadedVersion())
+	}
+}
+
+func TestRebind(t *testing.T) {
+	s := New[user](nil, "users", Postgres)
+	if got := s.rebind("a = ? AND b = ?"); got != "a = $1 AND b = $2" {
+		t.Errorf("Unexpected Postgres query %q", got)
+	}
+	s.dialect = SQLite
+	if got := s.rebind("a = ?"); got != "a = ?" {
+		t.Errorf("Unexpected SQLite query %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// staleKeySuffix is appended to the data key to name the stale backup copy.
+const staleKeySuffix = ":stale"
+
+// staleKey returns the key of the stale backup copy for this cache.
+func (c *RedisCache[V]) staleKey() string {
+	return c.key + staleKeySuffix
+}
+
+// queueStale queues storing values as the stale backup copy, a single payload in every
+// storage mode.
+func (c *RedisCache[V]) queueStale(ctx context.Context, pipe redis.Pipeliner, values []V) error {
+	data, err := c.codec().Marshal(values)
+	if err != nil {
+		return err
+	}
+	pipe.Set(ctx, c.staleKey(), data, c.config.StaleTTL)
+	return nil
+}
+
+// GetStale returns the backup copy written by the last Set, SetWithTTL or SetIfVersion while
+// RedisConfig.StaleTTL was set, which outlives the primary key. Use it to deliberately serve
+// old data when the primary key expired and the origin cannot rebuild it. Returns
+// ErrRemoteEmpty if there is no backup copy. The copy is not updated by per-item writes
+// (SetItem, DeleteItem) and has no version.
+func (c *RedisCache[V]) GetStale() ([]V, error) {
+	return c.GetStaleCtx(context.Background())
+}
+
+// GetStaleCtx is like GetStale but uses ctx for the Redis calls.
+func (c *RedisCache[V]) GetStaleCtx(ctx context.Context) (_ []V, err error) {
+	start, size := time.Now(), 0
+	defer func() { err = c.observe(OpGetStale, start, size, err) }()
+
+	if err := c.admit(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	data, err := c.client.Get(ctx, c.staleKey()).Bytes()
+	if err == redis.Nil {
+		return nil, ErrRemoteEmpty
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale copy: %w", err)
+	}
+	size = len(data)
+	return c.decode(data)
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRedisCache_GetStale(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().
+		WithTTL(time.Minute).
+		WithStaleTTL(time.Hour))
+
+	if _, err := cache.GetStale(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty before the first write, got %v", err)
+	}
+
+	if err := cache.Set([]TestUser{{ID: "1", Name: "Alice"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if ttl := mr.TTL("cache:data" + staleKeySuffix); ttl != time.Hour {
+		t.Errorf("Expected stale TTL 1h, got %v", ttl)
+	}
+
+	// The primary key expires, the backup copy remains.
+	mr.FastForward(2 * time.Minute)
+	if exists, _ := cache.Exists(); exists {
+		t.Fatal("Expected primary key to have expired")
+	}
+	values, err := cache.GetStale()
+	if err != nil {
+		t.Fatalf("GetStale error: %v", err)
+	}
+	if len(values) != 1 || values[0].Name != "Alice" {
+		t.Errorf("Expected stale Alice, got %+v", values)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if _, err := cache.GetStale(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected Clear to remove the stale copy, got %v", err)
+	}
+}
+
+func TestRedisCache_GetStaleDisabled(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig())
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if mr.Exists("cache:data" + staleKeySuffix) {
+		t.Error("Expected no stale copy without StaleTTL")
+	}
+	if _, err := cache.GetStale(); !errors.Is(err, ErrRemoteEmpty) {
+		t.Errorf("Expected ErrRemoteEmpty, got %v", err)
+	}
+}
+
+func TestRedisCache_GetStaleHashStorage(t *testing.T) {
+	cache, _ := newHashCache(t, DefaultRedisConfig().WithStaleTTL(time.Hour))
+
+	if ok, err := cache.SetIfVersion([]TestUser{{ID: "2"}, {ID: "1"}}, 0); err != nil || !ok {
+		t.Fatalf("SetIfVersion failed: %v (%v)", ok, err)
+	}
+	values, err := cache.GetStale()
+	if err != nil || len(values) != 2 {
+		t.Errorf("Expected stale copy of 2 values, got %+v (%v)", values, err)
+	}
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// staleWhileRevalidate is the state of HybridCache.WithStaleWhileRevalidate.
+type staleWhileRevalidate struct {
+	interval   time.Duration
+	lastCheck  atomic.Int64 // unix nanoseconds of the last version check
+	refreshing atomic.Bool  // a background check or reload is running
+}
+
+// WithStaleWhileRevalidate makes reads (GetAll, GetByIndex) serve memory immediately and,
+// at most once per interval, check in the background whether the Redis version moved past
+// the one memory holds (see LoadedVersion), reloading with LoadFromRedis if so. A read
+// never waits for Redis: the reads right after another instance's write see the previous
+// dataset until the reload lands.
+func (c *HybridCache[V]) WithStaleWhileRevalidate(interval time.Duration) *HybridCache[V] {
+	c.swr = &staleWhileRevalidate{interval: interval}
+	return c
+}
+
+// revalidate starts a background version check if one is due.
+func (c *HybridCache[V]) revalidate() {
+	s := c.swr
+	if s == nil {
+		return
+	}
+	now := time.Now().UnixNano()
+	if now-s.lastCheck.Load() < int64(s.interval) || !s.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	s.lastCheck.Store(now)
+	go func() {
+		defer s.refreshing.Store(false)
+		version, err := c.remote.GetVersionCtx(context.Background())
+		if err == nil && version != c.version.Load() {
+			err = c.LoadFromRedis()
+		}
+		c.reportError(TaskRevalidate, err)
+	}()
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHybridCache_StaleWhileRevalidate(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithStaleWhileRevalidate(time.Millisecond)
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if cache.LoadedVersion() != 1 {
+		t.Errorf("Expected the written version to be recorded, got %d", cache.LoadedVersion())
+	}
+
+	if err := writer.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	// The first read after the remote write is served from memory, then revalidated.
+	time.Sleep(2 * time.Millisecond)
+	if got := idsOf(cache.GetAll()); got != "1" {
+		t.Errorf("Expected the stale copy to be served immediately, got %s", got)
+	}
+	waitFor(t, func() bool { return idsOf(cache.GetAll()) == "2" })
+	if cache.LoadedVersion() != 2 {
+		t.Errorf("Expected the reloaded version to be recorded, got %d", cache.LoadedVersion())
+	}
+}
+
+func TestHybridCache_StaleWhileRevalidateInterval(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig().WithMetricsHook(hook)).
+		WithStaleWhileRevalidate(time.Hour)
+
+	count := func() int {
+		hook.mu.Lock()
+		defer hook.mu.Unlock()
+		return len(hook.ops)
+	}
+
+	cache.GetAll()
+	waitFor(t, func() bool { return !cache.swr.refreshing.Load() && count() > 0 })
+	checks := count()
+	for i := 0; i < 10; i++ {
+		cache.GetAll()
+	}
+	if got := count(); got != checks {
+		t.Errorf("Expected at most one check per interval, got %d operations", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import "time"
+
+// SyncRetryConfig configures HybridCache.WithSyncRetry.
+type SyncRetryConfig struct {
+	// MaxRetries is how often a failed Redis write is retried before it is given up.
+	// Default (0): 5
+	MaxRetries int
+	// Backoff is the wait before the first retry, doubled for each further one.
+	// Default (0): 100 milliseconds
+	Backoff time.Duration
+}
+
+// WithSyncRetry keeps a brief Redis outage from leaving memory and Redis divergent: when
+// the Redis write of a synchronous Set or SyncToRedis fails, the error is still returned
+// and the dataset is retried in the background with backoff until a retry succeeds,
+// MaxRetries is exhausted, or a newer write supersedes it. Failed retries reach the
+// WithErrorHandler handler as TaskSyncRetry. Write-behind Sets retry on their own (see
+// WriteBehindConfig). Close writes a pending retry one last time.
+func (c *HybridCache[V]) WithSyncRetry(config SyncRetryConfig) *HybridCache[V] {
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 5
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = 100 * time.Millisecond
+	}
+	if c.retry != nil {
+		_ = c.retry.close()
+	}
+	// The worker waits FlushDelay before its first attempt, which is the first retry.
+	c.retry = startWriteBehind(WriteBehindConfig{
+		FlushDelay:   config.Backoff,
+		MaxRetries:   config.MaxRetries - 1,
+		RetryBackoff: 2 * config.Backoff,
+	}, c.writeRedis, func(err error) { c.reportError(TaskSyncRetry, err) })
+	return c
+}
+
+// writeRedisRetrying writes values like writeRedis and, under WithSyncRetry, queues them
+// for retry if the write fails. The pending retry is dropped first, and the write holds
+// the retry lock, so a retry of an older dataset never lands after values.
+func (c *HybridCache[V]) writeRedisRetrying(values []V) error {
+	if c.retry == nil {
+		return c.writeRedis(values)
+	}
+	err := c.retry.discard(func() error { return c.writeRedis(values) })
+	if err != nil {
+		c.retry.enqueue(values)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHybridCache_SyncRetry(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	errs := &taskErrors{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithErrorHandler(errs.handle).
+		WithSyncRetry(SyncRetryConfig{MaxRetries: 100, Backoff: time.Millisecond})
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err == nil {
+		t.Fatal("Expected Set to return the Redis error")
+	}
+	waitFor(t, func() bool { return errs.count(TaskSyncRetry) > 0 })
+	mr.SetError("")
+	waitFor(t, func() bool {
+		remote, _ := cache.Redis().Get()
+		return idsOf(remote) == "1"
+	})
+}
+
+func TestHybridCache_SyncRetrySuperseded(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithSyncRetry(SyncRetryConfig{Backoff: time.Hour})
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err == nil {
+		t.Fatal("Expected Set to return the Redis error")
+	}
+	mr.SetError("")
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	// Close writes any pending retry: none is left, so Redis keeps the newer dataset.
+	if err := cache.retry.close(); err != nil {
+		t.Fatalf("close error: %v", err)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "2" {
+		t.Errorf("Expected the newer Set to supersede the retry, got %s", idsOf(remote))
+	}
+}
+
+func TestHybridCache_SyncRetryGivesUp(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	errs := &taskErrors{}
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithErrorHandler(errs.handle).
+		WithSyncRetry(SyncRetryConfig{MaxRetries: 2, Backoff: time.Millisecond})
+
+	mr.SetError("LOADING")
+	_ = cache.Set([]TestUser{{ID: "1"}})
+	waitFor(t, func() bool { return errs.count(TaskSyncRetry) == 2 })
+	time.Sleep(20 * time.Millisecond)
+	if got := errs.count(TaskSyncRetry); got != 2 {
+		t.Errorf("Expected 2 retries, got %d", got)
+	}
+}
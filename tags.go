@@ -0,0 +1,135 @@
+package cache
+
+// InvalidateByTag removes every entry carrying the given tag and returns the number removed.
+// Tags come from Config.TagsFunc; without it, no entry is tagged and this is a no-op.
+// Indexes and the cache hash are updated to reflect the removal.
+func (c *MemoryCache[V]) InvalidateByTag(tag string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pks, exists := c.tags[tag]
+	if !exists || len(pks) == 0 {
+		return 0
+	}
+
+	removed := make(map[string]struct{}, len(pks))
+	for pk := range pks {
+		removed[pk] = struct{}{}
+	}
+	return c.removeEntries(removed)
+}
+
+// TagNames returns the tags currently carried by at least one entry.
+func (c *MemoryCache[V]) TagNames() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.tags))
+	for tag := range c.tags {
+		names = append(names, tag)
+	}
+	return names
+}
+
+// tag records the tags of v for pk. The caller must hold the write lock.
+func (c *MemoryCache[V]) tag(pk string, v V) {
+	if c.config.TagsFunc == nil {
+		return
+	}
+	for _, t := range c.config.TagsFunc(v) {
+		if t == "" {
+			continue
+		}
+		set, exists := c.tags[t]
+		if !exists {
+			set = make(map[string]struct{})
+			c.tags[t] = set
+		}
+		set[pk] = struct{}{}
+	}
+}
+
+// untag removes pk from all tags of v, its stored value. The caller must hold the write lock.
+func (c *MemoryCache[V]) untag(pk string, v V) {
+	if c.config.TagsFunc == nil {
+		return
+	}
+	for _, t := range c.config.TagsFunc(v) {
+		if set, ok := c.tags[t]; ok {
+			delete(set, pk)
+			if len(set) == 0 {
+				delete(c.tags, t)
+			}
+		}
+	}
+}
+
+// removeEntries deletes the given primary keys from storage, order, indexes and tags,
+// then recalculates the hash. Returns the number of entries removed.
+// Storage and indexes are copied before modification so views and snapshots stay unchanged.
+// The caller must hold the write lock.
+func (c *MemoryCache[V]) removeEntries(pks map[string]struct{}) int {
+	previous := c.watchedValues()
+	c.cloneStorage()
+	c.cloneIndexes()
+	removed := 0
+	for pk := range pks {
+		if c.deleteLocked(pk) {
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0
+	}
+
+	c.pruneOrder(false)
+	c.setHash(c.calculateHash())
+	c.updatedAt = c.now()
+	c.revision++
+	c.retainSnapshot()
+	c.notifyWatchers(previous)
+	return removed
+}
+
+// deleteLocked removes pk from storage, indexes, tags and generations, leaving c.order to
+// pruneOrder. Storage and indexes must already be private copies (cloneStorage, cloneIndexes).
+// Reports whether pk was present. The caller must hold the write lock.
+func (c *MemoryCache[V]) deleteLocked(pk string) bool {
+	v, exists := c.value(pk)
+	if !exists {
+		return false
+	}
+	c.unindex(pk, v)
+	c.untag(pk, v)
+	delete(c.seen, pk)
+	if c.arena != nil {
+		delete(c.arena.spans, pk)
+	} else {
+		delete(c.data, pk)
+	}
+	return true
+}
+
+// pruneOrder replaces c.order with a copy holding only stored keys. With dedupe, repeated
+// keys (re-added after a deleteLocked) keep their first position.
+// The caller must hold the write lock.
+func (c *MemoryCache[V]) pruneOrder(dedupe bool) {
+	var kept map[string]struct{}
+	if dedupe {
+		kept = make(map[string]struct{}, len(c.order))
+	}
+	order := make([]string, 0, len(c.order))
+	for _, pk := range c.order {
+		if !c.contains(pk) {
+			continue
+		}
+		if dedupe {
+			if _, dup := kept[pk]; dup {
+				continue
+			}
+			kept[pk] = struct{}{}
+		}
+		order = append(order, pk)
+	}
+	c.order = order
+}
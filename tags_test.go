@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestTenantUser is a sample tagged type for testing
+type TestTenantUser struct {
+	ID     string
+	Email  string
+	Tenant string
+}
+
+func TestMemoryCache_InvalidateByTag(t *testing.T) {
+	for _, arena := range []bool{false, true} {
+		config := DefaultConfig[TestTenantUser]().
+			WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+			WithTagsFunc(func(u TestTenantUser) []string { return []string{"tenant:" + u.Tenant, "all"} })
+		if arena {
+			config.WithArenaStorage(nil)
+		}
+		cache := NewMultiIndexCache(config)
+		cache.AddIndex("email", func(u TestTenantUser) string { return u.Email })
+		cache.Set([]TestTenantUser{
+			{ID: "1", Email: "a@example.com", Tenant: "acme"},
+			{ID: "2", Email: "b@example.com", Tenant: "globex"},
+			{ID: "3", Email: "c@example.com", Tenant: "acme"},
+		})
+		hashBefore := cache.GetHash()
+
+		if n := cache.InvalidateByTag("tenant:acme"); n != 2 {
+			t.Errorf("arena=%v: expected 2 removed, got %d", arena, n)
+		}
+		if cache.Len() != 1 {
+			t.Errorf("arena=%v: expected 1 item left, got %d", arena, cache.Len())
+		}
+		if _, ok := cache.Get("1"); ok {
+			t.Errorf("arena=%v: expected entry 1 removed", arena)
+		}
+		if _, ok := cache.GetByIndex("email", "c@example.com"); ok {
+			t.Errorf("arena=%v: expected index entry for 3 removed", arena)
+		}
+		if _, ok := cache.GetByIndex("email", "b@example.com"); !ok {
+			t.Errorf("arena=%v: expected entry 2 still indexed", arena)
+		}
+		if all := cache.GetAll(); len(all) != 1 || all[0].ID != "2" {
+			t.Errorf("arena=%v: expected only entry 2 left, got %+v", arena, all)
+		}
+		if cache.GetHash() == hashBefore {
+			t.Errorf("arena=%v: expected hash to change after invalidation", arena)
+		}
+
+		// Tag no longer exists
+		if n := cache.InvalidateByTag("tenant:acme"); n != 0 {
+			t.Errorf("arena=%v: expected 0 removed on second call, got %d", arena, n)
+		}
+	}
+}
+
+func TestMemoryCache_InvalidateByTagUnknown(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{"tenant:" + u.Tenant, "all"} })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}})
+
+	if n := cache.InvalidateByTag("missing"); n != 0 {
+		t.Errorf("Expected 0 removed, got %d", n)
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Expected 1 item, got %d", cache.Len())
+	}
+}
+
+func TestMemoryCache_InvalidateByTagWithoutTagsFunc(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig())
+	cache.Set([]TestUser{{ID: "1"}})
+
+	if n := cache.InvalidateByTag("any"); n != 0 {
+		t.Errorf("Expected 0 removed without TagsFunc, got %d", n)
+	}
+	if len(cache.TagNames()) != 0 {
+		t.Error("Expected no tags without TagsFunc")
+	}
+}
+
+func TestMemoryCache_TagsReplacedOnDuplicateKey(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{"tenant:" + u.Tenant, "all"} })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{
+		{ID: "1", Tenant: "acme"},
+		{ID: "1", Tenant: "globex"},
+	})
+
+	if n := cache.InvalidateByTag("tenant:acme"); n != 0 {
+		t.Errorf("Expected stale tag of replaced value to be dropped, removed %d", n)
+	}
+	if n := cache.InvalidateByTag("tenant:globex"); n != 1 {
+		t.Errorf("Expected 1 removed by current tag, got %d", n)
+	}
+}
+
+func TestMemoryCache_TagNames(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{"tenant:" + u.Tenant, "all"} })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}, {ID: "2", Tenant: "globex"}})
+
+	names := cache.TagNames()
+	sort.Strings(names)
+	want := []string{"all", "tenant:acme", "tenant:globex"}
+	if len(names) != len(want) {
+		t.Fatalf("Expected tags %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Expected tags %v, got %v", want, names)
+		}
+	}
+
+	cache.Clear()
+	if len(cache.TagNames()) != 0 {
+		t.Error("Expected no tags after Clear")
+	}
+}
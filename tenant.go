@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrInvalidTenant is returned by Tenants.Tenant for an empty tenant ID or one containing
+// ':', which could make the keys of two tenants collide.
+var ErrInvalidTenant = errors.New("cache-kit: tenant ID must be non-empty and must not contain ':'")
+
+// Tenants gives every tenant of a SaaS deployment its own HybridCache behind one API:
+//
+//	tenants := cache.NewTenants[User](memoryConfig, client, cache.DefaultRedisConfig().WithKeyPrefix("users:"))
+//	u, ok := tenants.ForTenant("acme").GetByIndex("email", email)
+//
+// A tenant's cache is created on first use, with its own MemoryCache (built from the
+// shared memory configuration, indexes included) and its own Redis keys: the tenant ID is
+// appended to RedisConfig.KeyPrefix ("users:acme:"), or set as the Tenant of a
+// SegmentedKeys KeyBuilder ("app:prod:acme:users:data"), and appended to
+// RedisConfig.InvalidationChannel, if set. It is safe for concurrent use.
+type Tenants[V any] struct {
+	memoryConfig *Config[V]
+	client       redis.UniversalClient
+	redisConfig  *RedisConfig
+	setup        func(tenant string, c *HybridCache[V])
+
+	mu     sync.RWMutex
+	caches map[string]*HybridCache[V]
+}
+
+// NewTenants creates an empty tenant set; caches are created like NewHybridCache would
+// from memoryConfig, client and a per-tenant copy of redisConfig (default if nil).
+// Panics if redisConfig has a KeyBuilder other than a SegmentedKeys without Tenant, which
+// could not give every tenant its own keys.
+func NewTenants[V any](memoryConfig *Config[V], client redis.UniversalClient, redisConfig *RedisConfig) *Tenants[V] {
+	if redisConfig == nil {
+		redisConfig = DefaultRedisConfig()
+	}
+	if builder := redisConfig.KeyBuilder; builder != nil {
+		if keys, ok := builder.(SegmentedKeys); !ok || keys.Tenant != "" {
+			panic("cache-kit: Tenants requires RedisConfig.KeyPrefix or a SegmentedKeys KeyBuilder without Tenant")
+		}
+	}
+	return &Tenants[V]{
+		memoryConfig: memoryConfig,
+		client:       client,
+		redisConfig:  redisConfig,
+		caches:       make(map[string]*HybridCache[V]),
+	}
+}
+
+// WithSetup sets a function configuring every new tenant cache before it is returned,
+// e.g. to add a loader or a write policy. Caches created earlier are not affected. It runs
+// under the lock of t, so it must not call t.
+func (t *Tenants[V]) WithSetup(setup func(tenant string, c *HybridCache[V])) *Tenants[V] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setup = setup
+	return t
+}
+
+// Tenant returns the cache of tenant id, creating it on first use, or ErrInvalidTenant.
+func (t *Tenants[V]) Tenant(id string) (*HybridCache[V], error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTenant, id)
+	}
+	t.mu.RLock()
+	c, ok := t.caches[id]
+	t.mu.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c, ok := t.caches[id]; ok {
+		return c, nil
+	}
+	config := *t.redisConfig
+	if keys, ok := config.KeyBuilder.(SegmentedKeys); ok {
+		keys.Tenant = id
+		config.KeyBuilder = keys
+	} else {
+		config.KeyPrefix += id + ":"
+	}
+	if config.InvalidationChannel != "" {
+		config.InvalidationChannel += ":" + id
+	}
+	if _, err := redisCacheKeys(&config); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w", ErrInvalidTenant, id, err)
+	}
+	c = NewHybridCache[V](t.memoryConfig, t.client, &config)
+	if t.setup != nil {
+		t.setup(id, c)
+	}
+	t.caches[id] = c
+	return c, nil
+}
+
+// ForTenant is like Tenant but panics on an invalid ID, for IDs known to be valid (e.g.
+// checked when the tenant was provisioned).
+func (t *Tenants[V]) ForTenant(id string) *HybridCache[V] {
+	c, err := t.Tenant(id)
+	if err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+// IDs returns the tenants whose cache was created, sorted.
+func (t *Tenants[V]) IDs() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ids := make([]string, 0, len(t.caches))
+	for id := range t.caches {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Clear empties both tiers of tenant id (see HybridCache.Clear), leaving other tenants
+// untouched. A tenant without a cache yet has its Redis keys cleared all the same.
+func (t *Tenants[V]) Clear(ctx context.Context, id string) error {
+	c, err := t.Tenant(id)
+	if err != nil {
+		return err
+	}
+	return c.Clear(ctx)
+}
+
+// Stats returns the HybridStats of every tenant cache created so far, by tenant ID.
+func (t *Tenants[V]) Stats() map[string]HybridStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := make(map[string]HybridStats, len(t.caches))
+	for id, c := range t.caches {
+		stats[id] = c.Stats()
+	}
+	return stats
+}
+
+// Remove closes the cache of tenant id (see HybridCache.Close) and forgets it, e.g. when
+// the tenant is offboarded; its Redis keys are kept, use Clear first to drop them. The
+// next use creates a new cache.
+func (t *Tenants[V]) Remove(ctx context.Context, id string) error {
+	t.mu.Lock()
+	c, ok := t.caches[id]
+	delete(t.caches, id)
+	t.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return c.Close(ctx)
+}
+
+// Close closes and forgets every tenant cache, and returns their errors joined, each
+// naming the tenant.
+func (t *Tenants[V]) Close(ctx context.Context) error {
+	var errs []error
+	for _, id := range t.IDs() {
+		if err := t.Remove(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close tenant %q: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
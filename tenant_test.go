@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTenants_Isolation(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := userConfig().WithIndex("email", func(u TestUser) string { return u.Email })
+	tenants := NewTenants[TestUser](config, client, DefaultRedisConfig().WithKeyPrefix("users:"))
+
+	if err := tenants.ForTenant("acme").Set([]TestUser{{ID: "1", Email: "a@acme.test"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := tenants.ForTenant("globex").Set([]TestUser{{ID: "1", Email: "a@globex.test"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	if _, ok := tenants.ForTenant("acme").GetByIndex("email", "a@acme.test"); !ok {
+		t.Error("Expected acme user by index")
+	}
+	if _, ok := tenants.ForTenant("acme").GetByIndex("email", "a@globex.test"); ok {
+		t.Error("Expected globex user invisible to acme")
+	}
+	if !mr.Exists("users:acme:data") || !mr.Exists("users:globex:data") {
+		t.Errorf("Expected per-tenant keys, got %v", mr.Keys())
+	}
+	if tenants.ForTenant("acme") != tenants.ForTenant("acme") {
+		t.Error("Expected one cache per tenant")
+	}
+	if ids := tenants.IDs(); len(ids) != 2 || ids[0] != "acme" || ids[1] != "globex" {
+		t.Errorf("Expected sorted tenant IDs, got %v", ids)
+	}
+
+	if err := tenants.Clear(context.Background(), "acme"); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if mr.Exists("users:acme:data") || tenants.ForTenant("acme").Memory().Len() != 0 {
+		t.Error("Expected acme cleared")
+	}
+	if !mr.Exists("users:globex:data") || tenants.ForTenant("globex").Memory().Len() != 1 {
+		t.Error("Expected globex untouched")
+	}
+
+	stats := tenants.Stats()
+	if stats["acme"].MemoryHits != 1 || stats["acme"].MemoryMisses != 1 || stats["globex"].MemoryHits != 0 {
+		t.Errorf("Expected per-tenant stats, got %+v", stats)
+	}
+}
+
+func TestTenants_KeyBuilder(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	keys := SegmentedKeys{App: "app", Env: "prod", Dataset: "users"}
+	tenants := NewTenants[TestUser](userConfig(), client, DefaultRedisConfig().WithKeyBuilder(keys))
+
+	if err := tenants.ForTenant("acme").Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := tenants.ForTenant("globex").LoadFromRedis(); err != nil {
+		t.Fatalf("LoadFromRedis error: %v", err)
+	}
+	if n := tenants.ForTenant("globex").Memory().Len(); n != 0 {
+		t.Errorf("Expected globex not to see acme's data, got %d items", n)
+	}
+	if !mr.Exists("app:prod:acme:users:data") || mr.Exists("app:prod:users:data") {
+		t.Errorf("Expected keys under the tenant segment, got %v", mr.Keys())
+	}
+	if _, err := tenants.Tenant("a b"); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("Expected ErrInvalidTenant for an ID SegmentedKeys rejects, got %v", err)
+	}
+
+	for name, builder := range map[string]KeyBuilder{
+		"custom builder": fixedKeys{},
+		"tenant set":     SegmentedKeys{App: "app", Env: "prod", Tenant: "acme", Dataset: "users"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected NewTenants to panic")
+				}
+			}()
+			NewTenants[TestUser](userConfig(), client, DefaultRedisConfig().WithKeyBuilder(builder))
+		})
+	}
+}
+
+func TestTenants_InvalidID(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	tenants := NewTenants[TestUser](userConfig(), client, nil)
+
+	for _, id := range []string{"", "a:b"} {
+		if _, err := tenants.Tenant(id); !errors.Is(err, ErrInvalidTenant) {
+			t.Errorf("Expected ErrInvalidTenant for %q, got %v", id, err)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected ForTenant to panic on an invalid ID")
+		}
+	}()
+	tenants.ForTenant("")
+}
+
+func TestTenants_SetupAndRemove(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	tenants := NewTenants[TestUser](userConfig(), client, DefaultRedisConfig().WithInvalidationChannel("users")).
+		WithSetup(func(tenant string, c *HybridCache[TestUser]) { c.WithWritePolicy(WriteMemoryOnly) })
+
+	acme := tenants.ForTenant("acme")
+	if acme.WritePolicy() != WriteMemoryOnly {
+		t.Errorf("Expected setup applied, got %v", acme.WritePolicy())
+	}
+	if got := acme.Redis().config.InvalidationChannel; got != "users:acme" {
+		t.Errorf("Expected per-tenant invalidation channel, got %q", got)
+	}
+
+	if err := tenants.Remove(context.Background(), "acme"); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+	if tenants.ForTenant("acme") == acme {
+		t.Error("Expected a new cache after Remove")
+	}
+	if err := tenants.Close(context.Background()); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if len(tenants.IDs()) != 0 {
+		t.Errorf("Expected no tenants after Close, got %v", tenants.IDs())
+	}
+}
+
+// fixedKeys is a KeyBuilder naming the same keys whatever the tenant.
+type fixedKeys struct{}
+
+func (fixedKeys) DataKey() string     { return "fixed:data" }
+func (fixedKeys) VersionKey() string  { return "fixed:version" }
+func (fixedKeys) MetadataKey() string { return "fixed:meta" }
+func (fixedKeys) Prefix() string      { return "fixed:" }
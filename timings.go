@@ -0,0 +1,50 @@
+package cache
+
+import "time"
+
+// SetTimings reports how long each phase of a MemoryCache Set took.
+// Normalize and Validate are summed over all values; Index covers storing values
+// and updating indexes and tags; Sort and Hash cover the hash calculation.
+type SetTimings struct {
+	Normalize time.Duration
+	Validate  time.Duration
+	Index     time.Duration
+	Sort      time.Duration
+	Hash      time.Duration
+	Total     time.Duration
+
+	// Values is the number of values passed to Set.
+	Values int
+
+	// Stored is the number of values stored after validation and de-duplication.
+	Stored int
+}
+
+// LastSetTimings returns the phase timings of the most recent Set.
+// Timings are only measured when Config.SetTimingsFunc is set; otherwise the zero value is returned.
+func (c *MemoryCache[V]) LastSetTimings() SetTimings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.lastTimings
+}
+
+// phaseClock measures phase durations only when enabled, so untimed Sets do not pay for time.Now.
+type phaseClock struct {
+	enabled bool
+}
+
+// now returns the current time, or the zero time when disabled.
+func (p phaseClock) now() time.Time {
+	if !p.enabled {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// add accumulates the time elapsed since start into d when enabled.
+func (p phaseClock) add(d *time.Duration, start time.Time) {
+	if p.enabled {
+		*d += time.Since(start)
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCache_SetTimings(t *testing.T) {
+	var got []SetTimings
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithNormalizeFunc(func(u TestUser) TestUser { return u }).
+		WithValidateFunc(func(u TestUser) error { return nil }).
+		WithSortFunc(func(values []TestUser) []TestUser {
+			time.Sleep(5 * time.Millisecond)
+			return values
+		}).
+		WithSetTimingsFunc(func(timings SetTimings) { got = append(got, timings) })
+
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestUser{{ID: "1"}, {ID: "1"}, {ID: ""}})
+
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 timings callback, got %d", len(got))
+	}
+	timings := got[0]
+	if timings.Values != 3 {
+		t.Errorf("Expected 3 values, got %d", timings.Values)
+	}
+	if timings.Stored != 1 {
+		t.Errorf("Expected 1 stored, got %d", timings.Stored)
+	}
+	if timings.Sort < 5*time.Millisecond {
+		t.Errorf("Expected sort phase >= 5ms, got %v", timings.Sort)
+	}
+	if timings.Total < timings.Sort+timings.Hash {
+		t.Errorf("Expected total %v to cover sort and hash", timings.Total)
+	}
+	if cache.LastSetTimings() != timings {
+		t.Error("Expected LastSetTimings to match the callback")
+	}
+}
+
+func TestMemoryCache_SetTimingsCallbackCanUseCache(t *testing.T) {
+	var cache *MemoryCache[TestUser]
+	var length int
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithSetTimingsFunc(func(SetTimings) { length = cache.Len() })
+	cache = NewMultiIndexCache(config)
+
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}})
+	if length != 2 {
+		t.Errorf("Expected callback to read the new length 2, got %d", length)
+	}
+}
+
+func TestMemoryCache_SetTimingsDisabled(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+	cache.Set([]TestUser{{ID: "1"}})
+
+	if cache.LastSetTimings() != (SetTimings{}) {
+		t.Errorf("Expected zero timings when disabled, got %+v", cache.LastSetTimings())
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+// Upsert adds or replaces the given values without removing entries that are absent from values.
+// It is meant for pipelines that refresh a dataset in parts. Values are normalized and validated
+// like in Set, and every upserted entry is marked as seen in a new generation (see StaleEntries).
+// Storage and indexes are copied before modification, so the cost is proportional to the
+// cache size; prefer Set for full refreshes.
+// Panics if PrimaryKeyFunc is nil and len(values) > 0.
+func (c *MemoryCache[V]) Upsert(values []V) {
+	if len(values) > 0 && c.config.PrimaryKeyFunc == nil {
+		panic("cache-kit: MultiIndexCache requires PrimaryKeyFunc when setting non-empty data; set it via config.WithPrimaryKey()")
+	}
+
+	var skip skipped
+	defer c.logSkipped("Upsert", &skip) // after unlocking
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous := c.watchedValues()
+	c.cloneStorage()
+	c.cloneIndexes()
+	c.generation++
+
+	for _, v := range values {
+		if c.config.NormalizeFunc != nil {
+			v = c.config.NormalizeFunc(v)
+		}
+		if c.config.ValidateFunc != nil {
+			if err := c.config.ValidateFunc(v); err != nil {
+				skip.reject(err)
+				continue // Skip invalid values
+			}
+		}
+		if !c.storeLocked(v) {
+			skip.unkeyed++
+		}
+	}
+
+	c.setHash(c.calculateHash())
+	c.updatedAt = c.now()
+	c.refreshedAt = c.updatedAt
+	c.revision++
+	c.retainSnapshot()
+	c.notifyWatchers(previous)
+}
+
+// Generation returns the number of Set and Upsert calls applied so far.
+func (c *MemoryCache[V]) Generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generation
+}
+
+// StaleEntries returns, in insertion order, the entries that were not written by any of the
+// last olderThan Set or Upsert calls — e.g. records that stopped appearing in an upstream feed
+// that is ingested with Upsert. Values of olderThan below 1 are treated as 1.
+func (c *MemoryCache[V]) StaleEntries(olderThan int) []V {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var result []V
+	for _, pk := range c.order {
+		if c.isStale(pk, olderThan) {
+			if v, exists := c.value(pk); exists {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+// RemoveStale removes the entries StaleEntries(olderThan) would return and reports how many were removed.
+func (c *MemoryCache[V]) RemoveStale(olderThan int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stale := make(map[string]struct{})
+	for _, pk := range c.order {
+		if c.isStale(pk, olderThan) {
+			stale[pk] = struct{}{}
+		}
+	}
+	if len(stale) == 0 {
+		return 0
+	}
+	return c.removeEntries(stale)
+}
+
+// Delete removes the entry stored under pk and reports whether it existed.
+func (c *MemoryCache[V]) Delete(pk string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.removeEntries(map[string]struct{}{pk: {}}) == 1
+}
+
+// isStale reports whether pk was last seen at least olderThan generations ago.
+// The caller must hold the lock.
+func (c *MemoryCache[V]) isStale(pk string, olderThan int) bool {
+	if olderThan < 1 {
+		olderThan = 1
+	}
+	return c.generation-c.seen[pk] >= uint64(olderThan)
+}
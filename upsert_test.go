@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func idsOf(users []TestUser) string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestMemoryCache_Upsert(t *testing.T) {
+	for _, arena := range []bool{false, true} {
+		config := userConfig()
+		if arena {
+			config.WithArenaStorage(nil)
+		}
+		cache := NewMultiIndexCache(config)
+		cache.AddIndex("email", func(u TestUser) string { return u.Email })
+		cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+		snap := cache.Snapshot()
+		hash := cache.GetHash()
+
+		cache.Upsert([]TestUser{{ID: "2", Email: "b2@example.com"}, {ID: "3", Email: "c@example.com"}})
+
+		if got := idsOf(cache.GetAll()); got != "1,2,3" {
+			t.Errorf("arena=%v: expected 1,2,3 after upsert, got %s", arena, got)
+		}
+		if u, ok := cache.GetByIndex("email", "b2@example.com"); !ok || u.ID != "2" {
+			t.Errorf("arena=%v: expected new index key for 2", arena)
+		}
+		if _, ok := cache.GetByIndex("email", "b@example.com"); ok {
+			t.Errorf("arena=%v: expected old index key of replaced value removed", arena)
+		}
+		if cache.GetHash() == hash {
+			t.Errorf("arena=%v: expected hash to change", arena)
+		}
+
+		// Snapshots taken before the upsert are unaffected
+		if snap.Len() != 2 {
+			t.Errorf("arena=%v: expected snapshot length 2, got %d", arena, snap.Len())
+		}
+		if u, ok := snap.GetByIndex("email", "b@example.com"); !ok || u.Email != "b@example.com" {
+			t.Errorf("arena=%v: expected snapshot to keep old value, got %+v", arena, u)
+		}
+	}
+}
+
+func TestMemoryCache_SetDuplicateDropsStaleIndexKey(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig())
+	cache.AddIndex("email", func(u TestUser) string { return u.Email })
+	cache.Set([]TestUser{{ID: "1", Email: "old@example.com"}, {ID: "1", Email: "new@example.com"}})
+
+	if _, ok := cache.GetByIndex("email", "old@example.com"); ok {
+		t.Error("Expected index key of overwritten duplicate to be removed")
+	}
+	if _, ok := cache.GetByIndex("email", "new@example.com"); !ok {
+		t.Error("Expected index key of the last duplicate")
+	}
+}
+
+func TestMemoryCache_StaleEntries(t *testing.T) {
+	cache := NewMultiIndexCache(userConfig())
+	cache.Set([]TestUser{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+	if cache.Generation() != 1 {
+		t.Errorf("Expected generation 1, got %d", cache.Generation())
+	}
+	if stale := cache.StaleEntries(1); len(stale) != 0 {
+		t.Errorf("Expected no stale entries right after Set, got %v", idsOf(stale))
+	}
+
+	cache.Upsert([]TestUser{{ID: "1"}, {ID: "2"}})
+	cache.Upsert([]TestUser{{ID: "1"}})
+	if cache.Generation() != 3 {
+		t.Errorf("Expected generation 3, got %d", cache.Generation())
+	}
+
+	if got := idsOf(cache.StaleEntries(1)); got != "2,3" {
+		t.Errorf("Expected 2,3 stale for 1 generation, got %s", got)
+	}
+	if got := idsOf(cache.StaleEntries(2)); got != "3" {
+		t.Errorf("Expected 3 stale for 2 generations, got %s", got)
+	}
+	if got := idsOf(cache.StaleEntries(0)); got != "2,3" {
+		t.Errorf("Expected olderThan 0 treated as 1, got %s", got)
+	}
+	if got := cache.StaleEntries(3); len(got) != 0 {
+		t.Errorf("Expected nothing stale for 3 generations, got %s", idsOf(got))
+	}
+
+	if n := cache.RemoveStale(2); n != 1 {
+		t.Errorf("Expected 1 removed, got %d", n)
+	}
+	if got := idsOf(cache.GetAll()); got != "1,2" {
+		t.Errorf("Expected 1,2 left, got %s", got)
+	}
+	if n := cache.RemoveStale(5); n != 0 {
+		t.Errorf("Expected nothing removed, got %d", n)
+	}
+}
+
+func TestMemoryCache_UpsertSkipsInvalid(t *testing.T) {
+	config := DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }).
+		WithValidateFunc(func(u TestUser) error {
+			if u.Name == "" {
+				return errInvalidTestUser
+			}
+			return nil
+		}).
+		WithNormalizeFunc(func(u TestUser) TestUser {
+			u.Name = strings.TrimSpace(u.Name)
+			return u
+		})
+	cache := NewMultiIndexCache(config)
+	cache.Upsert([]TestUser{{ID: "1", Name: " A "}, {ID: "2", Name: "  "}})
+
+	if cache.Len() != 1 {
+		t.Errorf("Expected invalid value skipped, got %d items", cache.Len())
+	}
+	if u, _ := cache.Get("1"); u.Name != "A" {
+		t.Errorf("Expected normalized name, got %q", u.Name)
+	}
+}
+
+func TestMemoryCache_UpsertPanicsWithoutPrimaryKey(t *testing.T) {
+	cache := NewMultiIndexCache[TestUser](nil)
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic without PrimaryKeyFunc")
+		}
+	}()
+	cache.Upsert([]TestUser{{ID: "1"}})
+}
+
+var errInvalidTestUser = errors.New("invalid test user")
+
+func TestMemoryCache_Delete(t *testing.T) {
+	for _, arena := range []bool{false, true} {
+		config := userConfig()
+		if arena {
+			config.WithArenaStorage(nil)
+		}
+		cache := NewMultiIndexCache(config)
+		cache.AddIndex("email", func(u TestUser) string { return u.Email })
+		cache.Set([]TestUser{{ID: "1", Email: "a@example.com"}, {ID: "2", Email: "b@example.com"}})
+		hash := cache.GetHash()
+
+		if !cache.Delete("1") {
+			t.Error("Expected Delete to report an existing entry")
+		}
+		if cache.Delete("1") {
+			t.Error("Expected Delete to report a missing entry")
+		}
+		if got := idsOf(cache.GetAll()); got != "2" {
+			t.Errorf("arena=%v: expected 2, got %s", arena, got)
+		}
+		if _, ok := cache.GetByIndex("email", "a@example.com"); ok {
+			t.Errorf("arena=%v: expected the index entry to be removed", arena)
+		}
+		if cache.GetHash() == hash {
+			t.Errorf("arena=%v: expected the hash to change", arena)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BumpVersion increments the version without touching the dataset, so that instances
+// polling it (StartAutoRefresh, Watch, NeedsRefresh) reload, and returns the new version.
+// Under RedisConfig.VersionedEnvelope the data then disagrees with the version until the
+// next Set.
+func (c *RedisCache[V]) BumpVersion() (int64, error) {
+	return c.BumpVersionCtx(context.Background())
+}
+
+// BumpVersionCtx is like BumpVersion but uses ctx for the Redis call.
+func (c *RedisCache[V]) BumpVersionCtx(ctx context.Context) (version int64, err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpBumpVersion, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	version, err = c.client.Incr(ctx, c.versionKey()).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to bump version: %w", err)
+	}
+	return version, nil
+}
+
+// ResetVersion deletes the version key, leaving the dataset in place: GetVersion returns
+// 0 until the next Set, which stores version 1.
+func (c *RedisCache[V]) ResetVersion() error {
+	return c.ResetVersionCtx(context.Background())
+}
+
+// ResetVersionCtx is like ResetVersion but uses ctx for the Redis call.
+func (c *RedisCache[V]) ResetVersionCtx(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { err = c.observe(OpResetVersion, start, 0, err) }()
+
+	if err := c.admit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := c.getContext(ctx)
+	defer cancel()
+
+	if err := c.client.Del(ctx, c.versionKey()).Err(); err != nil {
+		return fmt.Errorf("failed to reset version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package cache
+
+import "testing"
+
+func TestRedisCache_BumpAndResetVersion(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	hook := &recordingHook{}
+	cache := NewRedisCache[TestUser](client, DefaultRedisConfig().WithMetricsHook(hook))
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	version, err := cache.BumpVersion()
+	if err != nil || version != 2 {
+		t.Fatalf("Expected version 2, got %d, %v", version, err)
+	}
+	if hook.last().op != OpBumpVersion {
+		t.Errorf("Expected OpBumpVersion, got %s", hook.last().op)
+	}
+	if values, _ := cache.Get(); len(values) != 1 {
+		t.Errorf("Expected BumpVersion to keep the dataset, got %v", values)
+	}
+
+	if err := cache.ResetVersion(); err != nil {
+		t.Fatalf("ResetVersion error: %v", err)
+	}
+	if version, _ := cache.GetVersion(); version != 0 {
+		t.Errorf("Expected version 0 after ResetVersion, got %d", version)
+	}
+	if exists, _ := cache.Exists(); !exists {
+		t.Error("Expected ResetVersion to keep the dataset")
+	}
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if version, _ := cache.GetVersion(); version != 1 {
+		t.Errorf("Expected the next Set to store version 1, got %d", version)
+	}
+}
+
+func TestRedisCache_BumpVersionNilClient(t *testing.T) {
+	cache := NewRedisCache[TestUser](nil, DefaultRedisConfig())
+	if _, err := cache.BumpVersion(); err != ErrNilClient {
+		t.Errorf("Expected ErrNilClient, got %v", err)
+	}
+	if err := cache.ResetVersion(); err != ErrNilClient {
+		t.Errorf("Expected ErrNilClient, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// VersionChange is a transition of the Redis dataset version reported by HybridCache.Watch.
+type VersionChange struct {
+	Old int64 // version before the change, 0 if the dataset did not exist
+	New int64 // version after the change, 0 if the dataset was cleared or expired
+}
+
+// Watch reports changes of the Redis dataset version, written by any instance, until ctx is
+// done, then closes the channel. The version is polled every RedisConfig.ChangePollInterval
+// (1s if unset) and also checked as soon as the cache's InvalidationSubscriber reports an
+// event, so with RedisConfig.InvalidationChannel or keyspace notifications changes surface
+// without waiting for the poll. Changes happening while the receiver is busy coalesce into
+// one event from the last reported version; read errors are skipped. Watch does not load
+// the new dataset: combine it with LoadFromRedis, or use StartAutoRefresh.
+func (c *HybridCache[V]) Watch(ctx context.Context) <-chan VersionChange {
+	changes := make(chan VersionChange)
+	var interval time.Duration
+	if c.redis != nil {
+		interval = c.redis.config.ChangePollInterval
+	}
+	if interval <= 0 {
+		interval = defaultChangePollInterval
+	}
+	last, err := c.remote.GetVersionCtx(ctx)
+	if err != nil {
+		last = 0
+	}
+
+	go func() {
+		defer close(changes)
+		var events <-chan InvalidationEvent
+		if c.redis != nil {
+			events = c.redis.InvalidationSubscriber().Events(ctx)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case _, ok := <-events:
+				if !ok {
+					events = nil // subscription failed; keep polling
+				}
+			}
+			version, err := c.remote.GetVersionCtx(ctx)
+			if err != nil || version == last {
+				continue
+			}
+			select {
+			case changes <- VersionChange{Old: last, New: version}:
+				last = version
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes
+}
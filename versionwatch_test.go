@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// nextChange returns the next change on changes, failing the test after a timeout.
+func nextChange(t *testing.T, changes <-chan VersionChange) VersionChange {
+	t.Helper()
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a version change")
+		return VersionChange{}
+	}
+}
+
+func TestHybridCache_WatchPolling(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().WithChangePollInterval(5 * time.Millisecond)
+	writer := NewRedisCache[TestUser](client, config)
+	cache := NewHybridCache[TestUser](userConfig(), client, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := cache.Watch(ctx)
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if change := nextChange(t, changes); change != (VersionChange{Old: 0, New: 1}) {
+		t.Errorf("Expected 0 -> 1, got %+v", change)
+	}
+	if err := writer.Clear(); err != nil {
+		t.Fatalf("Clear error: %v", err)
+	}
+	if change := nextChange(t, changes); change != (VersionChange{Old: 1, New: 0}) {
+		t.Errorf("Expected 1 -> 0 after Clear, got %+v", change)
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestHybridCache_WatchPubSub(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	config := DefaultRedisConfig().WithChangePollInterval(time.Hour).WithInvalidationChannel("users:changed")
+	writer := NewRedisCache[TestUser](client, config)
+	cache := NewHybridCache[TestUser](userConfig(), client, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := cache.Watch(ctx)
+	waitSubscribed(t, mr, "users:changed")
+
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	mr.Publish("users:changed", "1")
+	if change := nextChange(t, changes); change.New != 1 {
+		t.Errorf("Expected the event to surface version 1 without polling, got %+v", change)
+	}
+}
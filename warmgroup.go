@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// WarmFunc warms one cache, e.g. a HybridCache's Bootstrap or RefreshFromSource method.
+type WarmFunc func(ctx context.Context) error
+
+// WarmGroup warms several caches concurrently at startup, whatever their value types:
+//
+//	err := cache.NewWarmGroup(4).
+//		Add("users", users.Bootstrap).
+//		Add("plans", plans.RefreshFromSource).
+//		Run(ctx)
+type WarmGroup struct {
+	concurrency int
+	names       []string
+	warms       []WarmFunc
+}
+
+// NewWarmGroup creates a WarmGroup running at most concurrency warms at a time; values
+// below 1 run them all at once.
+func NewWarmGroup(concurrency int) *WarmGroup {
+	return &WarmGroup{concurrency: concurrency}
+}
+
+// Add registers warm under name, which identifies the cache in Run errors.
+func (g *WarmGroup) Add(name string, warm WarmFunc) *WarmGroup {
+	g.names = append(g.names, name)
+	g.warms = append(g.warms, warm)
+	return g
+}
+
+// Run calls every registered warm and waits for them. A failed warm does not stop the
+// others; once ctx is done, warms not started yet fail with ctx.Err().
+// Returns the errors of the failed warms joined in Add order, each naming the cache.
+func (g *WarmGroup) Run(ctx context.Context) error {
+	workers := g.concurrency
+	if workers < 1 || workers > len(g.warms) {
+		workers = len(g.warms)
+	}
+
+	errs := make([]error, len(g.warms))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for i := range next {
+				err := ctx.Err()
+				if err == nil {
+					err = g.warms[i](ctx)
+				}
+				if err != nil {
+					errs[i] = fmt.Errorf("failed to warm cache %q: %w", g.names[i], err)
+				}
+			}
+		})
+	}
+	for i := range g.warms {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
+}
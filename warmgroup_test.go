@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmGroup_Run(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	writer := NewRedisCache[TestUser](client, DefaultRedisConfig())
+	if err := writer.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	users := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig())
+
+	down := errors.New("origin down")
+	err := NewWarmGroup(2).
+		Add("users", users.Bootstrap).
+		Add("broken", func(context.Context) error { return down }).
+		Run(context.Background())
+	if !errors.Is(err, down) || !strings.Contains(err.Error(), `"broken"`) {
+		t.Errorf("Expected the failed warm to be reported by name, got %v", err)
+	}
+	if _, ok := users.Get("1"); !ok {
+		t.Error("Expected a failed warm not to stop the others")
+	}
+}
+
+func TestWarmGroup_Concurrency(t *testing.T) {
+	var running, peak, calls atomic.Int32
+	warm := func(context.Context) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		calls.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	g := NewWarmGroup(3)
+	for range 10 {
+		g.Add("cache", warm)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if calls.Load() != 10 {
+		t.Errorf("Expected 10 warms, got %d", calls.Load())
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("Expected up to 3 concurrent warms, got %d", p)
+	}
+
+	if err := NewWarmGroup(0).Run(context.Background()); err != nil {
+		t.Errorf("Expected an empty group to succeed, got %v", err)
+	}
+}
+
+func TestWarmGroup_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	err := NewWarmGroup(1).
+		Add("first", func(context.Context) error { calls.Add(1); cancel(); return nil }).
+		Add("second", func(context.Context) error { calls.Add(1); return nil }).
+		Run(ctx)
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), `"second"`) {
+		t.Errorf("Expected the unstarted warm to fail with ctx.Err(), got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 warm to run, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"reflect"
+	"sync"
+)
+
+// keyWatcher delivers changes of a single primary key.
+type keyWatcher[V any] struct {
+	ch   chan V
+	once sync.Once
+}
+
+// send delivers v without blocking. If the subscriber has not consumed the previous
+// notification yet, it is replaced so the channel always holds the latest value.
+func (w *keyWatcher[V]) send(v V) {
+	select {
+	case w.ch <- v:
+		return
+	default:
+	}
+	select {
+	case <-w.ch:
+	default:
+	}
+	select {
+	case w.ch <- v:
+	default:
+	}
+}
+
+// WatchKey subscribes to changes of the entry with the given primary key.
+// The returned channel receives the new value whenever a Set (or other mutation) adds the
+// entry or changes it (compared with reflect.DeepEqual), and the zero value when the entry
+// is removed. Notifications are coalesced: a slow reader only sees the latest value.
+// Call the returned function to unsubscribe; it closes the channel and is safe to call more than once.
+func (c *MemoryCache[V]) WatchKey(pk string) (<-chan V, func()) {
+	w := &keyWatcher[V]{ch: make(chan V, 1)}
+
+	c.mu.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[string]map[*keyWatcher[V]]struct{})
+	}
+	set, exists := c.watchers[pk]
+	if !exists {
+		set = make(map[*keyWatcher[V]]struct{})
+		c.watchers[pk] = set
+	}
+	set[w] = struct{}{}
+	c.mu.Unlock()
+
+	cancel := func() {
+		w.once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			if set, ok := c.watchers[pk]; ok {
+				delete(set, w)
+				if len(set) == 0 {
+					delete(c.watchers, pk)
+				}
+			}
+			close(w.ch)
+		})
+	}
+	return w.ch, cancel
+}
+
+// watchedValues captures the current values of all watched keys, for comparison after a mutation.
+// Returns nil when there are no watchers. The caller must hold the lock.
+func (c *MemoryCache[V]) watchedValues() map[string]V {
+	if len(c.watchers) == 0 {
+		return nil
+	}
+	previous := make(map[string]V, len(c.watchers))
+	for pk := range c.watchers {
+		if v, exists := c.value(pk); exists {
+			previous[pk] = v
+		}
+	}
+	return previous
+}
+
+// notifyWatchers compares watched keys against the values captured before a mutation
+// and notifies subscribers of every key that was added, changed or removed.
+// The caller must hold the write lock.
+func (c *MemoryCache[V]) notifyWatchers(previous map[string]V) {
+	for pk, set := range c.watchers {
+		old, had := previous[pk]
+		current, has := c.value(pk)
+
+		var notify bool
+		switch {
+		case has && !had:
+			notify = true
+		case has && had:
+			notify = !reflect.DeepEqual(old, current)
+		case had:
+			notify = true // removed; current is the zero value
+		}
+		if !notify {
+			continue
+		}
+		for w := range set {
+			w.send(current)
+		}
+	}
+}
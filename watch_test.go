@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+// receive waits briefly for a notification on ch.
+func receive[V any](t *testing.T, ch <-chan V) (V, bool) {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-time.After(100 * time.Millisecond):
+		var zero V
+		return zero, false
+	}
+}
+
+// expectNoNotification fails if ch delivers a value.
+func expectNoNotification[V any](t *testing.T, ch <-chan V) {
+	t.Helper()
+	select {
+	case v := <-ch:
+		t.Errorf("Expected no notification, got %+v", v)
+	default:
+	}
+}
+
+func TestMemoryCache_WatchKey(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+
+	ch, cancel := cache.WatchKey("1")
+	defer cancel()
+
+	// Added
+	cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "B"}})
+	v, ok := receive(t, ch)
+	if !ok || v.Name != "A" {
+		t.Errorf("Expected add notification with A, got %+v (ok=%v)", v, ok)
+	}
+
+	// Unchanged record, other record changed
+	cache.Set([]TestUser{{ID: "1", Name: "A"}, {ID: "2", Name: "C"}})
+	expectNoNotification(t, ch)
+
+	// Changed
+	cache.Set([]TestUser{{ID: "1", Name: "A2"}})
+	v, ok = receive(t, ch)
+	if !ok || v.Name != "A2" {
+		t.Errorf("Expected change notification with A2, got %+v (ok=%v)", v, ok)
+	}
+
+	// Removed
+	cache.Set([]TestUser{{ID: "2", Name: "C"}})
+	v, ok = receive(t, ch)
+	if !ok || v != (TestUser{}) {
+		t.Errorf("Expected zero value on removal, got %+v (ok=%v)", v, ok)
+	}
+}
+
+func TestMemoryCache_WatchKeyCoalesces(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+
+	ch, cancel := cache.WatchKey("1")
+	defer cancel()
+
+	cache.Set([]TestUser{{ID: "1", Name: "A"}})
+	cache.Set([]TestUser{{ID: "1", Name: "B"}})
+	cache.Set([]TestUser{{ID: "1", Name: "C"}})
+
+	v, _ := receive(t, ch)
+	if v.Name != "C" {
+		t.Errorf("Expected latest value C, got %+v", v)
+	}
+	expectNoNotification(t, ch)
+}
+
+func TestMemoryCache_WatchKeyCancel(t *testing.T) {
+	cache := NewMultiIndexCache(DefaultConfig[TestUser]().
+		WithPrimaryKey(func(u TestUser) string { return u.ID }))
+
+	ch, cancel := cache.WatchKey("1")
+	cancel()
+	cancel() // idempotent
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected channel closed after cancel")
+	}
+
+	// Must not panic sending to a closed channel
+	cache.Set([]TestUser{{ID: "1"}})
+	if len(cache.watchers) != 0 {
+		t.Errorf("Expected no watchers left, got %d", len(cache.watchers))
+	}
+}
+
+func TestMemoryCache_WatchKeyClearAndInvalidate(t *testing.T) {
+	config := DefaultConfig[TestTenantUser]().
+		WithPrimaryKey(func(u TestTenantUser) string { return u.ID }).
+		WithTagsFunc(func(u TestTenantUser) []string { return []string{u.Tenant} })
+	cache := NewMultiIndexCache(config)
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}})
+
+	ch, cancel := cache.WatchKey("1")
+	defer cancel()
+
+	cache.InvalidateByTag("acme")
+	if v, ok := receive(t, ch); !ok || v.ID != "" {
+		t.Errorf("Expected removal notification after InvalidateByTag, got %+v (ok=%v)", v, ok)
+	}
+
+	cache.Set([]TestTenantUser{{ID: "1", Tenant: "acme"}})
+	if v, _ := receive(t, ch); v.ID != "1" {
+		t.Errorf("Expected add notification, got %+v", v)
+	}
+
+	cache.Clear()
+	if v, ok := receive(t, ch); !ok || v.ID != "" {
+		t.Errorf("Expected removal notification after Clear, got %+v (ok=%v)", v, ok)
+	}
+}
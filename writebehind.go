@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// WriteBehindConfig configures HybridCache.WithWriteBehind.
+type WriteBehindConfig struct {
+	// FlushDelay is how long the worker waits after a Set before writing to Redis, so a
+	// burst of Sets costs one write of the latest dataset. Default (0): 50 milliseconds
+	FlushDelay time.Duration
+	// MaxRetries is how often a failed write is retried before the dataset is dropped
+	// (memory keeps it; the next Set or Flush writes again). Default (0): 3
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each further one.
+	// Default (0): 100 milliseconds
+	RetryBackoff time.Duration
+	// OnError, if set, is called from the worker with the error of every failed write and
+	// whether the dataset was dropped after the last retry.
+	OnError func(err error, dropped bool)
+}
+
+// writeBehind is the background writer of a HybridCache in write-behind mode. Sets replace
+// the whole dataset, so only the latest pending dataset is kept: queued writes coalesce.
+type writeBehind[V any] struct {
+	config WriteBehindConfig
+	write  func([]V) error
+	report func(error) // also receives every failed write
+
+	mu      sync.Mutex
+	pending []V
+	queued  bool   // pending holds a dataset not yet written
+	seq     uint64 // incremented by every enqueue, to tell whether pending was replaced
+	closed  bool   // set by close; enqueue refuses further datasets
+
+	// writeMu serializes Redis writes, so a dataset taken later is never overwritten by
+	// one taken earlier.
+	writeMu sync.Mutex
+
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	closing sync.Once
+}
+
+// newWriteBehind starts the worker of a write-behind queue writing with write and
+// reporting failed writes to report.
+func newWriteBehind[V any](config WriteBehindConfig, write func([]V) error, report func(error)) *writeBehind[V] {
+	if config.FlushDelay <= 0 {
+		config.FlushDelay = 50 * time.Millisecond
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 100 * time.Millisecond
+	}
+	return startWriteBehind(config, write, report)
+}
+
+// startWriteBehind is newWriteBehind without the defaults.
+func startWriteBehind[V any](config WriteBehindConfig, write func([]V) error, report func(error)) *writeBehind[V] {
+	w := &writeBehind[V]{
+		config:  config,
+		write:   write,
+		report:  report,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue replaces the pending dataset with a copy of values, so callers may reuse their
+// slice, and wakes the worker. Reports false, queueing nothing, once the queue is closed.
+func (w *writeBehind[V]) enqueue(values []V) bool {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return false
+	}
+	w.pending, w.queued = slices.Clone(values), true
+	w.seq++
+	w.mu.Unlock()
+	w.signal()
+	return true
+}
+
+// signal wakes the worker without blocking.
+func (w *writeBehind[V]) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// flush writes the pending dataset, if any, and returns its sequence number and write error.
+// A failed dataset is put back for the worker to retry, unless a newer one was queued.
+func (w *writeBehind[V]) flush() (uint64, error) {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	w.mu.Lock()
+	if !w.queued {
+		w.mu.Unlock()
+		return 0, nil
+	}
+	values, seq := w.pending, w.seq
+	w.pending, w.queued = nil, false
+	w.mu.Unlock()
+
+	err := w.write(values)
+	if err != nil {
+		w.mu.Lock()
+		if !w.queued {
+			w.pending, w.queued = slices.Clone(values), true
+		}
+		w.mu.Unlock()
+		w.signal()
+	}
+	return seq, err
+}
+
+// drop discards the pending dataset if it is still the one with sequence number seq.
+func (w *writeBehind[V]) drop(seq uint64) {
+	w.mu.Lock()
+	if w.queued && w.seq == seq {
+		w.pending, w.queued = nil, false
+	}
+	w.mu.Unlock()
+}
+
+// discard drops the pending dataset and runs fn while no write is in progress, so no
+// dataset queued before the call lands after fn.
+func (w *writeBehind[V]) discard(fn func() error) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	w.mu.Lock()
+	w.pending, w.queued = nil, false
+	w.mu.Unlock()
+	return fn()
+}
+
+// run is the worker loop: wait for a Set, let further Sets coalesce for FlushDelay, then
+// write with retries.
+func (w *writeBehind[V]) run() {
+	defer close(w.stopped)
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.wake:
+		}
+		if !w.sleep(w.config.FlushDelay) {
+			return
+		}
+		backoff := w.config.RetryBackoff
+		for attempt := 0; ; attempt++ {
+			seq, err := w.flush()
+			if err == nil {
+				break
+			}
+			dropped := attempt >= w.config.MaxRetries
+			if dropped {
+				w.drop(seq)
+			}
+			if w.config.OnError != nil {
+				w.config.OnError(err, dropped)
+			}
+			w.report(err)
+			if dropped || !w.sleep(backoff) {
+				break
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// sleep waits for d and reports false if the worker was stopped meanwhile.
+func (w *writeBehind[V]) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-w.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// close refuses further datasets, stops the worker, waits for it to exit and writes the
+// pending dataset, if any.
+func (w *writeBehind[V]) close() error {
+	w.mu.Lock()
+	w.closed = true
+	w.mu.Unlock()
+	w.closing.Do(func() { close(w.stop) })
+	<-w.stopped
+	_, err := w.flush()
+	return err
+}
+
+// WithWriteBehind switches Set to write-behind: memory is updated immediately and the Redis
+// write is queued to a background worker, taking Redis latency off the write path. Sets
+// arriving within FlushDelay coalesce into one write of the latest dataset, and failed
+// writes are retried with backoff (see WriteBehindConfig). Until the write lands, other
+// instances keep reading the previous dataset from Redis. Call Flush to write the pending
+// dataset now and Close on shutdown to drain the queue. Sets the WriteBack policy.
+func (c *HybridCache[V]) WithWriteBehind(config WriteBehindConfig) *HybridCache[V] {
+	if c.writeBehind != nil {
+		_ = c.writeBehind.close()
+	}
+	c.writeBehind = newWriteBehind(config, c.writeRedis, c.reportWriteBehind)
+	c.writePolicy = WriteBack
+	return c
+}
+
+// Flush writes the dataset queued by write-behind Sets to Redis now, waiting for a write
+// already in progress, and returns its error. It does nothing without write-behind or
+// pending writes. A failed dataset stays queued for the worker to retry.
+func (c *HybridCache[V]) Flush() error {
+	if c.writeBehind == nil {
+		return nil
+	}
+	_, err := c.writeBehind.flush()
+	return err
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// userConfig returns a memory config for TestUser keyed by ID.
+func userConfig() *Config[TestUser] {
+	return DefaultConfig[TestUser]().WithPrimaryKey(func(u TestUser) string { return u.ID })
+}
+
+// waitFor blocks until cond holds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHybridCache_WriteBehind(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour})
+	defer cache.Close(context.Background())
+
+	for _, id := range []string{"1", "2", "3"} {
+		if err := cache.Set([]TestUser{{ID: id}}); err != nil {
+			t.Fatalf("Set error: %v", err)
+		}
+	}
+	if got := idsOf(cache.GetAll()); got != "3" {
+		t.Errorf("Expected memory to hold 3 right away, got %s", got)
+	}
+	if exists, _ := cache.Redis().Exists(); exists {
+		t.Error("Expected the Redis write to be queued")
+	}
+
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	values, version, err := cache.Redis().GetWithVersion()
+	if err != nil || idsOf(values) != "3" || version != 1 {
+		t.Errorf("Expected the queued Sets to coalesce into 3 at version 1, got %s at %d (%v)", idsOf(values), version, err)
+	}
+	if err := cache.Flush(); err != nil {
+		t.Errorf("Expected an empty queue to flush without error, got %v", err)
+	}
+}
+
+func TestHybridCache_WriteBehindCopiesValues(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour})
+	defer cache.Close(context.Background())
+
+	buf := []TestUser{{ID: "1"}, {ID: "2"}}
+	if err := cache.Set(buf); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	buf[0], buf[1] = TestUser{ID: "3"}, TestUser{ID: "4"} // the caller reuses its buffer
+
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Flush error: %v", err)
+	}
+	if values, err := cache.Redis().Get(); err != nil || idsOf(values) != "1,2" {
+		t.Errorf("Expected the dataset as of Set in Redis, got %s (%v)", idsOf(values), err)
+	}
+}
+
+func TestHybridCache_WriteBehindRetry(t *testing.T) {
+	mr, client := setupMiniRedis(t)
+	var mu sync.Mutex
+	var failures, drops int
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{
+			FlushDelay:   time.Millisecond,
+			RetryBackoff: time.Millisecond,
+			MaxRetries:   1,
+			OnError: func(err error, dropped bool) {
+				mu.Lock()
+				defer mu.Unlock()
+				failures++
+				if dropped {
+					drops++
+				}
+			},
+		})
+	defer cache.Close(context.Background())
+
+	mr.SetError("LOADING")
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool { mu.Lock(); defer mu.Unlock(); return drops == 1 })
+	mu.Lock()
+	if failures != 2 {
+		t.Errorf("Expected one write and one retry before the drop, got %d failures", failures)
+	}
+	mu.Unlock()
+
+	mr.SetError("")
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool { values, _ := cache.Redis().Get(); return idsOf(values) == "2" })
+}
+
+func TestHybridCache_WriteBehindClose(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).
+		WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour})
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if values, _ := cache.Redis().Get(); idsOf(values) != "1" {
+		t.Errorf("Expected Close to drain the queue, got %s", idsOf(values))
+	}
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if values, _ := cache.Redis().Get(); idsOf(values) != "2" {
+		t.Errorf("Expected Sets after Close to write synchronously, got %s", idsOf(values))
+	}
+	if err := cache.Close(context.Background()); err != nil {
+		t.Errorf("Expected a second Close to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package cache
+
+// WritePolicy selects which tiers HybridCache.Set writes.
+type WritePolicy int
+
+const (
+	// WriteThrough updates memory, then writes Redis before Set returns (the default).
+	WriteThrough WritePolicy = iota
+
+	// WriteBack updates memory and queues the Redis write to the write-behind worker
+	// (see WithWriteBehind), with the default WriteBehindConfig unless WithWriteBehind
+	// configured one.
+	WriteBack
+
+	// WriteMemoryOnly updates memory only, for instances consuming a dataset another
+	// service owns; pair it with StartAutoRefresh or WithPubSubReload to pick up the
+	// owner's writes.
+	WriteMemoryOnly
+
+	// WriteRedisOnly writes Redis only and leaves memory to LoadFromRedis, for an owner
+	// that publishes a dataset without reading it locally.
+	WriteRedisOnly
+)
+
+// String returns the policy name, e.g. "write-through".
+func (p WritePolicy) String() string {
+	switch p {
+	case WriteThrough:
+		return "write-through"
+	case WriteBack:
+		return "write-back"
+	case WriteMemoryOnly:
+		return "memory-only"
+	case WriteRedisOnly:
+		return "redis-only"
+	default:
+		return "unknown"
+	}
+}
+
+// WithWritePolicy sets which tiers Set writes. SyncToRedis and RebuildRedis write Redis
+// under every policy, and LoadFromRedis always updates memory. Leaving WriteBack drains
+// and stops the write-behind worker.
+func (c *HybridCache[V]) WithWritePolicy(policy WritePolicy) *HybridCache[V] {
+	switch {
+	case policy == WriteBack && c.writeBehind == nil:
+		c.writeBehind = newWriteBehind(WriteBehindConfig{}, c.writeRedis, c.reportWriteBehind)
+	case policy != WriteBack && c.writeBehind != nil:
+		_ = c.writeBehind.close()
+		c.writeBehind = nil
+	}
+	c.writePolicy = policy
+	return c
+}
+
+// WritePolicy returns the policy set by WithWritePolicy (or WithWriteBehind).
+func (c *HybridCache[V]) WritePolicy() WritePolicy {
+	return c.writePolicy
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHybridCache_WritePolicy(t *testing.T) {
+	tests := []struct {
+		policy            WritePolicy
+		inMemory, inRedis bool
+	}{
+		{WriteThrough, true, true},
+		{WriteMemoryOnly, true, false},
+		{WriteRedisOnly, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			_, client := setupMiniRedis(t)
+			cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithWritePolicy(tt.policy)
+			if cache.WritePolicy() != tt.policy {
+				t.Errorf("Expected policy %v, got %v", tt.policy, cache.WritePolicy())
+			}
+			if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+				t.Fatalf("Set error: %v", err)
+			}
+			if got := len(cache.Memory().GetAll()) == 1; got != tt.inMemory {
+				t.Errorf("Expected memory written: %v, got %v", tt.inMemory, got)
+			}
+			remote, err := cache.Redis().Get()
+			if err != nil {
+				t.Fatalf("Get error: %v", err)
+			}
+			if got := len(remote) == 1; got != tt.inRedis {
+				t.Errorf("Expected Redis written: %v, got %v", tt.inRedis, got)
+			}
+		})
+	}
+}
+
+func TestHybridCache_WritePolicyWriteBack(t *testing.T) {
+	_, client := setupMiniRedis(t)
+	cache := NewHybridCache[TestUser](userConfig(), client, DefaultRedisConfig()).WithWritePolicy(WriteBack)
+	defer cache.Close(context.Background())
+
+	if err := cache.Set([]TestUser{{ID: "1"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	waitFor(t, func() bool {
+		remote, _ := cache.Redis().Get()
+		return idsOf(remote) == "1"
+	})
+
+	// Leaving write-back stops the worker; Set writes Redis synchronously again.
+	cache.WithWritePolicy(WriteThrough)
+	if cache.writeBehind != nil {
+		t.Error("Expected the write-behind worker to be stopped")
+	}
+	if err := cache.Set([]TestUser{{ID: "2"}}); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if remote, _ := cache.Redis().Get(); idsOf(remote) != "2" {
+		t.Errorf("Expected a synchronous write, got %s", idsOf(remote))
+	}
+
+	if cache.WithWriteBehind(WriteBehindConfig{FlushDelay: time.Hour}).WritePolicy() != WriteBack {
+		t.Error("Expected WithWriteBehind to set WriteBack")
+	}
+}